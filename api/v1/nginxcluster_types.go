@@ -17,10 +17,16 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // NginxClusterSpec defines the desired state of NginxCluster
+// +kubebuilder:validation:XValidation:rule="!(has(self.nginxConf) && has(self.nginxConfFrom))",message="nginxConf and nginxConfFrom are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="!(has(self.configTemplateFrom) && (has(self.nginxConf) || has(self.nginxConfFrom)))",message="configTemplateFrom is mutually exclusive with nginxConf and nginxConfFrom"
+// +kubebuilder:validation:XValidation:rule="has(self.targetNamespace) == has(oldSelf.targetNamespace) && (!has(self.targetNamespace) || self.targetNamespace == oldSelf.targetNamespace)",message="targetNamespace is immutable"
 type NginxClusterSpec struct {
 	// Replicas is the number of nginx instances
 	// +kubebuilder:default=1
@@ -33,6 +39,848 @@ type NginxClusterSpec struct {
 
 	// NginxConf is the nginx configuration content
 	NginxConf string `json:"nginxConf,omitempty"`
+
+	// DefaultConfigMode selects the config used when no configuration is
+	// given: StaticFiles serves DocumentRoot, ReverseProxy proxies
+	// all requests to Upstreams.
+	// +kubebuilder:validation:Enum=StaticFiles;ReverseProxy
+	// +kubebuilder:default=StaticFiles
+	// +optional
+	DefaultConfigMode string `json:"defaultConfigMode,omitempty"`
+
+	// Upstreams are the servers of the ReverseProxy default config, as
+	// host:port, e.g. the address of a Service. Required for ReverseProxy.
+	// +kubebuilder:validation:items:Pattern=`^[^\s;{}]+$`
+	// +listType=atomic
+	// +optional
+	Upstreams []string `json:"upstreams,omitempty"`
+
+	// ProxyBufferSize sets the proxy_buffer_size directive of the
+	// ReverseProxy default config, the buffer for the response headers of an
+	// upstream, e.g. 16k when large headers make nginx answer 502.
+	// +kubebuilder:validation:Pattern=`^[0-9]+[kKmM]?$`
+	// +optional
+	ProxyBufferSize string `json:"proxyBufferSize,omitempty"`
+
+	// ProxyBuffers sets the proxy_buffers directive of the ReverseProxy
+	// default config, as the number and size of the buffers of a response,
+	// e.g. "8 16k".
+	// +kubebuilder:validation:Pattern=`^[1-9][0-9]* [0-9]+[kKmM]?$`
+	// +optional
+	ProxyBuffers string `json:"proxyBuffers,omitempty"`
+
+	// ProxyBusyBuffersSize sets the proxy_busy_buffers_size directive of the
+	// ReverseProxy default config. nginx requires it to be at least
+	// ProxyBufferSize and one of ProxyBuffers, and less than all
+	// ProxyBuffers but one.
+	// +kubebuilder:validation:Pattern=`^[0-9]+[kKmM]?$`
+	// +optional
+	ProxyBusyBuffersSize string `json:"proxyBusyBuffersSize,omitempty"`
+
+	// DocumentRoot is the root of the StaticFiles default config. Defaults to
+	// /usr/share/nginx/html. ErrorPages are mounted there and are not found
+	// under another root.
+	// +kubebuilder:validation:Pattern=`^/[^\s;{}]*$`
+	// +optional
+	DocumentRoot string `json:"documentRoot,omitempty"`
+
+	// IndexFiles are the index files of the StaticFiles default config, in
+	// lookup order. Defaults to index.html and index.htm.
+	// +kubebuilder:validation:items:Pattern=`^[^\s;{}]+$`
+	// +listType=atomic
+	// +optional
+	IndexFiles []string `json:"indexFiles,omitempty"`
+
+	// NginxConfFrom reads the nginx configuration from a key of a ConfigMap in
+	// the same namespace instead of NginxConf. The ConfigMap is watched and
+	// changes roll the pods. Mutually exclusive with NginxConf.
+	// +optional
+	NginxConfFrom *corev1.ConfigMapKeySelector `json:"nginxConfFrom,omitempty"`
+
+	// ConfigTemplateFrom reads a Go text/template from a key of a ConfigMap in
+	// the same namespace. It is executed with TemplateValues and the output is
+	// used as NginxConf. The ConfigMap is watched and changes roll the pods.
+	// Mutually exclusive with NginxConf and NginxConfFrom.
+	// +optional
+	ConfigTemplateFrom *corev1.ConfigMapKeySelector `json:"configTemplateFrom,omitempty"`
+
+	// TemplateValues are the data the config template is executed with, e.g.
+	// {{ .upstream }}. Referencing a missing value fails the rendering.
+	// +optional
+	TemplateValues map[string]string `json:"templateValues,omitempty"`
+
+	// ConfigProfiles is a library of named nginx configurations, e.g. one per
+	// environment. ActiveProfile selects the one used.
+	// +optional
+	ConfigProfiles map[string]string `json:"configProfiles,omitempty"`
+
+	// ActiveProfile is the name of the entry of ConfigProfiles used as
+	// NginxConf. Switching profiles rolls the pods. Mutually exclusive with
+	// NginxConf, NginxConfFrom and ConfigTemplateFrom.
+	// +optional
+	ActiveProfile string `json:"activeProfile,omitempty"`
+
+	// RevisionHistoryLimit is the number of old ReplicaSets to retain for rollback
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// ProgressDeadlineSeconds is the time a rollout may take to make progress
+	// before the Deployment reports ProgressDeadlineExceeded
+	// +kubebuilder:default=120
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+
+	// RolloutRamp steps the minReadySeconds of the Deployment down as a
+	// rollout progresses: the first pods have to stay ready long before the
+	// next ones are replaced, the later ones less so once the early pods
+	// proved healthy
+	// +optional
+	RolloutRamp *RampSpec `json:"rolloutRamp,omitempty"`
+
+	// NetworkPolicy, when set, creates a default-deny NetworkPolicy for the nginx
+	// pods that only admits the listed ports and namespaces
+	// +optional
+	NetworkPolicy *NetworkPolicySpec `json:"networkPolicy,omitempty"`
+
+	// ConfigWritable mounts the nginx config read-write for images that need to
+	// write into the config dir. The config is mounted read-only by default.
+	// +optional
+	ConfigWritable bool `json:"configWritable,omitempty"`
+
+	// SharedServiceName, when set, puts the cluster's pods behind the named
+	// Service shared with other NginxClusters instead of a per-cluster Service.
+	// All clusters sharing a Service must expose the same ports. It cannot be
+	// the name of the cluster, which is taken by the per-cluster Service.
+	// +optional
+	SharedServiceName string `json:"sharedServiceName,omitempty"`
+
+	// InjectPodMetadataEnv adds the POD_NAME, POD_NAMESPACE, NODE_NAME and
+	// POD_IP environment variables to the nginx container via the downward API
+	// +optional
+	InjectPodMetadataEnv bool `json:"injectPodMetadataEnv,omitempty"`
+
+	// Affinity is the scheduling affinity of the nginx pods. When set, it
+	// replaces the default pod anti-affinity.
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// DefaultPodAntiAffinity spreads the pods of multi-replica clusters across
+	// nodes with a preferred anti-affinity rule when Affinity is not set. The
+	// rule is set whatever the replicas, so scaling doesn't roll the pods.
+	// +kubebuilder:default=true
+	// +optional
+	DefaultPodAntiAffinity *bool `json:"defaultPodAntiAffinity,omitempty"`
+
+	// ResourceProfile sets predefined CPU and memory requests and limits on
+	// the nginx container: small requests 100m/128Mi with limits 500m/256Mi,
+	// medium 250m/256Mi with 1/512Mi, and large 1/1Gi with 2/2Gi. Resources
+	// takes precedence. Without either, the container has no resources set.
+	// +kubebuilder:validation:Enum=small;medium;large
+	// +optional
+	ResourceProfile string `json:"resourceProfile,omitempty"`
+
+	// Resources of the nginx container, replacing those of ResourceProfile
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// EnableHTTP3 exposes UDP port 443 and merges the QUIC listen directives into
+	// the first server block of the config that sets ssl_certificate, as QUIC
+	// needs TLS. The config is left alone if none does. The image must be built
+	// with QUIC support (nginx 1.25+).
+	// +optional
+	EnableHTTP3 bool `json:"enableHTTP3,omitempty"`
+
+	// EnableScrapeAnnotations adds the prometheus.io/scrape, port and path
+	// annotations to the pods, for Prometheus setups discovering targets by
+	// annotation. The operator runs no exporter itself: MetricsPort has to be
+	// served, e.g. by an exporter sidecar added with PodTemplatePatch.
+	// +optional
+	EnableScrapeAnnotations bool `json:"enableScrapeAnnotations,omitempty"`
+
+	// MetricsPort is the port announced in the prometheus.io/port annotation.
+	// Defaults to 9113, the port of the nginx Prometheus exporter.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	MetricsPort int32 `json:"metricsPort,omitempty"`
+
+	// MetricsPath is the path announced in the prometheus.io/path annotation.
+	// Defaults to /metrics.
+	// +optional
+	MetricsPath string `json:"metricsPath,omitempty"`
+
+	// MetricsService creates a ClusterIP Service named <name>-metrics exposing
+	// only MetricsPort, as the metrics port, for a ServiceMonitor to select by
+	// its nginx.example.com/metrics label. Scrapes then don't go through the
+	// Service carrying the data-plane traffic.
+	// +optional
+	MetricsService bool `json:"metricsService,omitempty"`
+
+	// SchedulingGates are added to the pod template. Pods stay Pending until
+	// every gate has been removed by an external controller.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	SchedulingGates []corev1.PodSchedulingGate `json:"schedulingGates,omitempty"`
+
+	// ReadinessGates are added to the pod template. A pod only becomes Ready,
+	// and counts as available for the rolling update, once an external
+	// controller sets each gate's condition type to True on it, e.g. when a
+	// load balancer controller has registered it as a healthy target.
+	// +listType=map
+	// +listMapKey=conditionType
+	// +optional
+	ReadinessGates []corev1.PodReadinessGate `json:"readinessGates,omitempty"`
+
+	// TerminationMessagePolicy sets how the nginx container's termination
+	// message is populated. FallbackToLogsOnError uses the tail of the
+	// container log when the message file is empty and the container failed.
+	// +kubebuilder:validation:Enum=File;FallbackToLogsOnError
+	// +optional
+	TerminationMessagePolicy corev1.TerminationMessagePolicy `json:"terminationMessagePolicy,omitempty"`
+
+	// TerminationMessagePath is the file the nginx container's termination
+	// message is read from. Defaults to /dev/termination-log.
+	// +optional
+	TerminationMessagePath string `json:"terminationMessagePath,omitempty"`
+
+	// CacheVolume mounts an emptyDir at /var/cache/nginx. Set a size limit so
+	// a growing proxy cache is bounded instead of getting the pod evicted.
+	// +optional
+	CacheVolume *CacheVolumeSpec `json:"cacheVolume,omitempty"`
+
+	// PodTemplatePatch is a strategic merge patch applied over the generated
+	// pod template, for pod settings without a dedicated field. Containers are
+	// merged by name; the nginx container is named after ContainerName.
+	// +optional
+	PodTemplatePatch *runtime.RawExtension `json:"podTemplatePatch,omitempty"`
+
+	// TargetNamespace is the namespace the managed resources are created in.
+	// Defaults to the namespace of the NginxCluster. Resources in another
+	// namespace are tracked with management labels and removed by the
+	// finalizer, since owner references cannot cross namespaces. It can't be
+	// set, changed or removed after creation.
+	// +optional
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+
+	// AdoptExisting takes over a Deployment of the cluster's name created
+	// before it, e.g. during a migration, only once it is compatible: its
+	// selector must match the cluster's and its nginx container must mount
+	// its config under /etc/nginx. An incompatible Deployment is left alone
+	// and reported with reason AdoptionFailed in the Degraded condition.
+	// +optional
+	AdoptExisting bool `json:"adoptExisting,omitempty"`
+
+	// ManageWorkload creates the Deployment and Service running nginx. When
+	// false only the generated ConfigMap is reconciled, for a Deployment
+	// managed elsewhere, e.g. by Helm, that mounts it. A Deployment and
+	// Service created before are left in place.
+	// +kubebuilder:default=true
+	// +optional
+	ManageWorkload *bool `json:"manageWorkload,omitempty"`
+
+	// ExternalDeployment names a Deployment in the namespace of the
+	// generated ConfigMap whose pods are restarted on config changes, through
+	// the config-hash and restartedAt annotations of its pod template, when
+	// ManageWorkload is false. Its replica counts are reported in status.
+	// +optional
+	ExternalDeployment string `json:"externalDeployment,omitempty"`
+
+	// WorkerProcesses sets the worker_processes directive, either "auto" or a
+	// number of processes. It is added to the default config, or to NginxConf
+	// when that does not set worker_processes itself.
+	// +kubebuilder:validation:Pattern=`^(auto|[1-9][0-9]*)$`
+	// +optional
+	WorkerProcesses string `json:"workerProcesses,omitempty"`
+
+	// ClientMaxBodySize sets the client_max_body_size directive, the largest
+	// request body accepted before nginx answers 413, e.g. 50m for uploads.
+	// nginx defaults to 1m; 0 disables the check. It is added to the http
+	// block unless the config sets client_max_body_size anywhere itself.
+	// +kubebuilder:validation:Pattern=`^[0-9]+[kKmMgG]?$`
+	// +optional
+	ClientMaxBodySize string `json:"clientMaxBodySize,omitempty"`
+
+	// WorkerRlimitNofile sets the worker_rlimit_nofile directive, the file
+	// descriptor limit of the worker processes. Like WorkerProcesses, it is
+	// only added when the config does not set it itself. The master process
+	// raises the limit before dropping privileges, so the container needs no
+	// extra settings when nginx starts as root.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	WorkerRlimitNofile int32 `json:"workerRlimitNofile,omitempty"`
+
+	// TotalConnections is the number of connections the cluster should
+	// handle as a whole. Its share per replica, TotalConnections / Replicas,
+	// sets worker_connections in the default config and is passed to config
+	// templates as .WorkerConnections, so scaling rolls the pods with a new
+	// share. The share must be at least MinWorkerConnections. It is ignored
+	// with other configs.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TotalConnections int32 `json:"totalConnections,omitempty"`
+
+	// ListenAddresses are the IP addresses the server of the default config
+	// listens on, port 80 each, instead of all addresses, e.g. specific node
+	// IPs when the pods run in the host network. Ignored with other configs.
+	// +listType=set
+	// +optional
+	ListenAddresses []string `json:"listenAddresses,omitempty"`
+
+	// Gzip adds gzip compression directives to the http block of the default
+	// config. Ignored with other configs.
+	// +optional
+	Gzip *GzipSpec `json:"gzip,omitempty"`
+
+	// RateLimit limits the request rate per client address in the server
+	// block of the default config. Ignored with other configs.
+	// +optional
+	RateLimit *RateLimitSpec `json:"rateLimit,omitempty"`
+
+	// LogFormat defines the access log format of the default config, e.g. the
+	// json preset for structured logging pipelines, and logs with it to
+	// /var/log/nginx/access.log. Ignored with other configs. nginx has no
+	// format for the error log.
+	// +optional
+	LogFormat *LogFormatSpec `json:"logFormat,omitempty"`
+
+	// LogSampling logs only a share of the requests to the access log. The
+	// generated config picks the requests with split_clients and adds an if=
+	// condition to its access_log directives.
+	// +optional
+	LogSampling *LogSamplingSpec `json:"logSampling,omitempty"`
+
+	// ErrorPages maps HTTP status codes from 300 to 599 to the HTML served
+	// for them, e.g. a branded 404 page. The pages are kept in a
+	// <name>-error-pages ConfigMap, mounted as /usr/share/nginx/html/<code>.html,
+	// and error_page directives for them are merged into the first server
+	// block of the generated config. Changing a page rolls the pods.
+	// +optional
+	ErrorPages map[string]string `json:"errorPages,omitempty"`
+
+	// NjsScripts maps file names such as main.js to njs scripts. They are
+	// kept in a <name>-njs-scripts ConfigMap mounted at /etc/nginx/njs, and
+	// the generated config loads the njs module and imports each script
+	// under its name without .js, e.g. js_import main from main.js, so
+	// js_content main.handler can use it. Changing a script rolls the pods.
+	// +optional
+	NjsScripts map[string]string `json:"njsScripts,omitempty"`
+
+	// BasicAuth protects the generated config with HTTP basic authentication
+	// against an htpasswd file kept in a Secret. The Secret is mounted at
+	// /etc/nginx/auth, and auth_basic and auth_basic_user_file directives are
+	// added to the http block unless the config sets auth_basic itself. The
+	// rollout is held while the Secret doesn't exist.
+	// +optional
+	BasicAuth *BasicAuthSpec `json:"basicAuth,omitempty"`
+
+	// ServiceLabels are added to the metadata of the per-cluster Service only,
+	// not to its selector or the pods
+	// +optional
+	ServiceLabels map[string]string `json:"serviceLabels,omitempty"`
+
+	// PodLabels are added to the pod template only, not to the Deployment
+	// selector, which is immutable, so they can be changed at any time. They
+	// cannot override the app, cluster and shared Service labels.
+	// +optional
+	PodLabels map[string]string `json:"podLabels,omitempty"`
+
+	// ServiceType of the per-cluster Service. When unset, the Service is
+	// created as ClusterIP and a type changed on it afterwards is kept.
+	// ExternalName turns the cluster into a placeholder resolving to
+	// spec.externalName, e.g. while traffic moves to or from an endpoint
+	// outside the cluster; its Deployment is scaled to zero.
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer;ExternalName
+	// +optional
+	ServiceType corev1.ServiceType `json:"serviceType,omitempty"`
+
+	// ExternalName is the DNS name the Service resolves to with serviceType
+	// ExternalName
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	ExternalName string `json:"externalName,omitempty"`
+
+	// LoadBalancerClass selects the load balancer implementation of a
+	// LoadBalancer Service, e.g. on clusters running MetalLB next to the cloud
+	// provider's. The class of a load balancer can't be changed, so changing
+	// it recreates the Service, which gets a new address.
+	// +optional
+	LoadBalancerClass *string `json:"loadBalancerClass,omitempty"`
+
+	// IPFamilyPolicy of the per-cluster Service, e.g. SingleStack to keep a
+	// Service on a dual-stack cluster on one family. Left to the cluster's
+	// default when unset.
+	// +kubebuilder:validation:Enum=SingleStack;PreferDualStack;RequireDualStack
+	// +optional
+	IPFamilyPolicy *corev1.IPFamilyPolicy `json:"ipFamilyPolicy,omitempty"`
+
+	// IPFamilies of the per-cluster Service, primary family first. The
+	// primary family cannot be changed once the Service exists.
+	// +kubebuilder:validation:MaxItems=2
+	// +kubebuilder:validation:items:Enum=IPv4;IPv6
+	// +listType=atomic
+	// +optional
+	IPFamilies []corev1.IPFamily `json:"ipFamilies,omitempty"`
+
+	// ConfigReloaderSidecar applies config changes without restarting the
+	// pods: the config directory is mounted instead of nginx.conf alone, so
+	// ConfigMap updates reach the pods, and a sidecar running the nginx image
+	// signals nginx to reload when the config changes. nginx is started with
+	// -c /etc/nginx/operator/nginx.conf, so relative include paths resolve
+	// against that directory. Changes to error pages and config dependencies
+	// still roll the pods.
+	// +optional
+	ConfigReloaderSidecar bool `json:"configReloaderSidecar,omitempty"`
+
+	// ValidationSidecar adds a sidecar running the nginx image that checks
+	// the mounted config with nginx -t every 30 seconds and serves the result
+	// on port 8087 of the pod, which must not be used otherwise. The operator
+	// fetches it in every reconcile, at least once a minute, and reports it in
+	// the ConfigValid condition, so a config broken after its rollout, e.g.
+	// by a changed dependency, shows before nginx loads it. The operator must
+	// run in the cluster network to reach the pods. The check doesn't affect
+	// the readiness of the pods.
+	// +optional
+	ValidationSidecar bool `json:"validationSidecar,omitempty"`
+
+	// RestartOnConfigChange rolls the pods when nginx.conf changes. When
+	// false the ConfigMap is still updated, and the config directory is
+	// mounted as with ConfigReloaderSidecar so the update reaches the pods,
+	// but applying it, e.g. with nginx -s reload, is left to the user.
+	// +kubebuilder:default=true
+	// +optional
+	RestartOnConfigChange *bool `json:"restartOnConfigChange,omitempty"`
+
+	// ImmutableConfig marks the generated ConfigMap immutable, so kubelets
+	// stop watching it, for clusters whose config rarely changes. A config
+	// change then replaces the ConfigMap and rolls the pods. Cannot be
+	// combined with configReloaderSidecar, which relies on ConfigMap updates
+	// reaching the pods.
+	// +optional
+	ImmutableConfig bool `json:"immutableConfig,omitempty"`
+
+	// ConfigValidationMode selects what happens to an nginx config failing
+	// the operator's structural check, e.g. an unbalanced block. Strict holds
+	// the rollout back and sets the Degraded condition, so the pods keep the
+	// last config rolled out; Warn emits a warning event and rolls it out;
+	// Off skips the check.
+	// +kubebuilder:validation:Enum=Strict;Warn;Off
+	// +kubebuilder:default=Strict
+	// +optional
+	ConfigValidationMode string `json:"configValidationMode,omitempty"`
+
+	// DisableFinalizer keeps the operator from adding its finalizer, for
+	// tooling that handles cleanup itself. Deleting the cluster is then left
+	// to owner-reference garbage collection: pods are not drained first, the
+	// release of LoadBalancer addresses is not awaited, and resources in a
+	// target namespace, tied by labels only, are left behind. A finalizer
+	// added before is removed.
+	// +optional
+	DisableFinalizer bool `json:"disableFinalizer,omitempty"`
+
+	// HoldRollout pauses the Deployment. Pod template changes are recorded but
+	// only rolled out once the hold is cleared.
+	// +optional
+	HoldRollout bool `json:"holdRollout,omitempty"`
+
+	// ConfigDependencies are Secrets and ConfigMaps in the workload namespace,
+	// such as mounted TLS certificates, whose content is folded into the config
+	// hash. Changing them rolls the pods like a config change.
+	// +listType=atomic
+	// +optional
+	ConfigDependencies []ObjectRef `json:"configDependencies,omitempty"`
+
+	// WorkingDir is the working directory of the nginx container, for images
+	// that resolve relative includes against it. Defaults to the image's.
+	// +optional
+	WorkingDir string `json:"workingDir,omitempty"`
+
+	// RestartSchedule is a cron expression, evaluated in UTC, on which the pods
+	// are restarted with a rolling update, e.g. "0 3 * * *" for nightly
+	// restarts. Missed restarts are caught up once, not per missed run.
+	// +optional
+	RestartSchedule string `json:"restartSchedule,omitempty"`
+
+	// DisableRestartTimestamp stops config changes from writing the
+	// restartedAt pod template annotation, which shows up as a perpetual diff
+	// in GitOps tools. The config-hash annotation still rolls the pods.
+	// Scheduled restarts keep using restartedAt.
+	// +optional
+	DisableRestartTimestamp bool `json:"disableRestartTimestamp,omitempty"`
+
+	// ContainerName is the name of the nginx container, for policies and
+	// sidecar injectors that key off the primary container name
+	// +kubebuilder:default=nginx
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +optional
+	ContainerName string `json:"containerName,omitempty"`
+
+	// Route exposes the Service through an OpenShift Route. Ignored on clusters
+	// without the route.openshift.io API.
+	// +optional
+	Route *RouteSpec `json:"route,omitempty"`
+
+	// HTTPRoute attaches the Service to a Gateway through a Gateway API
+	// HTTPRoute. Ignored on clusters without the gateway.networking.k8s.io API.
+	// +optional
+	HTTPRoute *HTTPRouteSpec `json:"httpRoute,omitempty"`
+
+	// RollbackToRevision pins the Deployment to the pod template of an earlier
+	// revision listed in status.revisions. While set, spec changes to the pod
+	// template are not rolled out; clearing it returns to the template derived
+	// from the spec.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RollbackToRevision *int64 `json:"rollbackToRevision,omitempty"`
+
+	// ShutdownDrainSeconds is how long nginx may drain connections on
+	// shutdown. A preStop hook runs "nginx -s quit" and waits that long, and
+	// the termination grace period is set to the drain time plus
+	// ShutdownDrainBufferSeconds.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ShutdownDrainSeconds int32 `json:"shutdownDrainSeconds,omitempty"`
+
+	// VPA creates a VerticalPodAutoscaler for the Deployment. Ignored on
+	// clusters without the autoscaling.k8s.io API.
+	// +optional
+	VPA *VPASpec `json:"vpa,omitempty"`
+
+	// HealthCheck adds readiness and liveness probes to the nginx container
+	// +optional
+	HealthCheck *HealthCheckSpec `json:"healthCheck,omitempty"`
+
+	// EnableHealthEndpoint adds a server answering GET /healthz with 200, on
+	// the internal port 8086 and without access logs, to the http block of
+	// the config. HTTP probes request it instead of healthCheck.path, so
+	// health checks don't show up in the logs of the main server. Probes are
+	// added even without HealthCheck.
+	// +optional
+	EnableHealthEndpoint bool `json:"enableHealthEndpoint,omitempty"`
+
+	// FSGroup is the supplemental group owning the pod's volumes, so a
+	// non-root nginx can share an emptyDir with a sidecar
+	// +optional
+	FSGroup *int64 `json:"fsGroup,omitempty"`
+
+	// RunAsUser is the uid the nginx container runs as, for images built to
+	// run nginx without root. The image's default when unset.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RunAsUser *int64 `json:"runAsUser,omitempty"`
+
+	// RunAsGroup is the primary gid of the nginx container. The image's
+	// default when unset.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RunAsGroup *int64 `json:"runAsGroup,omitempty"`
+
+	// ReadOnlyRootFilesystem makes the root filesystem of the nginx container
+	// read-only. emptyDirs are mounted at /tmp and /var/run, and the pid file
+	// and the temp paths of the generated config are pointed there unless the
+	// config sets them itself. A config from NginxConfFrom is used as is, so
+	// it has to use writable paths.
+	// +optional
+	ReadOnlyRootFilesystem bool `json:"readOnlyRootFilesystem,omitempty"`
+
+	// Sysctls are namespaced kernel parameters set for the pod, such as
+	// net.core.somaxconn. Sysctls outside the Kubernetes safe set must be
+	// allowed on the kubelet with --allowed-unsafe-sysctls.
+	// +optional
+	Sysctls []corev1.Sysctl `json:"sysctls,omitempty"`
+}
+
+// ShutdownDrainBufferSeconds is added to spec.shutdownDrainSeconds for the
+// termination grace period, so nginx is not killed while the preStop hook
+// finishes
+const ShutdownDrainBufferSeconds = 5
+
+// MinWorkerConnections is the smallest worker_connections share per replica
+// spec.totalConnections may result in
+const MinWorkerConnections = 64
+
+// LogSamplingSpec configures access log sampling
+type LogSamplingSpec struct {
+	// Rate is the share of requests written to the access log, between 0 and
+	// 1, e.g. "0.1" for one request in ten. nginx splits clients with a
+	// precision of 0.01%.
+	// +kubebuilder:validation:Pattern=`^(0(\.[0-9]+)?|1(\.0+)?)$`
+	Rate string `json:"rate"`
+}
+
+// LogFormatSpec configures the access log format of the default config.
+// Exactly one of Format and Preset is set.
+type LogFormatSpec struct {
+	// Name of the log_format. combined is predefined by nginx.
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_]*$`
+	// +kubebuilder:default=main
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Format is the log_format string, with nginx variables such as
+	// $remote_addr
+	// +optional
+	Format string `json:"format,omitempty"`
+
+	// Preset selects a predefined format instead of Format: json logs one
+	// JSON object per request, with the client address, request, status,
+	// sizes, timings, upstream and request ID
+	// +kubebuilder:validation:Enum=json
+	// +optional
+	Preset string `json:"preset,omitempty"`
+
+	// Escape is the escaping of variables in Format: default, json or none.
+	// The json preset always uses json.
+	// +kubebuilder:validation:Enum=default;json;none
+	// +optional
+	Escape string `json:"escape,omitempty"`
+}
+
+// GzipSpec configures gzip compression in the default config
+type GzipSpec struct {
+	// Enabled turns gzip on
+	// +kubebuilder:default=true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Types are the MIME types compressed besides text/html, which always
+	// is. Defaults to common text types: CSS, JavaScript, JSON, XML, SVG and
+	// plain text.
+	// +kubebuilder:validation:items:Pattern=`^[^\s;{}]+$`
+	// +listType=set
+	// +optional
+	Types []string `json:"types,omitempty"`
+
+	// MinLength is the minimum response length in bytes, by Content-Length,
+	// to compress. nginx defaults to 20.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinLength int32 `json:"minLength,omitempty"`
+
+	// Level is the compression level from 1, fastest, to 9, smallest. nginx
+	// defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=9
+	// +optional
+	Level int32 `json:"level,omitempty"`
+}
+
+// RateLimitSpec configures request rate limiting in the default config
+type RateLimitSpec struct {
+	// Rate is the sustained rate of requests per client address, per second
+	// or minute, e.g. 10r/s. Requests beyond it are answered with 429.
+	// +kubebuilder:validation:Pattern=`^[1-9][0-9]*r/[sm]$`
+	Rate string `json:"rate"`
+
+	// Burst is the number of requests beyond Rate that are queued, and
+	// delayed to keep to the rate, instead of rejected
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Burst int32 `json:"burst,omitempty"`
+
+	// ZoneSize is the shared memory of the limit_req_zone holding the client
+	// states, about 16000 addresses per megabyte
+	// +kubebuilder:validation:Pattern=`^[0-9]+[kKmM]?$`
+	// +kubebuilder:default="10m"
+	// +optional
+	ZoneSize string `json:"zoneSize,omitempty"`
+}
+
+// BasicAuthSpec configures HTTP basic authentication
+type BasicAuthSpec struct {
+	// SecretName of a Secret in the workload namespace whose htpasswd key
+	// holds the user file, e.g. created with htpasswd -c. nginx reads the
+	// file on each request, so user changes apply without a restart once
+	// the kubelet has updated the mounted Secret.
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
+
+	// Realm is shown in the login prompt of browsers
+	// +kubebuilder:validation:Pattern=`^[^"\\]*$`
+	// +kubebuilder:default=Restricted
+	// +optional
+	Realm string `json:"realm,omitempty"`
+}
+
+// RampSpec configures a stepped minReadySeconds progression over a rollout
+type RampSpec struct {
+	// Steps are ordered by UpdatedPercent, which must increase. A rollout
+	// starts with the first step; each further step applies once its share of
+	// the replicas runs the new revision and is available.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=atomic
+	Steps []RampStep `json:"steps"`
+}
+
+// RampStep is the minReadySeconds of a stage of the rollout
+type RampStep struct {
+	// UpdatedPercent is the share of available replicas of the new revision
+	// from which the step applies
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	UpdatedPercent int32 `json:"updatedPercent"`
+
+	// MinReadySeconds is how long new pods have to be ready before they
+	// count as available, and the rollout moves on
+	// +kubebuilder:validation:Minimum=0
+	MinReadySeconds int32 `json:"minReadySeconds"`
+}
+
+// RouteSpec configures the generated OpenShift Route
+type RouteSpec struct {
+	// Host is the public host name. The router generates one when empty.
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// TLSTermination enables TLS on the Route. Passthrough and reencrypt need
+	// nginx to serve TLS itself. Plain HTTP when empty.
+	// +kubebuilder:validation:Enum=edge;passthrough;reencrypt
+	// +optional
+	TLSTermination string `json:"tlsTermination,omitempty"`
+}
+
+// HTTPRouteSpec configures the generated Gateway API HTTPRoute
+type HTTPRouteSpec struct {
+	// ParentRef is the Gateway the route attaches to
+	ParentRef GatewayReference `json:"parentRef"`
+
+	// Hostnames are the host names the route matches. All host names of the
+	// Gateway listener when empty.
+	// +listType=atomic
+	// +optional
+	Hostnames []string `json:"hostnames,omitempty"`
+
+	// Paths are the request paths routed to nginx. All paths when empty.
+	// +listType=atomic
+	// +optional
+	Paths []HTTPRoutePath `json:"paths,omitempty"`
+}
+
+// GatewayReference identifies a Gateway
+type GatewayReference struct {
+	// Name of the Gateway
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace of the Gateway, the namespace of the HTTPRoute by default.
+	// The Gateway listener has to allow routes from that namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// SectionName selects a listener of the Gateway. All listeners when empty.
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+// HTTPRoutePath matches request paths
+type HTTPRoutePath struct {
+	// Type is how Value is matched
+	// +kubebuilder:validation:Enum=Exact;PathPrefix
+	// +kubebuilder:default=PathPrefix
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Value is the path, starting with a slash
+	// +kubebuilder:validation:Pattern=`^/`
+	Value string `json:"value"`
+}
+
+// HealthCheckSpec configures the probes of the nginx container
+type HealthCheckSpec struct {
+	// Type selects the probe. HTTP requests Path on the http port; GRPC calls
+	// the gRPC health checking protocol on Port, which needs Kubernetes 1.24
+	// and falls back to a TCP check on older clusters.
+	// +kubebuilder:validation:Enum=HTTP;GRPC
+	// +kubebuilder:default=HTTP
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Path is the HTTP path to probe. Defaults to "/".
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Port serving the gRPC health service, exposed as the grpc-health
+	// container port. Required for GRPC.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// Service is the service name sent in gRPC health checks. When empty, the
+	// health of the server as a whole is checked.
+	// +optional
+	Service string `json:"service,omitempty"`
+
+	// LivenessType selects a different liveness probe than Type. Exec checks
+	// that the nginx master process recorded in PIDFile is running, which
+	// catches a dead master whose workers still answer requests. Defaults to
+	// Type.
+	// +kubebuilder:validation:Enum=HTTP;GRPC;Exec
+	// +optional
+	LivenessType string `json:"livenessType,omitempty"`
+
+	// PIDFile is the pid file of the nginx master checked by Exec liveness
+	// probes. Defaults to /var/run/nginx.pid, the path of the official image.
+	// +optional
+	PIDFile string `json:"pidFile,omitempty"`
+}
+
+// VPASpec configures the generated VerticalPodAutoscaler
+type VPASpec struct {
+	// UpdateMode is the VPA update mode. Off only publishes recommendations
+	// in the VPA status; the other modes apply them to the pods, in which
+	// case the operator leaves pod resources to the VPA.
+	// +kubebuilder:validation:Enum=Off;Initial;Recreate;Auto
+	// +kubebuilder:default=Off
+	// +optional
+	UpdateMode string `json:"updateMode,omitempty"`
+
+	// ResourcePolicy is copied verbatim to spec.resourcePolicy of the VPA,
+	// e.g. to bound the recommendations per container
+	// +optional
+	ResourcePolicy *runtime.RawExtension `json:"resourcePolicy,omitempty"`
+}
+
+// ObjectRef refers to a Secret or ConfigMap in the workload namespace
+type ObjectRef struct {
+	// Kind of the referenced object
+	// +kubebuilder:validation:Enum=Secret;ConfigMap
+	Kind string `json:"kind"`
+
+	// Name of the referenced object
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// CacheVolumeSpec configures the emptyDir backing the nginx cache directory
+type CacheVolumeSpec struct {
+	// SizeLimit is the maximum amount of local storage the cache may use
+	// +optional
+	SizeLimit *resource.Quantity `json:"sizeLimit,omitempty"`
+
+	// Medium is the storage medium backing the volume. Memory uses tmpfs,
+	// which counts against the container memory limit.
+	// +kubebuilder:validation:Enum="";Memory
+	// +optional
+	Medium corev1.StorageMedium `json:"medium,omitempty"`
+}
+
+// NetworkPolicySpec defines the ingress allowed by the generated NetworkPolicy
+type NetworkPolicySpec struct {
+	// AllowedIngressPorts are the TCP ports that accept ingress traffic.
+	// When empty, all ports are allowed for the selected namespaces.
+	// +optional
+	AllowedIngressPorts []int32 `json:"allowedIngressPorts,omitempty"`
+
+	// AllowedNamespaceSelectors select the namespaces that may reach the pods.
+	// When empty, traffic on the allowed ports is accepted from any source.
+	// When both lists are empty, all ingress is denied.
+	// +optional
+	AllowedNamespaceSelectors []metav1.LabelSelector `json:"allowedNamespaceSelectors,omitempty"`
 }
 
 // NginxClusterStatus defines the observed state of NginxCluster
@@ -40,22 +888,214 @@ type NginxClusterStatus struct {
 	// Replicas is the current number of replicas
 	Replicas int32 `json:"replicas,omitempty"`
 
+	// ObservedGeneration is the generation of the spec last fully reconciled
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// ReadyReplicas is the number of ready replicas
 	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
 
-	// ConfigHash is the hash of current nginx config
+	// UpdatedReplicas is the number of replicas running the current pod
+	// template
+	UpdatedReplicas int32 `json:"updatedReplicas,omitempty"`
+
+	// AvailableReplicas is the number of replicas that have been ready for at
+	// least minReadySeconds
+	AvailableReplicas int32 `json:"availableReplicas,omitempty"`
+
+	// ConfigHash is the hash of current nginx config, as recorded on the pod
+	// template. With the config reloader sidecar it leaves nginx.conf out, as
+	// changes to it don't restart the pods.
 	ConfigHash string `json:"configHash,omitempty"`
 
+	// ConfigMapResourceVersion is the resource version of the ConfigMap holding
+	// the nginx config as last seen by the operator
+	ConfigMapResourceVersion string `json:"configMapResourceVersion,omitempty"`
+
+	// EffectiveConfigConfigMap names the ConfigMap, in the cluster's namespace,
+	// whose nginx.conf key holds the configuration the pods are running.
+	// Generated ConfigMaps carry the nginx.example.com/effective-config label.
+	EffectiveConfigConfigMap string `json:"effectiveConfigConfigMap,omitempty"`
+
 	// LastUpdateTime is the timestamp of last configuration update
 	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+
+	// OldestPodAge is the age of the oldest nginx pod at LastUpdateTime. A
+	// large gap to NewestPodAge after a rollout points at pods that did not roll.
+	OldestPodAge *metav1.Duration `json:"oldestPodAge,omitempty"`
+
+	// NewestPodAge is the age of the newest nginx pod at LastUpdateTime
+	NewestPodAge *metav1.Duration `json:"newestPodAge,omitempty"`
+
+	// LastScheduledRestartTime is when the pods were last restarted by the
+	// restart schedule, or when the schedule was first observed. The next
+	// restart is counted from it.
+	LastScheduledRestartTime *metav1.Time `json:"lastScheduledRestartTime,omitempty"`
+
+	// Conditions describe the latest observed state of the cluster
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Revisions are the rollback points kept by the Deployment, newest first
+	Revisions []RevisionStatus `json:"revisions,omitempty"`
+
+	// Rollback is the revision the Deployment is pinned to by
+	// spec.rollbackToRevision
+	Rollback *RollbackStatus `json:"rollback,omitempty"`
+
+	// ConfigInSync is true when every running nginx pod was started with the
+	// config the spec currently resolves to, i.e. ConfigHash
+	ConfigInSync bool `json:"configInSync,omitempty"`
+
+	// RolloutState tracks the rollout of the latest config across
+	// reconciles, so it survives operator restarts
+	RolloutState *RolloutState `json:"rolloutState,omitempty"`
+
+	// LastRolloutDuration is how long the last completed rollout took, from
+	// the config change until all replicas ran the new config
+	LastRolloutDuration *metav1.Duration `json:"lastRolloutDuration,omitempty"`
+
+	// AccessURL is where nginx can be reached from outside the cluster: the
+	// Route host, the load balancer address or a node port of the Service.
+	// Empty for ClusterIP Services without a Route.
+	AccessURL string `json:"accessURL,omitempty"`
+
+	// ServiceReachable is true when nginx answered the last reachability
+	// check through the Service, see the ServiceReachable condition
+	ServiceReachable bool `json:"serviceReachable,omitempty"`
+
+	// ConfigOnly is true while spec.manageWorkload is false and the operator
+	// only distributes the config
+	ConfigOnly bool `json:"configOnly,omitempty"`
+
+	// ConfigValid is true when nginx -t passed in the validation sidecars of
+	// all pods at the last check, see the ConfigValid condition
+	ConfigValid bool `json:"configValid,omitempty"`
+}
+
+// RevisionStatus describes a ReplicaSet the Deployment can be rolled back to
+type RevisionStatus struct {
+	// Revision is the Deployment revision of the ReplicaSet
+	Revision int64 `json:"revision"`
+
+	// ReplicaSet is the name of the ReplicaSet
+	ReplicaSet string `json:"replicaSet"`
+
+	// ConfigHash is the hash of the nginx config the revision runs
+	ConfigHash string `json:"configHash,omitempty"`
+}
+
+// RollbackStatus records an active rollback. Rolling back gives the
+// ReplicaSet a new revision, so it is tracked by name.
+type RollbackStatus struct {
+	// Revision is the revision requested in spec.rollbackToRevision
+	Revision int64 `json:"revision"`
+
+	// ReplicaSet is the ReplicaSet that revision was found in
+	ReplicaSet string `json:"replicaSet"`
+}
+
+// RolloutState describes the rollout of a config to the pods
+type RolloutState struct {
+	// Phase is Propagating while the pods wait for the updated ConfigMap to
+	// reach the kubelets, RollingOut while they are replaced and Complete
+	// once all of them run the config
+	// +kubebuilder:validation:Enum=Propagating;RollingOut;Complete
+	Phase string `json:"phase"`
+
+	// TargetHash is the config hash being rolled out
+	TargetHash string `json:"targetHash"`
+
+	// PreviousHash is the target of the rollout before, empty for the first
+	// rollout the operator saw
+	// +optional
+	PreviousHash string `json:"previousHash,omitempty"`
+
+	// StartTime is when the operator first saw TargetHash
+	StartTime metav1.Time `json:"startTime"`
+
+	// CompletionTime is when all pods ran TargetHash
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// RampStep is the index of the step of spec.rolloutRamp the rollout
+	// reached. It only moves forward until the rollout completes.
+	// +optional
+	RampStep int32 `json:"rampStep,omitempty"`
 }
 
+// Rollout phases reported in RolloutState
+const (
+	RolloutPhasePropagating = "Propagating"
+	RolloutPhaseRollingOut  = "RollingOut"
+	RolloutPhaseComplete    = "Complete"
+)
+
+// Values of spec.configValidationMode
+const (
+	ConfigValidationStrict = "Strict"
+	ConfigValidationWarn   = "Warn"
+	ConfigValidationOff    = "Off"
+)
+
+// Condition types reported in NginxClusterStatus
+const (
+	// ConditionRolloutPaused is true while the Deployment is paused, by
+	// spec.holdRollout or the rollout circuit breaker
+	ConditionRolloutPaused = "RolloutPaused"
+
+	// ConditionProgressing is true while a rollout of the Deployment is in
+	// progress
+	ConditionProgressing = "Progressing"
+
+	// ConditionDegraded is true when the cluster cannot reach its desired
+	// state, e.g. because the namespace ResourceQuota is too small for the
+	// requested replicas or the image cannot be pulled
+	ConditionDegraded = "Degraded"
+
+	// ConditionRolloutCircuitOpen is true while a rollout is paused because its
+	// new pods keep restarting
+	ConditionRolloutCircuitOpen = "RolloutCircuitOpen"
+
+	// ConditionConfigDrift is true while some pods run another config than
+	// the spec resolves to, e.g. until a pending restart has replaced them
+	ConditionConfigDrift = "ConfigDrift"
+
+	// ConditionWaitingForRolloutSlot is true while a config rollout is held
+	// back because the operator already rolls out its maximum number of
+	// clusters
+	ConditionWaitingForRolloutSlot = "WaitingForRolloutSlot"
+
+	// ConditionServiceReachable is true when nginx answers requests sent
+	// through the cluster IP of the Service, with the operator's
+	// --service-reachability-check. Unknown when the operator can't tell,
+	// e.g. as it runs outside the cluster network.
+	ConditionServiceReachable = "ServiceReachable"
+
+	// ConditionSelectorOverlap is true while pods not run by the cluster's
+	// Deployment, e.g. those of another workload with the same labels, match
+	// the selector of its Service and receive part of its traffic
+	ConditionSelectorOverlap = "SelectorOverlap"
+
+	// ConditionConfigValid reports the nginx -t results of the validation
+	// sidecars with spec.validationSidecar. Unknown when the operator can't
+	// fetch them, e.g. as it runs outside the cluster network.
+	ConditionConfigValid = "ConfigValid"
+
+	// ConditionReady is false with reason InvalidSpec while the operator
+	// rejects the spec, e.g. as the validating webhook is not deployed. It is
+	// removed once a valid spec is reconciled.
+	ConditionReady = "Ready"
+)
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="Replicas",type=integer,JSONPath=`.spec.replicas`
 //+kubebuilder:printcolumn:name="Ready",type=integer,JSONPath=`.status.readyReplicas`
 //+kubebuilder:printcolumn:name="Image",type=string,JSONPath=`.spec.image`
+//+kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.status.accessURL`
 //+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+//+kubebuilder:validation:XValidation:rule="!has(self.spec) || !has(self.spec.sharedServiceName) || self.spec.sharedServiceName != self.metadata.name",message="sharedServiceName cannot be the name of the cluster"
 
 // NginxCluster is the Schema for the nginxclusters API
 type NginxCluster struct {
@@ -0,0 +1,338 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/example/nginx-operator/internal/cron"
+)
+
+// njsScriptNamePattern matches the file names accepted in spec.njsScripts.
+// The name without .js is the name the module is imported as, so it must be
+// a JavaScript identifier.
+var njsScriptNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*\.js$`)
+
+// gzipTypePattern matches a single gzip_types argument, as the CRD pattern
+// of spec.gzip.types does
+var gzipTypePattern = regexp.MustCompile(`^[^\s;{}]+$`)
+
+// The rules below are shared by the validating webhook and the controller,
+// which applies them itself when the webhook is not deployed.
+
+// ValidateSpec returns the errors of all rules the CRD schema can't express
+func ValidateSpec(m *NginxCluster) field.ErrorList {
+	spec := &m.Spec
+	errs := ValidateConfigSource(spec)
+	errs = append(errs, ValidateDefaultConfig(spec)...)
+	errs = append(errs, ValidateActiveProfile(spec)...)
+	errs = append(errs, ValidatePodTemplatePatch(spec)...)
+	errs = append(errs, ValidateRestartSchedule(spec)...)
+	errs = append(errs, ValidateHealthCheck(spec)...)
+	errs = append(errs, ValidateIPFamilies(spec)...)
+	errs = append(errs, ValidateErrorPages(spec)...)
+	errs = append(errs, ValidateNjsScripts(spec)...)
+	errs = append(errs, ValidateLogFormat(spec)...)
+	errs = append(errs, ValidateRolloutRamp(spec)...)
+	errs = append(errs, ValidateSharedServiceName(spec, m.Name)...)
+	errs = append(errs, ValidateExternalDeployment(spec)...)
+	errs = append(errs, ValidateListenAddresses(spec)...)
+	errs = append(errs, ValidateGzip(spec)...)
+	errs = append(errs, ValidateTotalConnections(spec)...)
+	errs = append(errs, ValidateImmutableConfig(spec)...)
+	return append(errs, ValidateServiceType(spec)...)
+}
+
+// ValidateConfigSource checks that the config comes from one source, and
+// that ConfigMap references name both the ConfigMap and the key
+func ValidateConfigSource(spec *NginxClusterSpec) field.ErrorList {
+	var errs field.ErrorList
+	if spec.NginxConf != "" && spec.NginxConfFrom != nil {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "nginxConfFrom"), spec.NginxConfFrom.Name, "mutually exclusive with nginxConf"))
+	}
+	if spec.ConfigTemplateFrom != nil && (spec.NginxConf != "" || spec.NginxConfFrom != nil) {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "configTemplateFrom"), spec.ConfigTemplateFrom.Name, "mutually exclusive with nginxConf and nginxConfFrom"))
+	}
+	for _, ref := range []struct {
+		name string
+		sel  *corev1.ConfigMapKeySelector
+	}{{"nginxConfFrom", spec.NginxConfFrom}, {"configTemplateFrom", spec.ConfigTemplateFrom}} {
+		if ref.sel == nil {
+			continue
+		}
+		path := field.NewPath("spec", ref.name)
+		if ref.sel.Name == "" {
+			errs = append(errs, field.Required(path.Child("name"), "the ConfigMap is required"))
+		}
+		if ref.sel.Key == "" {
+			errs = append(errs, field.Required(path.Child("key"), "the key is required"))
+		}
+	}
+	return errs
+}
+
+// ValidateDefaultConfig checks that the reverse proxy default config has
+// servers to proxy to
+func ValidateDefaultConfig(spec *NginxClusterSpec) field.ErrorList {
+	if spec.DefaultConfigMode == "ReverseProxy" && len(spec.Upstreams) == 0 {
+		return field.ErrorList{field.Required(field.NewPath("spec", "upstreams"), "required for defaultConfigMode ReverseProxy")}
+	}
+	return nil
+}
+
+// ValidateActiveProfile checks that the active profile exists and is the
+// only config source
+func ValidateActiveProfile(spec *NginxClusterSpec) field.ErrorList {
+	profile := spec.ActiveProfile
+	if profile == "" {
+		return nil
+	}
+	path := field.NewPath("spec", "activeProfile")
+	if _, ok := spec.ConfigProfiles[profile]; !ok {
+		return field.ErrorList{field.NotFound(path, profile)}
+	}
+	if spec.NginxConf != "" || spec.NginxConfFrom != nil || spec.ConfigTemplateFrom != nil {
+		return field.ErrorList{field.Invalid(path, profile, "mutually exclusive with nginxConf, nginxConfFrom and configTemplateFrom")}
+	}
+	return nil
+}
+
+// ValidatePodTemplatePatch checks that the patch applies cleanly to a pod
+// template and produces a valid one
+func ValidatePodTemplatePatch(spec *NginxClusterSpec) field.ErrorList {
+	patch := spec.PodTemplatePatch
+	if patch == nil || len(patch.Raw) == 0 {
+		return nil
+	}
+	path := field.NewPath("spec", "podTemplatePatch")
+	patched, err := strategicpatch.StrategicMergePatch([]byte("{}"), patch.Raw, corev1.PodTemplateSpec{})
+	if err != nil {
+		return field.ErrorList{field.Invalid(path, string(patch.Raw), err.Error())}
+	}
+	if err := json.Unmarshal(patched, &corev1.PodTemplateSpec{}); err != nil {
+		return field.ErrorList{field.Invalid(path, string(patch.Raw), err.Error())}
+	}
+	return nil
+}
+
+// ValidateRestartSchedule checks that the restart schedule is a valid cron
+// expression
+func ValidateRestartSchedule(spec *NginxClusterSpec) field.ErrorList {
+	if spec.RestartSchedule == "" {
+		return nil
+	}
+	if _, err := cron.Parse(spec.RestartSchedule); err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "restartSchedule"), spec.RestartSchedule, err.Error())}
+	}
+	return nil
+}
+
+// ValidateHealthCheck checks that gRPC health checks name their port
+func ValidateHealthCheck(spec *NginxClusterSpec) field.ErrorList {
+	if hc := spec.HealthCheck; hc != nil && (hc.Type == "GRPC" || hc.LivenessType == "GRPC") && hc.Port == 0 {
+		return field.ErrorList{field.Required(field.NewPath("spec", "healthCheck", "port"), "required for gRPC health checks")}
+	}
+	return nil
+}
+
+// ValidateIPFamilies checks that the Service IP families are distinct and
+// fit the IP family policy
+func ValidateIPFamilies(spec *NginxClusterSpec) field.ErrorList {
+	families := spec.IPFamilies
+	path := field.NewPath("spec", "ipFamilies")
+	if len(families) == 2 && families[0] == families[1] {
+		return field.ErrorList{field.Duplicate(path.Index(1), families[1])}
+	}
+	policy := spec.IPFamilyPolicy
+	if policy == nil {
+		return nil
+	}
+	if *policy == corev1.IPFamilyPolicySingleStack && len(families) > 1 {
+		return field.ErrorList{field.Invalid(path, families, "SingleStack allows one IP family")}
+	}
+	if *policy == corev1.IPFamilyPolicyRequireDualStack && len(families) == 1 {
+		return field.ErrorList{field.Invalid(path, families, "RequireDualStack needs both IP families")}
+	}
+	return nil
+}
+
+// ValidateSharedServiceName checks that the shared Service isn't named
+// after the cluster, whose per-cluster Service has that name
+func ValidateSharedServiceName(spec *NginxClusterSpec, name string) field.ErrorList {
+	if spec.SharedServiceName != "" && spec.SharedServiceName == name {
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "sharedServiceName"), spec.SharedServiceName, "cannot be the name of the cluster, which is taken by the per-cluster Service")}
+	}
+	return nil
+}
+
+// ValidateExternalDeployment checks that the operator doesn't also run the
+// Deployment it restarts for config changes
+func ValidateExternalDeployment(spec *NginxClusterSpec) field.ErrorList {
+	if spec.ExternalDeployment != "" && (spec.ManageWorkload == nil || *spec.ManageWorkload) {
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "externalDeployment"), spec.ExternalDeployment, "requires manageWorkload false")}
+	}
+	return nil
+}
+
+// ValidateListenAddresses checks that the listen addresses are IP addresses
+func ValidateListenAddresses(spec *NginxClusterSpec) field.ErrorList {
+	var errs field.ErrorList
+	for i, addr := range spec.ListenAddresses {
+		if net.ParseIP(addr) == nil {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "listenAddresses").Index(i), addr, "must be an IP address"))
+		}
+	}
+	return errs
+}
+
+// ValidateGzip checks the gzip compression level, and that the types can't
+// end the gzip_types directive
+func ValidateGzip(spec *NginxClusterSpec) field.ErrorList {
+	gz := spec.Gzip
+	if gz == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	path := field.NewPath("spec", "gzip")
+	if gz.Level < 0 || gz.Level > 9 {
+		errs = append(errs, field.Invalid(path.Child("level"), gz.Level, "must be from 1 to 9"))
+	}
+	for i, t := range gz.Types {
+		if !gzipTypePattern.MatchString(t) {
+			errs = append(errs, field.Invalid(path.Child("types").Index(i), t, "must be a MIME type without whitespace, ';', '{' or '}'"))
+		}
+	}
+	return errs
+}
+
+// ValidateTotalConnections checks that the connections leave each replica
+// at least MinWorkerConnections
+func ValidateTotalConnections(spec *NginxClusterSpec) field.ErrorList {
+	if spec.TotalConnections <= 0 {
+		return nil
+	}
+	if n := spec.TotalConnections / max(spec.Replicas, 1); n < MinWorkerConnections {
+		return field.ErrorList{field.Invalid(field.NewPath("spec", "totalConnections"), spec.TotalConnections,
+			fmt.Sprintf("leaves %d worker_connections per replica, fewer than %d", n, MinWorkerConnections))}
+	}
+	return nil
+}
+
+// ValidateImmutableConfig checks that an immutable config reaches the pods
+// through restarts
+func ValidateImmutableConfig(spec *NginxClusterSpec) field.ErrorList {
+	var errs field.ErrorList
+	path := field.NewPath("spec", "immutableConfig")
+	if spec.ImmutableConfig && spec.ConfigReloaderSidecar {
+		errs = append(errs, field.Invalid(path, true, "cannot be combined with configReloaderSidecar"))
+	}
+	if spec.ImmutableConfig && spec.RestartOnConfigChange != nil && !*spec.RestartOnConfigChange {
+		errs = append(errs, field.Invalid(path, true, "requires restartOnConfigChange"))
+	}
+	return errs
+}
+
+// ValidateServiceType checks that loadBalancerClass and externalName come
+// with the Service type they apply to
+func ValidateServiceType(spec *NginxClusterSpec) field.ErrorList {
+	var errs field.ErrorList
+	if spec.LoadBalancerClass != nil && spec.ServiceType != corev1.ServiceTypeLoadBalancer {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "loadBalancerClass"), *spec.LoadBalancerClass, "requires serviceType LoadBalancer"))
+	}
+	if spec.ServiceType == corev1.ServiceTypeExternalName && spec.ExternalName == "" {
+		errs = append(errs, field.Required(field.NewPath("spec", "externalName"), "required for serviceType ExternalName"))
+	} else if spec.ExternalName != "" && spec.ServiceType != corev1.ServiceTypeExternalName {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "externalName"), spec.ExternalName, "requires serviceType ExternalName"))
+	}
+	return errs
+}
+
+// ValidateErrorPages checks that error pages are keyed by status codes
+// error_page accepts
+func ValidateErrorPages(spec *NginxClusterSpec) field.ErrorList {
+	var errs field.ErrorList
+	path := field.NewPath("spec", "errorPages")
+	for code := range spec.ErrorPages {
+		if n, err := strconv.Atoi(code); err != nil || len(code) != 3 || n < 300 || n > 599 {
+			errs = append(errs, field.Invalid(path.Key(code), code, "must be an HTTP status code from 300 to 599"))
+		}
+	}
+	return errs
+}
+
+// ValidateNjsScripts checks that njs scripts are named after the module they
+// are imported as
+func ValidateNjsScripts(spec *NginxClusterSpec) field.ErrorList {
+	var errs field.ErrorList
+	path := field.NewPath("spec", "njsScripts")
+	for name := range spec.NjsScripts {
+		if !njsScriptNamePattern.MatchString(name) {
+			errs = append(errs, field.Invalid(path.Key(name), name, "must be a JavaScript identifier followed by .js"))
+		}
+	}
+	return errs
+}
+
+// ValidateRolloutRamp checks that the ramp has steps, each moving on at a
+// larger share of updated replicas than the previous one
+func ValidateRolloutRamp(spec *NginxClusterSpec) field.ErrorList {
+	if spec.RolloutRamp == nil {
+		return nil
+	}
+	steps := spec.RolloutRamp.Steps
+	path := field.NewPath("spec", "rolloutRamp", "steps")
+	if len(steps) == 0 {
+		return field.ErrorList{field.Required(path, "at least one step is required")}
+	}
+	var errs field.ErrorList
+	for i := 1; i < len(steps); i++ {
+		if steps[i].UpdatedPercent <= steps[i-1].UpdatedPercent {
+			errs = append(errs, field.Invalid(path.Index(i).Child("updatedPercent"), steps[i].UpdatedPercent, "must be greater than the updatedPercent of the previous step"))
+		}
+	}
+	return errs
+}
+
+// ValidateLogFormat checks that the log format sets one of format and preset,
+// under a name other than the predefined combined
+func ValidateLogFormat(spec *NginxClusterSpec) field.ErrorList {
+	lf := spec.LogFormat
+	if lf == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	path := field.NewPath("spec", "logFormat")
+	switch {
+	case strings.TrimSpace(lf.Format) == "" && lf.Preset == "":
+		errs = append(errs, field.Required(path.Child("format"), "format or preset is required"))
+	case lf.Format != "" && lf.Preset != "":
+		errs = append(errs, field.Invalid(path.Child("format"), lf.Format, "cannot be combined with preset"))
+	}
+	if lf.Name == "combined" {
+		errs = append(errs, field.Invalid(path.Child("name"), lf.Name, "is predefined by nginx"))
+	}
+	return errs
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// safeSysctls are the sysctls every kubelet allows, see
+// https://kubernetes.io/docs/tasks/administer-cluster/sysctl-cluster/
+var safeSysctls = map[string]bool{
+	"kernel.shm_rmid_forced":              true,
+	"net.ipv4.ip_local_port_range":        true,
+	"net.ipv4.tcp_syncookies":             true,
+	"net.ipv4.ping_group_range":           true,
+	"net.ipv4.ip_unprivileged_port_start": true,
+	"net.ipv4.ip_local_reserved_ports":    true,
+	"net.ipv4.tcp_keepalive_time":         true,
+	"net.ipv4.tcp_fin_timeout":            true,
+	"net.ipv4.tcp_keepalive_intvl":        true,
+	"net.ipv4.tcp_keepalive_probes":       true,
+}
+
+// log is for logging in this package.
+var nginxclusterlog = logf.Log.WithName("nginxcluster-resource")
+
+// deprecatedDirective is a config pattern that still works but should be
+// migrated away from.
+type deprecatedDirective struct {
+	pattern *regexp.Regexp
+	message string
+}
+
+// deprecatedDirectives are the patterns the validating webhook warns about.
+// Matching objects are still admitted.
+var deprecatedDirectives = []deprecatedDirective{
+	{
+		pattern: regexp.MustCompile(`(?m)^\s*ssl\s+on\s*;`),
+		message: `"ssl on;" is deprecated, use the ssl parameter of the listen directive instead`,
+	},
+	{
+		pattern: regexp.MustCompile(`(?m)^\s*listen\s+[^;]*\bhttp2\b`),
+		message: `the http2 parameter of the listen directive is deprecated since nginx 1.25.1, use "http2 on;" instead`,
+	},
+	{
+		pattern: regexp.MustCompile(`(?m)^\s*listen\s+[^;]*\bspdy\b`),
+		message: `the spdy parameter of the listen directive was removed in nginx 1.9.5, use http2 instead`,
+	},
+	{
+		pattern: regexp.MustCompile(`(?m)^\s*ssl_protocols\s+[^;]*\b(SSLv2|SSLv3|TLSv1|TLSv1\.1)\s*[ ;]`),
+		message: `ssl_protocols enables protocols older than TLSv1.2`,
+	},
+}
+
+func (r *NginxCluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-nginx-example-com-v1-nginxcluster,mutating=false,failurePolicy=fail,sideEffects=None,groups=nginx.example.com,resources=nginxclusters,verbs=create;update,versions=v1,name=vnginxcluster.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &NginxCluster{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *NginxCluster) ValidateCreate() (admission.Warnings, error) {
+	nginxclusterlog.Info("validate create", "name", r.Name)
+	return r.warnings(), r.validate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *NginxCluster) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	nginxclusterlog.Info("validate update", "name", r.Name)
+	return r.warnings(), r.validate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *NginxCluster) ValidateDelete() (admission.Warnings, error) {
+	return nil, nil
+}
+
+// warnings returns the warnings about settings that are admitted but likely
+// wrong
+func (r *NginxCluster) warnings() admission.Warnings {
+	warnings := r.configWarnings()
+	warnings = append(warnings, r.shutdownWarnings()...)
+	warnings = append(warnings, r.sysctlWarnings()...)
+	return append(warnings, r.finalizerWarnings()...)
+}
+
+// finalizerWarnings warns when disabling the finalizer leaves resources
+// behind, as those in another namespace carry no owner reference
+func (r *NginxCluster) finalizerWarnings() admission.Warnings {
+	if r.Spec.DisableFinalizer && r.Spec.TargetNamespace != "" && r.Spec.TargetNamespace != r.Namespace {
+		return admission.Warnings{"spec.disableFinalizer: resources in spec.targetNamespace are not deleted with the cluster without the finalizer"}
+	}
+	return nil
+}
+
+// configWarnings returns one warning per deprecated pattern found in NginxConf
+// or the active config profile
+func (r *NginxCluster) configWarnings() admission.Warnings {
+	var warnings admission.Warnings
+	for _, d := range deprecatedDirectives {
+		if d.pattern.MatchString(r.Spec.NginxConf) {
+			warnings = append(warnings, "spec.nginxConf: "+d.message)
+		}
+		if profile, ok := r.Spec.ConfigProfiles[r.Spec.ActiveProfile]; ok && d.pattern.MatchString(profile) {
+			warnings = append(warnings, fmt.Sprintf("spec.configProfiles[%s]: %s", r.Spec.ActiveProfile, d.message))
+		}
+	}
+	return warnings
+}
+
+// shutdownWarnings warns when podTemplatePatch overrides the termination grace
+// period with one too short for the shutdown drain
+func (r *NginxCluster) shutdownWarnings() admission.Warnings {
+	drain := r.Spec.ShutdownDrainSeconds
+	patch := r.Spec.PodTemplatePatch
+	if drain <= 0 || patch == nil || len(patch.Raw) == 0 {
+		return nil
+	}
+	var overrides struct {
+		Spec struct {
+			TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(patch.Raw, &overrides); err != nil {
+		// Malformed patches are rejected by validate
+		return nil
+	}
+	gracePeriod := overrides.Spec.TerminationGracePeriodSeconds
+	if want := int64(drain) + ShutdownDrainBufferSeconds; gracePeriod != nil && *gracePeriod < want {
+		return admission.Warnings{fmt.Sprintf("spec.podTemplatePatch: terminationGracePeriodSeconds %d is shorter than shutdownDrainSeconds plus %ds (%d); nginx may be killed while draining", *gracePeriod, ShutdownDrainBufferSeconds, want)}
+	}
+	return nil
+}
+
+// sysctlWarnings warns about sysctls outside the safe set, which the kubelet
+// rejects pods for unless they are explicitly allowed
+func (r *NginxCluster) sysctlWarnings() admission.Warnings {
+	var warnings admission.Warnings
+	for i, s := range r.Spec.Sysctls {
+		if !safeSysctls[s.Name] {
+			warnings = append(warnings, fmt.Sprintf("spec.sysctls[%d]: %s is not a safe sysctl; pods are rejected unless it is allowed with the kubelet's --allowed-unsafe-sysctls", i, s.Name))
+		}
+	}
+	return warnings
+}
+
+// validate rejects specs the reconciler cannot act on
+func (r *NginxCluster) validate() error {
+	errs := ValidateSpec(r)
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: "NginxCluster"}, r.Name, errs)
+}
@@ -0,0 +1,380 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestValidateWarnsOnDeprecatedDirectives(t *testing.T) {
+	m := &NginxCluster{Spec: NginxClusterSpec{NginxConf: `events {}
+http {
+    server {
+        listen 443 ssl http2;
+        ssl on;
+        ssl_protocols TLSv1 TLSv1.2;
+    }
+}
+`}}
+
+	warnings, err := m.ValidateCreate()
+	if err != nil {
+		t.Fatalf("deprecated directives must not reject the object: %v", err)
+	}
+	if len(warnings) != 3 {
+		t.Fatalf("expected 3 warnings, got %d: %v", len(warnings), warnings)
+	}
+	for _, w := range warnings {
+		if !strings.HasPrefix(w, "spec.nginxConf: ") {
+			t.Errorf("warning %q does not name the field", w)
+		}
+	}
+
+	warnings, err = m.ValidateUpdate(m.DeepCopy())
+	if err != nil || len(warnings) != 3 {
+		t.Fatalf("expected the same warnings on update, got %v, %v", warnings, err)
+	}
+}
+
+func TestValidateCleanConfigHasNoWarnings(t *testing.T) {
+	m := &NginxCluster{Spec: NginxClusterSpec{NginxConf: `events {}
+http {
+    server {
+        listen 443 ssl;
+        http2 on;
+        ssl_protocols TLSv1.2 TLSv1.3;
+        # ssl on; is deprecated
+    }
+}
+`}}
+
+	warnings, err := m.ValidateCreate()
+	if err != nil || len(warnings) != 0 {
+		t.Fatalf("expected no warnings, got %v, %v", warnings, err)
+	}
+}
+
+func TestValidateRejectsMalformedPodTemplatePatch(t *testing.T) {
+	m := &NginxCluster{Spec: NginxClusterSpec{
+		PodTemplatePatch: &runtime.RawExtension{Raw: []byte(`{"spec": {"containers": "nginx"}}`)},
+	}}
+	if _, err := m.ValidateCreate(); err == nil || !strings.Contains(err.Error(), "spec.podTemplatePatch") {
+		t.Fatalf("expected the malformed patch to be rejected, got %v", err)
+	}
+
+	m.Spec.PodTemplatePatch.Raw = []byte(`{"spec": {"priorityClassName": "edge-critical"}}`)
+	if _, err := m.ValidateUpdate(m.DeepCopy()); err != nil {
+		t.Fatalf("valid patch rejected: %v", err)
+	}
+}
+
+func TestValidateRejectsInvalidRestartSchedule(t *testing.T) {
+	m := &NginxCluster{Spec: NginxClusterSpec{RestartSchedule: "0 25 * * *"}}
+	if _, err := m.ValidateCreate(); err == nil || !strings.Contains(err.Error(), "spec.restartSchedule") {
+		t.Fatalf("expected the invalid schedule to be rejected, got %v", err)
+	}
+
+	m.Spec.RestartSchedule = "0 3 * * *"
+	if _, err := m.ValidateUpdate(m.DeepCopy()); err != nil {
+		t.Fatalf("valid schedule rejected: %v", err)
+	}
+}
+
+func TestValidateRequiresGRPCHealthCheckPort(t *testing.T) {
+	m := &NginxCluster{Spec: NginxClusterSpec{HealthCheck: &HealthCheckSpec{Type: "GRPC"}}}
+	if _, err := m.ValidateCreate(); err == nil || !strings.Contains(err.Error(), "spec.healthCheck.port") {
+		t.Fatalf("expected the missing port to be rejected, got %v", err)
+	}
+
+	m.Spec.HealthCheck.Port = 9090
+	if _, err := m.ValidateCreate(); err != nil {
+		t.Fatalf("valid health check rejected: %v", err)
+	}
+}
+
+func TestValidateRequiresUpstreamsForReverseProxy(t *testing.T) {
+	m := &NginxCluster{Spec: NginxClusterSpec{DefaultConfigMode: "ReverseProxy"}}
+	if _, err := m.ValidateCreate(); err == nil || !strings.Contains(err.Error(), "spec.upstreams") {
+		t.Fatalf("expected the missing upstreams to be rejected, got %v", err)
+	}
+
+	m.Spec.Upstreams = []string{"app.default.svc:8080"}
+	if _, err := m.ValidateCreate(); err != nil {
+		t.Fatalf("valid reverse proxy rejected: %v", err)
+	}
+}
+
+func TestValidateActiveProfile(t *testing.T) {
+	m := &NginxCluster{Spec: NginxClusterSpec{
+		ConfigProfiles: map[string]string{"staging": "events {}\nhttp {}\n"},
+		ActiveProfile:  "production",
+	}}
+	if _, err := m.ValidateCreate(); err == nil || !strings.Contains(err.Error(), "spec.activeProfile") {
+		t.Fatalf("expected the unknown profile to be rejected, got %v", err)
+	}
+
+	m.Spec.ActiveProfile = "staging"
+	if _, err := m.ValidateCreate(); err != nil {
+		t.Fatalf("valid profile rejected: %v", err)
+	}
+
+	m.Spec.NginxConf = "events {}\n"
+	if _, err := m.ValidateCreate(); err == nil || !strings.Contains(err.Error(), "mutually exclusive") {
+		t.Fatalf("expected a profile together with nginxConf to be rejected, got %v", err)
+	}
+}
+
+func TestValidateWarnsOnUnsafeSysctls(t *testing.T) {
+	m := &NginxCluster{Spec: NginxClusterSpec{Sysctls: []corev1.Sysctl{
+		{Name: "net.ipv4.tcp_fin_timeout", Value: "15"},
+		{Name: "net.core.somaxconn", Value: "65535"},
+	}}}
+	warnings, err := m.ValidateCreate()
+	if err != nil {
+		t.Fatalf("unsafe sysctls rejected: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "spec.sysctls[1]: net.core.somaxconn") {
+		t.Fatalf("expected a warning about net.core.somaxconn only, got %v", warnings)
+	}
+}
+
+func TestValidateWarnsOnDisabledFinalizerWithTargetNamespace(t *testing.T) {
+	m := &NginxCluster{Spec: NginxClusterSpec{DisableFinalizer: true}}
+	m.Namespace = "default"
+	if warnings, _ := m.ValidateCreate(); len(warnings) != 0 {
+		t.Fatalf("unexpected warnings %v", warnings)
+	}
+	m.Spec.TargetNamespace = "workloads"
+	warnings, _ := m.ValidateCreate()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "spec.disableFinalizer") {
+		t.Fatalf("expected a warning about the finalizer, got %v", warnings)
+	}
+}
+
+func TestValidateRejectsDuplicateIPFamilies(t *testing.T) {
+	m := &NginxCluster{Spec: NginxClusterSpec{IPFamilies: []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv4Protocol}}}
+	if _, err := m.ValidateCreate(); err == nil || !strings.Contains(err.Error(), "spec.ipFamilies[1]") {
+		t.Fatalf("expected the duplicate family to be rejected, got %v", err)
+	}
+
+	requireDualStack := corev1.IPFamilyPolicyRequireDualStack
+	m.Spec.IPFamilyPolicy = &requireDualStack
+	m.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv6Protocol}
+	if _, err := m.ValidateCreate(); err == nil || !strings.Contains(err.Error(), "spec.ipFamilies") {
+		t.Fatalf("expected a single family to be rejected for RequireDualStack, got %v", err)
+	}
+}
+
+func TestValidateRejectsInvalidErrorPageCodes(t *testing.T) {
+	m := &NginxCluster{Spec: NginxClusterSpec{ErrorPages: map[string]string{"404": "", "200": ""}}}
+	_, err := m.ValidateCreate()
+	if err == nil || !strings.Contains(err.Error(), "spec.errorPages[200]") || strings.Contains(err.Error(), "spec.errorPages[404]") {
+		t.Fatalf("expected only the 200 page to be rejected, got %v", err)
+	}
+	for _, code := range []string{"302", "599"} {
+		if errs := ValidateErrorPages(&NginxClusterSpec{ErrorPages: map[string]string{code: ""}}); len(errs) != 0 {
+			t.Errorf("expected %q to be accepted, got %v", code, errs)
+		}
+	}
+	for _, code := range []string{"600", "40x", "0404"} {
+		if errs := ValidateErrorPages(&NginxClusterSpec{ErrorPages: map[string]string{code: ""}}); len(errs) != 1 {
+			t.Errorf("expected %q to be rejected", code)
+		}
+	}
+}
+
+func TestValidateExternalName(t *testing.T) {
+	m := &NginxCluster{Spec: NginxClusterSpec{ServiceType: corev1.ServiceTypeExternalName}}
+	if _, err := m.ValidateCreate(); err == nil || !strings.Contains(err.Error(), "spec.externalName") {
+		t.Fatalf("expected a missing externalName to be rejected, got %v", err)
+	}
+	m.Spec.ExternalName = "nginx.legacy.example.com"
+	if _, err := m.ValidateCreate(); err != nil {
+		t.Fatalf("ValidateCreate() error = %v", err)
+	}
+	m.Spec.ServiceType = corev1.ServiceTypeClusterIP
+	if _, err := m.ValidateCreate(); err == nil || !strings.Contains(err.Error(), "spec.externalName") {
+		t.Fatalf("expected externalName without ExternalName type to be rejected, got %v", err)
+	}
+}
+
+func TestValidateRejectsImmutableConfigWithReloader(t *testing.T) {
+	m := &NginxCluster{Spec: NginxClusterSpec{ImmutableConfig: true, ConfigReloaderSidecar: true}}
+	if _, err := m.ValidateCreate(); err == nil || !strings.Contains(err.Error(), "spec.immutableConfig") {
+		t.Fatalf("expected immutableConfig with the reloader sidecar to be rejected, got %v", err)
+	}
+	disabled := false
+	m.Spec.ConfigReloaderSidecar = false
+	m.Spec.RestartOnConfigChange = &disabled
+	if _, err := m.ValidateCreate(); err == nil || !strings.Contains(err.Error(), "spec.immutableConfig") {
+		t.Fatalf("expected immutableConfig without restarts on config changes to be rejected, got %v", err)
+	}
+}
+
+func TestValidateTotalConnections(t *testing.T) {
+	m := &NginxCluster{Spec: NginxClusterSpec{Replicas: 4, TotalConnections: 1024}}
+	if _, err := m.ValidateCreate(); err != nil {
+		t.Fatalf("ValidateCreate() error = %v", err)
+	}
+	m.Spec.Replicas = 32
+	if _, err := m.ValidateCreate(); err == nil || !strings.Contains(err.Error(), "spec.totalConnections") {
+		t.Fatalf("expected 32 worker_connections per replica to be rejected, got %v", err)
+	}
+}
+
+func TestValidateListenAddresses(t *testing.T) {
+	m := &NginxCluster{Spec: NginxClusterSpec{ListenAddresses: []string{"10.0.0.5", "10.0.0"}}}
+	_, err := m.ValidateCreate()
+	if err == nil || !strings.Contains(err.Error(), "spec.listenAddresses[1]") || strings.Contains(err.Error(), "spec.listenAddresses[0]") {
+		t.Fatalf("expected only the second address to be rejected, got %v", err)
+	}
+}
+
+func TestValidateConfigSource(t *testing.T) {
+	m := &NginxCluster{Spec: NginxClusterSpec{NginxConfFrom: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "shared"}}}}
+	if _, err := m.ValidateCreate(); err == nil || !strings.Contains(err.Error(), "spec.nginxConfFrom.key") {
+		t.Fatalf("expected nginxConfFrom without a key to be rejected, got %v", err)
+	}
+	m.Spec.NginxConfFrom.Key = "nginx.conf"
+	if _, err := m.ValidateCreate(); err != nil {
+		t.Fatalf("ValidateCreate() error = %v", err)
+	}
+	m.Spec.NginxConf = "events {}\n"
+	if _, err := m.ValidateCreate(); err == nil || !strings.Contains(err.Error(), "spec.nginxConfFrom") {
+		t.Fatalf("expected nginxConf with nginxConfFrom to be rejected, got %v", err)
+	}
+	m.Spec.NginxConfFrom = nil
+	m.Spec.ConfigTemplateFrom = &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "template"}, Key: "nginx.conf.tmpl"}
+	if _, err := m.ValidateCreate(); err == nil || !strings.Contains(err.Error(), "spec.configTemplateFrom") {
+		t.Fatalf("expected nginxConf with configTemplateFrom to be rejected, got %v", err)
+	}
+}
+
+func TestValidateSharedServiceName(t *testing.T) {
+	m := &NginxCluster{ObjectMeta: metav1.ObjectMeta{Name: "blue"}, Spec: NginxClusterSpec{SharedServiceName: "blue"}}
+	if _, err := m.ValidateCreate(); err == nil || !strings.Contains(err.Error(), "spec.sharedServiceName") {
+		t.Fatalf("expected the cluster's own name to be rejected, got %v", err)
+	}
+	m.Spec.SharedServiceName = "web"
+	if _, err := m.ValidateCreate(); err != nil {
+		t.Fatalf("ValidateCreate() error = %v", err)
+	}
+}
+
+func TestValidateExternalDeployment(t *testing.T) {
+	m := &NginxCluster{Spec: NginxClusterSpec{ExternalDeployment: "helm-nginx"}}
+	if _, err := m.ValidateCreate(); err == nil || !strings.Contains(err.Error(), "spec.externalDeployment") {
+		t.Fatalf("expected externalDeployment with a managed workload to be rejected, got %v", err)
+	}
+	disabled := false
+	m.Spec.ManageWorkload = &disabled
+	if _, err := m.ValidateCreate(); err != nil {
+		t.Fatalf("ValidateCreate() error = %v", err)
+	}
+}
+
+func TestValidateNjsScripts(t *testing.T) {
+	m := &NginxCluster{Spec: NginxClusterSpec{NjsScripts: map[string]string{"main.js": "", "my-script.js": ""}}}
+	_, err := m.ValidateCreate()
+	if err == nil || !strings.Contains(err.Error(), "spec.njsScripts[my-script.js]") || strings.Contains(err.Error(), "spec.njsScripts[main.js]") {
+		t.Fatalf("expected only my-script.js to be rejected, got %v", err)
+	}
+	for _, name := range []string{"main", "main.mjs", "2fa.js", "../main.js"} {
+		if errs := ValidateNjsScripts(&NginxClusterSpec{NjsScripts: map[string]string{name: ""}}); len(errs) != 1 {
+			t.Errorf("expected %q to be rejected", name)
+		}
+	}
+}
+
+func TestValidateGzipLevel(t *testing.T) {
+	m := &NginxCluster{Spec: NginxClusterSpec{Gzip: &GzipSpec{Level: 10}}}
+	if _, err := m.ValidateCreate(); err == nil || !strings.Contains(err.Error(), "spec.gzip.level") {
+		t.Fatalf("expected gzip level 10 to be rejected, got %v", err)
+	}
+}
+
+func TestValidateGzipTypes(t *testing.T) {
+	m := &NginxCluster{Spec: NginxClusterSpec{Gzip: &GzipSpec{Types: []string{"application/wasm", "text/plain; } server {"}}}}
+	_, err := m.ValidateCreate()
+	if err == nil || !strings.Contains(err.Error(), "spec.gzip.types[1]") || strings.Contains(err.Error(), "spec.gzip.types[0]") {
+		t.Fatalf("expected only the second type to be rejected, got %v", err)
+	}
+}
+
+func TestValidateLogFormat(t *testing.T) {
+	m := &NginxCluster{Spec: NginxClusterSpec{LogFormat: &LogFormatSpec{Name: "main"}}}
+	if _, err := m.ValidateCreate(); err == nil || !strings.Contains(err.Error(), "spec.logFormat.format") {
+		t.Fatalf("expected an empty format to be rejected, got %v", err)
+	}
+	m.Spec.LogFormat = &LogFormatSpec{Name: "combined", Preset: "json"}
+	if _, err := m.ValidateCreate(); err == nil || !strings.Contains(err.Error(), "spec.logFormat.name") {
+		t.Fatalf("expected the combined name to be rejected, got %v", err)
+	}
+	for _, tc := range []struct {
+		spec  LogFormatSpec
+		valid bool
+	}{
+		{LogFormatSpec{Preset: "json"}, true},
+		{LogFormatSpec{Name: "custom", Format: "$remote_addr $status"}, true},
+		{LogFormatSpec{Format: "  "}, false},
+		{LogFormatSpec{Format: "$status", Preset: "json"}, false},
+		{LogFormatSpec{Name: "combined", Format: "$status"}, false},
+	} {
+		if errs := ValidateLogFormat(&NginxClusterSpec{LogFormat: &tc.spec}); (len(errs) == 0) != tc.valid {
+			t.Errorf("ValidateLogFormat(%+v) = %v, want valid %v", tc.spec, errs, tc.valid)
+		}
+	}
+}
+
+func TestValidateRejectsUnorderedRolloutRamp(t *testing.T) {
+	m := &NginxCluster{Spec: NginxClusterSpec{RolloutRamp: &RampSpec{Steps: []RampStep{
+		{UpdatedPercent: 0, MinReadySeconds: 60},
+		{UpdatedPercent: 50, MinReadySeconds: 10},
+		{UpdatedPercent: 50},
+	}}}}
+	_, err := m.ValidateCreate()
+	if err == nil || !strings.Contains(err.Error(), "spec.rolloutRamp.steps[2].updatedPercent") || strings.Contains(err.Error(), "steps[1]") {
+		t.Fatalf("expected only the third step to be rejected, got %v", err)
+	}
+	if errs := ValidateRolloutRamp(&NginxClusterSpec{RolloutRamp: &RampSpec{}}); len(errs) != 1 || errs[0].Field != "spec.rolloutRamp.steps" {
+		t.Fatalf("expected a ramp without steps to be rejected, got %v", errs)
+	}
+}
+
+func TestValidateWarnsOnShortGracePeriod(t *testing.T) {
+	m := &NginxCluster{Spec: NginxClusterSpec{
+		ShutdownDrainSeconds: 30,
+		PodTemplatePatch:     &runtime.RawExtension{Raw: []byte(`{"spec": {"terminationGracePeriodSeconds": 20}}`)},
+	}}
+	warnings, err := m.ValidateCreate()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "terminationGracePeriodSeconds 20") {
+		t.Fatalf("expected a grace period warning, got %v", warnings)
+	}
+
+	m.Spec.PodTemplatePatch.Raw = []byte(`{"spec": {"terminationGracePeriodSeconds": 60}}`)
+	if warnings, _ := m.ValidateUpdate(m.DeepCopy()); len(warnings) != 0 {
+		t.Fatalf("unexpected warnings %v", warnings)
+	}
+}
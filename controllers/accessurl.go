@@ -0,0 +1,162 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// accessURL returns the URL nginx is reachable at from outside the cluster:
+// the host of the Route when there is one, else the load balancer address or
+// a node port of the Service. It is empty while none is assigned, and for
+// ClusterIP Services.
+func (r *NginxClusterReconciler) accessURL(ctx context.Context, m *nginxv1.NginxCluster) (string, error) {
+	if m.Spec.Route != nil {
+		available, err := routeAPIAvailable(r.RESTMapper())
+		if err != nil {
+			return "", err
+		}
+		if available {
+			route := newRoute()
+			err := r.Get(ctx, types.NamespacedName{Name: m.Name, Namespace: workloadNamespace(m)}, route)
+			if err != nil && !errors.IsNotFound(err) {
+				return "", err
+			}
+			if err == nil {
+				if u := routeAccessURL(route); u != "" {
+					return u, nil
+				}
+			}
+		}
+	}
+
+	serviceName := m.Name
+	if m.Spec.SharedServiceName != "" {
+		serviceName = m.Spec.SharedServiceName
+	}
+	srv := &corev1.Service{}
+	err := r.Get(ctx, types.NamespacedName{Name: serviceName, Namespace: workloadNamespace(m)}, srv)
+	if errors.IsNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	var nodeAddress string
+	if srv.Spec.Type == corev1.ServiceTypeNodePort {
+		if nodeAddress, err = r.nodeAddress(ctx); err != nil {
+			return "", err
+		}
+	}
+	return serviceAccessURL(srv, nodeAddress), nil
+}
+
+// routeAccessURL returns the URL of the host admitted by the router, or the
+// one requested in the spec until then
+func routeAccessURL(route *unstructured.Unstructured) string {
+	host, _, _ := unstructured.NestedString(route.Object, "spec", "host")
+	ingress, _, _ := unstructured.NestedSlice(route.Object, "status", "ingress")
+	if len(ingress) > 0 {
+		if admitted, ok := ingress[0].(map[string]interface{}); ok {
+			if h, _, _ := unstructured.NestedString(admitted, "host"); h != "" {
+				host = h
+			}
+		}
+	}
+	if host == "" {
+		return ""
+	}
+	scheme := "http"
+	if termination, _, _ := unstructured.NestedString(route.Object, "spec", "tls", "termination"); termination != "" {
+		scheme = "https"
+	}
+	return (&url.URL{Scheme: scheme, Host: host}).String()
+}
+
+// serviceAccessURL returns the URL of the http port of srv on its load
+// balancer, or on nodeAddress for NodePort Services
+func serviceAccessURL(srv *corev1.Service, nodeAddress string) string {
+	var port *corev1.ServicePort
+	for i := range srv.Spec.Ports {
+		if srv.Spec.Ports[i].Name == "http" {
+			port = &srv.Spec.Ports[i]
+		}
+	}
+	if port == nil {
+		return ""
+	}
+	switch srv.Spec.Type {
+	case corev1.ServiceTypeLoadBalancer:
+		ingress := srv.Status.LoadBalancer.Ingress
+		if len(ingress) == 0 {
+			return ""
+		}
+		host := ingress[0].IP
+		if host == "" {
+			host = ingress[0].Hostname
+		}
+		return httpURL(host, port.Port)
+	case corev1.ServiceTypeNodePort:
+		if nodeAddress == "" || port.NodePort == 0 {
+			return ""
+		}
+		return httpURL(nodeAddress, port.NodePort)
+	}
+	return ""
+}
+
+// httpURL returns the http URL of host and port, leaving out the default
+// port
+func httpURL(host string, port int32) string {
+	if port != 80 {
+		host = net.JoinHostPort(host, strconv.Itoa(int(port)))
+	} else if ip := net.ParseIP(host); ip != nil && ip.To4() == nil {
+		host = "[" + host + "]"
+	}
+	return (&url.URL{Scheme: "http", Host: host}).String()
+}
+
+// nodeAddress returns an address NodePort Services can be reached at: the
+// external IP of the first node by name, or its internal IP when no node
+// has an external one
+func (r *NginxClusterReconciler) nodeAddress(ctx context.Context) (string, error) {
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes); err != nil {
+		return "", err
+	}
+	sort.Slice(nodes.Items, func(i, j int) bool { return nodes.Items[i].Name < nodes.Items[j].Name })
+	for _, addressType := range []corev1.NodeAddressType{corev1.NodeExternalIP, corev1.NodeInternalIP} {
+		for _, node := range nodes.Items {
+			for _, address := range node.Status.Addresses {
+				if address.Type == addressType {
+					return address.Address, nil
+				}
+			}
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+func TestServiceAccessURL(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	srv := r.serviceForNginxCluster(newTestNginxCluster("access-url"))
+	if got := serviceAccessURL(srv, "10.0.0.1"); got != "" {
+		t.Fatalf("expected no URL for a ClusterIP Service, got %q", got)
+	}
+
+	srv.Spec.Type = corev1.ServiceTypeLoadBalancer
+	if got := serviceAccessURL(srv, ""); got != "" {
+		t.Fatalf("expected no URL before the load balancer is assigned, got %q", got)
+	}
+	srv.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{Hostname: "lb.example.com"}}
+	if got := serviceAccessURL(srv, ""); got != "http://lb.example.com" {
+		t.Fatalf("serviceAccessURL() = %q", got)
+	}
+	srv.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "2001:db8::1"}}
+	if got := serviceAccessURL(srv, ""); got != "http://[2001:db8::1]" {
+		t.Fatalf("serviceAccessURL() = %q", got)
+	}
+
+	srv.Spec.Type = corev1.ServiceTypeNodePort
+	srv.Spec.Ports[0].NodePort = 30080
+	if got := serviceAccessURL(srv, "192.0.2.10"); got != "http://192.0.2.10:30080" {
+		t.Fatalf("serviceAccessURL() = %q", got)
+	}
+}
+
+func TestRouteAccessURL(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("access-url")
+	m.Spec.Route = &nginxv1.RouteSpec{}
+	route := r.routeForNginxCluster(m)
+	if got := routeAccessURL(route); got != "" {
+		t.Fatalf("expected no URL before the router assigns a host, got %q", got)
+	}
+
+	route.Object["status"] = map[string]interface{}{
+		"ingress": []interface{}{map[string]interface{}{"host": "web-default.apps.example.com"}},
+	}
+	if got := routeAccessURL(route); got != "http://web-default.apps.example.com" {
+		t.Fatalf("routeAccessURL() = %q", got)
+	}
+
+	m.Spec.Route = &nginxv1.RouteSpec{Host: "www.example.com", TLSTermination: "edge"}
+	if got := routeAccessURL(r.routeForNginxCluster(m)); got != "https://www.example.com" {
+		t.Fatalf("routeAccessURL() = %q", got)
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// deploymentAdoptionProblems returns why the existing Deployment dep can't
+// be taken over by m. Its selector, which is immutable, must be the one of
+// m, and its nginx container must read its config from a mount under
+// /etc/nginx, where the operator mounts the generated one. Taking over
+// replaces the pod template with the spec-derived one.
+func deploymentAdoptionProblems(m *nginxv1.NginxCluster, dep *appsv1.Deployment) []string {
+	var problems []string
+	if owner := metav1.GetControllerOf(dep); owner != nil {
+		problems = append(problems, fmt.Sprintf("it is controlled by %s %s", owner.Kind, owner.Name))
+	}
+	want := labelsForNginxCluster(m)
+	if sel := dep.Spec.Selector; sel == nil || len(sel.MatchExpressions) > 0 || !reflect.DeepEqual(sel.MatchLabels, want) {
+		problems = append(problems, fmt.Sprintf("its selector is not %s", metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: want})))
+	}
+	name := containerNameForNginxCluster(m)
+	var container *corev1.Container
+	for i := range dep.Spec.Template.Spec.Containers {
+		if dep.Spec.Template.Spec.Containers[i].Name == name {
+			container = &dep.Spec.Template.Spec.Containers[i]
+		}
+	}
+	if container == nil {
+		problems = append(problems, fmt.Sprintf("it has no %s container", name))
+		return problems
+	}
+	mounted := false
+	for _, mount := range container.VolumeMounts {
+		if mount.MountPath == "/etc/nginx" || mount.MountPath == "/etc/nginx/nginx.conf" {
+			mounted = true
+		}
+	}
+	if !mounted {
+		problems = append(problems, fmt.Sprintf("its %s container mounts no config at /etc/nginx", name))
+	}
+	return problems
+}
+
+// adoptionFailedCondition reports a Deployment that can't be adopted
+func adoptionFailedCondition(m *nginxv1.NginxCluster, dep *appsv1.Deployment, problems []string) metav1.Condition {
+	return metav1.Condition{
+		Type:               nginxv1.ConditionDegraded,
+		Status:             metav1.ConditionTrue,
+		Reason:             "AdoptionFailed",
+		Message:            fmt.Sprintf("Deployment %s/%s cannot be adopted: %s", dep.Namespace, dep.Name, strings.Join(problems, ", ")),
+		ObservedGeneration: m.Generation,
+	}
+}
+
+// adoptDeployment takes over the existing Deployment dep for m with
+// spec.adoptExisting, once it is compatible. An incompatible Deployment is
+// left untouched and reported in the Degraded condition; the returned bool
+// tells that the reconcile has to stop there.
+func (r *NginxClusterReconciler) adoptDeployment(ctx context.Context, m *nginxv1.NginxCluster, dep *appsv1.Deployment) (bool, error) {
+	if problems := deploymentAdoptionProblems(m, dep); len(problems) > 0 {
+		degraded := adoptionFailedCondition(m, dep, problems)
+		if cond := meta.FindStatusCondition(m.Status.Conditions, nginxv1.ConditionDegraded); cond == nil || cond.Message != degraded.Message {
+			r.recordEvent(m, corev1.EventTypeWarning, degraded.Reason, degraded.Message)
+		}
+		return true, r.updateStatusWithRetry(ctx, m, func() {
+			meta.SetStatusCondition(&m.Status.Conditions, degraded)
+		})
+	}
+	log.FromContext(ctx).Info("Adopting existing Deployment", "Deployment.Namespace", dep.Namespace, "Deployment.Name", dep.Name)
+	if err := r.updateWithRetry(ctx, dep, func() {
+		r.setOwner(m, dep)
+		// A Deployment without the annotation counts as pinned by a rollback,
+		// an empty one lets the spec-derived template replace the live one
+		if _, ok := dep.Annotations["pod-spec-hash"]; !ok {
+			if dep.Annotations == nil {
+				dep.Annotations = map[string]string{}
+			}
+			dep.Annotations["pod-spec-hash"] = ""
+		}
+	}); err != nil {
+		return false, err
+	}
+	r.recordEvent(m, corev1.EventTypeNormal, "AdoptedDeployment", fmt.Sprintf("Adopted existing Deployment %s", dep.Name))
+	return false, nil
+}
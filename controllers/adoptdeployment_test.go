@@ -0,0 +1,128 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// preexistingDeployment returns a Deployment of m's name as deployed before
+// the operator, e.g. by a Helm chart
+func preexistingDeployment(m *nginxv1.NginxCluster) *appsv1.Deployment {
+	replicas := int32(1)
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: m.Name, Namespace: m.Namespace},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labelsForNginxCluster(m)},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labelsForNginxCluster(m)},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:         "nginx",
+						Image:        "nginx:1.24",
+						VolumeMounts: []corev1.VolumeMount{{Name: "conf", MountPath: "/etc/nginx"}},
+					}},
+					Volumes: []corev1.Volume{{Name: "conf", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}},
+				},
+			},
+		},
+	}
+}
+
+func TestDeploymentAdoptionProblems(t *testing.T) {
+	m := newTestNginxCluster("adopt")
+	if problems := deploymentAdoptionProblems(m, preexistingDeployment(m)); len(problems) != 0 {
+		t.Fatalf("compatible Deployment rejected: %v", problems)
+	}
+
+	dep := preexistingDeployment(m)
+	dep.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "legacy"}}
+	dep.Spec.Template.Spec.Containers[0].VolumeMounts = nil
+	problems := deploymentAdoptionProblems(m, dep)
+	if len(problems) != 2 || !strings.Contains(problems[0], "selector") || !strings.Contains(problems[1], "/etc/nginx") {
+		t.Fatalf("unexpected problems %v", problems)
+	}
+
+	dep = preexistingDeployment(m)
+	dep.Spec.Template.Spec.Containers[0].Name = "web"
+	if problems := deploymentAdoptionProblems(m, dep); len(problems) != 1 || !strings.Contains(problems[0], "no nginx container") {
+		t.Fatalf("unexpected problems %v", problems)
+	}
+}
+
+func TestReconcileAdoptsExistingDeployment(t *testing.T) {
+	requireEnvtest(t)
+	ctx := context.Background()
+
+	// The incompatible Deployment is left alone
+	m := newTestNginxCluster("adopt-incompatible")
+	m.Spec.AdoptExisting = true
+	dep := preexistingDeployment(m)
+	dep.Spec.Template.Spec.Containers[0].VolumeMounts = nil
+	if err := k8sClient.Create(ctx, dep); err != nil {
+		t.Fatalf("failed to create Deployment: %v", err)
+	}
+	createTestNginxCluster(t, m)
+	eventually(t, func() error {
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(m), m); err != nil {
+			return err
+		}
+		if cond := meta.FindStatusCondition(m.Status.Conditions, nginxv1.ConditionDegraded); cond == nil || cond.Reason != "AdoptionFailed" {
+			return fmt.Errorf("Degraded condition is %+v", cond)
+		}
+		return nil
+	})
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(dep), dep); err != nil {
+		t.Fatalf("failed to get Deployment: %v", err)
+	}
+	if metav1.GetControllerOf(dep) != nil || dep.Spec.Template.Spec.Containers[0].Image != "nginx:1.24" {
+		t.Fatalf("incompatible Deployment was taken over")
+	}
+
+	// The compatible one is adopted and brought in line with the spec
+	m = newTestNginxCluster("adopt-compatible")
+	m.Spec.AdoptExisting = true
+	dep = preexistingDeployment(m)
+	if err := k8sClient.Create(ctx, dep); err != nil {
+		t.Fatalf("failed to create Deployment: %v", err)
+	}
+	createTestNginxCluster(t, m)
+	eventually(t, func() error {
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(dep), dep); err != nil {
+			return err
+		}
+		if owner := metav1.GetControllerOf(dep); owner == nil || owner.Name != m.Name {
+			return fmt.Errorf("Deployment not adopted")
+		}
+		if *dep.Spec.Replicas != m.Spec.Replicas || dep.Spec.Template.Spec.Containers[0].Image != m.Spec.Image {
+			return fmt.Errorf("adopted Deployment not synced with the spec")
+		}
+		return nil
+	})
+}
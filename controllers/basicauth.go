@@ -0,0 +1,114 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+const (
+	basicAuthVolumeName = "basic-auth"
+	basicAuthDir        = "/etc/nginx/auth"
+	// basicAuthKey is the key of the htpasswd file in the Secret
+	basicAuthKey = "htpasswd"
+	// defaultBasicAuthRealm applies when spec.basicAuth.realm is unset, as
+	// without the defaulting of the CRD
+	defaultBasicAuthRealm = "Restricted"
+)
+
+// authBasicPattern matches an auth_basic directive
+var authBasicPattern = regexp.MustCompile(`(?m)^[ \t]*auth_basic\s`)
+
+// withBasicAuth turns on basic authentication for all servers of conf with
+// auth_basic directives in its http block, unless conf sets auth_basic
+// itself
+func withBasicAuth(m *nginxv1.NginxCluster, conf string) string {
+	if authBasicPattern.MatchString(conf) {
+		return conf
+	}
+	realm := m.Spec.BasicAuth.Realm
+	if realm == "" {
+		realm = defaultBasicAuthRealm
+	}
+	return injectHTTPDirectives(conf, []string{
+		`auth_basic "` + realm + `";`,
+		"auth_basic_user_file " + basicAuthDir + "/" + basicAuthKey + ";",
+	})
+}
+
+// addBasicAuth mounts the htpasswd file of spec.basicAuth into basicAuthDir
+// of the nginx container. The directory is mounted rather than the file, so
+// updates of the Secret reach the pods.
+func addBasicAuth(podSpec *corev1.PodSpec, m *nginxv1.NginxCluster) {
+	if m.Spec.BasicAuth == nil {
+		return
+	}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: basicAuthVolumeName,
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{
+			SecretName: m.Spec.BasicAuth.SecretName,
+			Items:      []corev1.KeyToPath{{Key: basicAuthKey, Path: basicAuthKey}},
+		}},
+	})
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      basicAuthVolumeName,
+		MountPath: basicAuthDir,
+		ReadOnly:  true,
+	})
+}
+
+// basicAuthSecretCondition reports the missing htpasswd Secret
+func basicAuthSecretCondition(m *nginxv1.NginxCluster) metav1.Condition {
+	return metav1.Condition{
+		Type:               nginxv1.ConditionDegraded,
+		Status:             metav1.ConditionTrue,
+		Reason:             "BasicAuthSecretMissing",
+		Message:            fmt.Sprintf("Rollout held, the pods could not mount Secret %s with the htpasswd file of basicAuth", m.Spec.BasicAuth.SecretName),
+		ObservedGeneration: m.Generation,
+	}
+}
+
+// holdForMissingBasicAuthSecret checks that the Secret of spec.basicAuth
+// exists. If it doesn't, it sets the Degraded condition and reports that
+// the running pods have to be kept: new ones would not start without the
+// Secret. The cluster is reconciled again once the Secret is created.
+func (r *NginxClusterReconciler) holdForMissingBasicAuthSecret(ctx context.Context, m *nginxv1.NginxCluster) (bool, error) {
+	if m.Spec.BasicAuth == nil {
+		return false, nil
+	}
+	err := r.Get(ctx, types.NamespacedName{Name: m.Spec.BasicAuth.SecretName, Namespace: workloadNamespace(m)}, &corev1.Secret{})
+	if !errors.IsNotFound(err) {
+		return false, err
+	}
+	degraded := basicAuthSecretCondition(m)
+	if cond := meta.FindStatusCondition(m.Status.Conditions, nginxv1.ConditionDegraded); cond == nil || cond.Message != degraded.Message {
+		r.recordEvent(m, corev1.EventTypeWarning, degraded.Reason, degraded.Message)
+	}
+	return true, r.updateStatusWithRetry(ctx, m, func() {
+		meta.SetStatusCondition(&m.Status.Conditions, degraded)
+	})
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+func TestEffectiveNginxConfBasicAuth(t *testing.T) {
+	m := newTestNginxCluster("basic-auth")
+	m.Spec.NginxConf = ""
+	m.Spec.BasicAuth = &nginxv1.BasicAuthSpec{SecretName: "staging-users"}
+	conf := effectiveNginxConf(m)
+	want := "http {\n    auth_basic \"Restricted\";\n    auth_basic_user_file /etc/nginx/auth/htpasswd;\n"
+	if !strings.Contains(conf, want) {
+		t.Fatalf("auth_basic directives missing from the http block:\n%s", conf)
+	}
+
+	// Configs setting auth_basic themselves are left alone
+	m.Spec.NginxConf = "events {}\nhttp {\n    server {\n        auth_basic off;\n    }\n}\n"
+	if got := effectiveNginxConf(m); strings.Contains(got, "auth_basic_user_file") {
+		t.Fatalf("auth_basic merged into a config that sets it:\n%s", got)
+	}
+}
+
+func TestDeploymentMountsBasicAuthSecret(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("basic-auth")
+	m.Spec.BasicAuth = &nginxv1.BasicAuthSpec{SecretName: "staging-users", Realm: "Staging"}
+	dep := r.deploymentForNginxCluster(m, "hash")
+	volumes := dep.Spec.Template.Spec.Volumes
+	if v := volumes[len(volumes)-1]; v.Secret == nil || v.Secret.SecretName != "staging-users" {
+		t.Fatalf("unexpected basic auth volume %+v", v)
+	}
+	mounts := dep.Spec.Template.Spec.Containers[0].VolumeMounts
+	if mount := mounts[len(mounts)-1]; mount.MountPath != "/etc/nginx/auth" || mount.SubPath != "" {
+		t.Fatalf("unexpected basic auth mount %+v", mount)
+	}
+	if got := indexConfigDependencies(m); len(got) != 1 || got[0] != "Secret/default/staging-users" {
+		t.Fatalf("index values = %v", got)
+	}
+}
+
+func TestReconcileHoldsForBasicAuthSecret(t *testing.T) {
+	requireEnvtest(t)
+	ctx := context.Background()
+
+	m := newTestNginxCluster("basic-auth-hold")
+	m.Spec.BasicAuth = &nginxv1.BasicAuthSpec{SecretName: "basic-auth-hold-users"}
+	createTestNginxCluster(t, m)
+	key := client.ObjectKeyFromObject(m)
+	eventually(t, func() error {
+		if err := k8sClient.Get(ctx, key, m); err != nil {
+			return err
+		}
+		if cond := meta.FindStatusCondition(m.Status.Conditions, nginxv1.ConditionDegraded); cond == nil || cond.Reason != "BasicAuthSecretMissing" {
+			return fmt.Errorf("Degraded condition not set: %+v", cond)
+		}
+		return nil
+	})
+	if err := k8sClient.Get(ctx, key, &appsv1.Deployment{}); err == nil {
+		t.Fatalf("Deployment created without the htpasswd Secret")
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "basic-auth-hold-users", Namespace: m.Namespace},
+		Data:       map[string][]byte{"htpasswd": []byte("user:$apr1$abc$def\n")},
+	}
+	if err := k8sClient.Create(ctx, secret); err != nil {
+		t.Fatalf("failed to create Secret: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = k8sClient.Delete(context.Background(), secret)
+	})
+	eventually(t, func() error {
+		return k8sClient.Get(ctx, key, &appsv1.Deployment{})
+	})
+}
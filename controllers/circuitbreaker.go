@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// circuitBreakerRestartThreshold is the number of container restarts of a
+// pod created by a rollout after which the rollout is paused
+const circuitBreakerRestartThreshold = 3
+
+// rolloutCircuitCondition evaluates the rollout circuit breaker. While a
+// rollout is in progress, a pod of the new ReplicaSet restarting repeatedly,
+// e.g. because it keeps failing its liveness probe, opens the circuit and the
+// Deployment is paused so the breakage doesn't spread. The circuit stays open
+// until the spec changes, typically with a fixed config. sets are the
+// Deployment's ReplicaSets, newest first.
+func (r *NginxClusterReconciler) rolloutCircuitCondition(ctx context.Context, m *nginxv1.NginxCluster, sets []appsv1.ReplicaSet) (metav1.Condition, error) {
+	if cond := meta.FindStatusCondition(m.Status.Conditions, nginxv1.ConditionRolloutCircuitOpen); cond != nil &&
+		cond.Status == metav1.ConditionTrue && cond.ObservedGeneration == m.Generation {
+		return *cond, nil
+	}
+	closed := metav1.Condition{
+		Type:               nginxv1.ConditionRolloutCircuitOpen,
+		Status:             metav1.ConditionFalse,
+		Reason:             "RolloutHealthy",
+		Message:            "Pods created by the rollout are not restarting",
+		ObservedGeneration: m.Generation,
+	}
+	if !rolloutInProgress(sets) {
+		return closed, nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(workloadNamespace(m)), client.MatchingLabels(sets[0].Spec.Selector.MatchLabels)); err != nil {
+		return closed, err
+	}
+	for i := range pods.Items {
+		if restarts := podRestarts(&pods.Items[i]); restarts >= circuitBreakerRestartThreshold {
+			return metav1.Condition{
+				Type:               nginxv1.ConditionRolloutCircuitOpen,
+				Status:             metav1.ConditionTrue,
+				Reason:             "NewPodsRestarting",
+				Message:            fmt.Sprintf("Pod %s of the rollout restarted %d times; the Deployment is paused until the NginxCluster spec changes", pods.Items[i].Name, restarts),
+				ObservedGeneration: m.Generation,
+			}, nil
+		}
+	}
+	return closed, nil
+}
+
+// rolloutInProgress reports whether pods of older ReplicaSets are still
+// running next to the newest one
+func rolloutInProgress(sets []appsv1.ReplicaSet) bool {
+	if len(sets) == 0 {
+		return false
+	}
+	for _, rs := range sets[1:] {
+		if rs.Status.Replicas > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// podRestarts returns the highest restart count among the pod's containers
+func podRestarts(pod *corev1.Pod) int32 {
+	var restarts int32
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.RestartCount > restarts {
+			restarts = status.RestartCount
+		}
+	}
+	return restarts
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+func TestRolloutInProgress(t *testing.T) {
+	newRS := appsv1.ReplicaSet{Status: appsv1.ReplicaSetStatus{Replicas: 2}}
+	oldRS := appsv1.ReplicaSet{}
+	if rolloutInProgress(nil) || rolloutInProgress([]appsv1.ReplicaSet{newRS, oldRS}) {
+		t.Fatalf("expected no rollout once the old ReplicaSets are scaled down")
+	}
+	oldRS.Status.Replicas = 1
+	if !rolloutInProgress([]appsv1.ReplicaSet{newRS, oldRS}) {
+		t.Fatalf("expected a rollout while old pods are running")
+	}
+}
+
+func TestPodRestarts(t *testing.T) {
+	pod := &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+		{Name: "nginx", RestartCount: 4},
+		{Name: "sidecar", RestartCount: 1},
+	}}}
+	if got := podRestarts(pod); got != 4 {
+		t.Fatalf("podRestarts() = %d, want 4", got)
+	}
+}
+
+func TestRolloutCircuitStaysOpen(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("circuit")
+	m.Generation = 2
+	open := metav1.Condition{
+		Type:               nginxv1.ConditionRolloutCircuitOpen,
+		Status:             metav1.ConditionTrue,
+		Reason:             "NewPodsRestarting",
+		ObservedGeneration: 2,
+	}
+	m.Status.Conditions = []metav1.Condition{open}
+
+	// No ReplicaSets are rolling out, but the circuit only closes with a new
+	// generation
+	cond, err := r.rolloutCircuitCondition(context.Background(), m, nil)
+	if err != nil || cond.Status != metav1.ConditionTrue {
+		t.Fatalf("expected the circuit to stay open, got %+v, %v", cond, err)
+	}
+
+	m.Generation = 3
+	cond, err = r.rolloutCircuitCondition(context.Background(), m, nil)
+	if err != nil || cond.Status != metav1.ConditionFalse {
+		t.Fatalf("expected a spec change to close the circuit, got %+v, %v", cond, err)
+	}
+}
+
+func TestRolloutPausedByCircuit(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("circuit-paused")
+	dep := r.deploymentForNginxCluster(m, "hash")
+	dep.Spec.Paused = true
+	if cond := rolloutPausedCondition(m, dep); cond.Status != metav1.ConditionTrue || cond.Reason != "RolloutCircuitOpen" {
+		t.Fatalf("expected RolloutPaused=True by the circuit breaker, got %+v", cond)
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// DefaultClientQPS and DefaultClientBurst are the client-side rate limits
+	// controller-runtime applies to the API server client, the ones of the
+	// Kubernetes controller manager
+	DefaultClientQPS   = 20
+	DefaultClientBurst = 30
+)
+
+// WithClientRateLimits returns a copy of cfg whose clients send at most qps
+// requests per second to the API server, with bursts of up to burst
+// requests. A negative qps disables client-side rate limiting, leaving
+// throttling to the API server's priority and fairness. A qps of zero is
+// rejected, as client-go would replace it with its own lower default.
+func WithClientRateLimits(cfg *rest.Config, qps float32, burst int) (*rest.Config, error) {
+	if qps == 0 {
+		return nil, fmt.Errorf("client QPS must not be 0, use -1 to disable client-side rate limiting")
+	}
+	if qps > 0 && float32(burst) < qps {
+		return nil, fmt.Errorf("client burst %d is lower than the QPS %v", burst, qps)
+	}
+	cfg = rest.CopyConfig(cfg)
+	cfg.QPS = qps
+	cfg.Burst = burst
+	return cfg, nil
+}
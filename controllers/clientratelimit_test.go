@@ -0,0 +1,56 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+// TestWithClientRateLimits checks the limits behind the sizing the README
+// recommends for fleets of hundreds of NginxClusters. Reads are served from
+// the informer cache, so a reconcile only spends the rate limit on writes:
+// the status update and, when something changed, a resource update and an
+// event, about three requests. After an operator restart or an image bump
+// across 500 clusters that is some 1500 requests: at the default 20 QPS the
+// last cluster is reconciled after about 75s, at 100 QPS with a burst of 200
+// after about 13s.
+func TestWithClientRateLimits(t *testing.T) {
+	base := &rest.Config{Host: "https://example.com", QPS: DefaultClientQPS, Burst: DefaultClientBurst}
+	cfg, err := WithClientRateLimits(base, 100, 200)
+	if err != nil {
+		t.Fatalf("WithClientRateLimits() error = %v", err)
+	}
+	if cfg.QPS != 100 || cfg.Burst != 200 || cfg.Host != base.Host {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+	if base.QPS != DefaultClientQPS {
+		t.Fatalf("base config modified")
+	}
+
+	if _, err := WithClientRateLimits(base, 100, 50); err == nil {
+		t.Fatalf("expected a burst lower than the QPS to be rejected")
+	}
+	// client-go would silently fall back to 5 QPS
+	if _, err := WithClientRateLimits(base, 0, 10); err == nil {
+		t.Fatalf("expected a QPS of 0 to be rejected")
+	}
+	if _, err := WithClientRateLimits(base, -1, 0); err != nil {
+		t.Fatalf("disabling rate limiting rejected: %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// configDependenciesIndex indexes NginxClusters by the Secrets and ConfigMaps
+// listed in spec.configDependencies.
+const configDependenciesIndex = ".spec.configDependencies"
+
+// withConfigDependencies folds the content of the cluster's config
+// dependencies into configHash. Clusters without dependencies keep the plain
+// config hash, so adding the field doesn't roll existing clusters.
+func (r *NginxClusterReconciler) withConfigDependencies(ctx context.Context, m *nginxv1.NginxCluster, configHash string) (string, error) {
+	if len(m.Spec.ConfigDependencies) == 0 {
+		return configHash, nil
+	}
+	var b strings.Builder
+	b.WriteString(configHash)
+	for _, dep := range m.Spec.ConfigDependencies {
+		key := types.NamespacedName{Name: dep.Name, Namespace: workloadNamespace(m)}
+		var data map[string][]byte
+		switch dep.Kind {
+		case "Secret":
+			secret := &corev1.Secret{}
+			if err := r.Get(ctx, key, secret); err != nil {
+				return "", err
+			}
+			data = secret.Data
+		case "ConfigMap":
+			cm := &corev1.ConfigMap{}
+			if err := r.Get(ctx, key, cm); err != nil {
+				return "", err
+			}
+			data = configMapData(cm)
+		default:
+			return "", fmt.Errorf("unsupported config dependency kind %q", dep.Kind)
+		}
+		fmt.Fprintf(&b, "\n%s/%s:%s", dep.Kind, dep.Name, hashDependencyData(data))
+	}
+	return calculateConfigHash(b.String()), nil
+}
+
+// configMapData merges the text and binary data of a ConfigMap
+func configMapData(cm *corev1.ConfigMap) map[string][]byte {
+	data := make(map[string][]byte, len(cm.Data)+len(cm.BinaryData))
+	for k, v := range cm.Data {
+		data[k] = []byte(v)
+	}
+	for k, v := range cm.BinaryData {
+		data[k] = v
+	}
+	return data
+}
+
+// hashDependencyData hashes the content of a dependency. Only the data counts:
+// metadata updates, such as a relabel, bump the resource version but don't
+// warrant a rollout.
+func hashDependencyData(data map[string][]byte) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s=%s\n", k, calculateConfigHash(string(data[k])))
+	}
+	return calculateConfigHash(b.String())
+}
+
+// indexConfigDependencies extracts the dependencies for the field index as
+// kind/namespace/name. The htpasswd Secret of spec.basicAuth is indexed too,
+// so creating it resumes a rollout held for it.
+func indexConfigDependencies(obj client.Object) []string {
+	m := obj.(*nginxv1.NginxCluster)
+	var keys []string
+	for _, dep := range m.Spec.ConfigDependencies {
+		keys = append(keys, dep.Kind+"/"+workloadNamespace(m)+"/"+dep.Name)
+	}
+	if m.Spec.BasicAuth != nil {
+		keys = append(keys, "Secret/"+workloadNamespace(m)+"/"+m.Spec.BasicAuth.SecretName)
+	}
+	return keys
+}
+
+// requestsForConfigDependency returns a map function enqueueing the clusters
+// that depend on an object of the given kind.
+func (r *NginxClusterReconciler) requestsForConfigDependency(kind string) func(context.Context, client.Object) []reconcile.Request {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		clusters := &nginxv1.NginxClusterList{}
+		if err := r.List(ctx, clusters, client.MatchingFields{configDependenciesIndex: kind + "/" + obj.GetNamespace() + "/" + obj.GetName()}); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to list NginxClusters depending on "+kind, "Namespace", obj.GetNamespace(), "Name", obj.GetName())
+			return nil
+		}
+		var reqs []reconcile.Request
+		for _, m := range clusters.Items {
+			reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: m.Name, Namespace: m.Namespace}})
+		}
+		return reqs
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+func TestHashDependencyData(t *testing.T) {
+	hash := hashDependencyData(map[string][]byte{"tls.crt": []byte("old"), "tls.key": []byte("key")})
+	if got := hashDependencyData(map[string][]byte{"tls.key": []byte("key"), "tls.crt": []byte("old")}); got != hash {
+		t.Errorf("hash depends on key order: %q != %q", got, hash)
+	}
+	if hashDependencyData(map[string][]byte{"tls.crt": []byte("new"), "tls.key": []byte("key")}) == hash {
+		t.Errorf("expected rotating a value to change the hash")
+	}
+	if hashDependencyData(map[string][]byte{"tls.crt": []byte("old")}) == hash {
+		t.Errorf("expected removing a key to change the hash")
+	}
+}
+
+func TestWithConfigDependenciesNone(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("no-deps")
+	got, err := r.withConfigDependencies(context.Background(), m, "abc")
+	if err != nil || got != "abc" {
+		t.Fatalf("withConfigDependencies() = %q, %v; want the plain hash", got, err)
+	}
+}
+
+func TestIndexConfigDependencies(t *testing.T) {
+	m := newTestNginxCluster("deps-index")
+	m.Spec.ConfigDependencies = []nginxv1.ObjectRef{{Kind: "Secret", Name: "tls"}, {Kind: "ConfigMap", Name: "upstreams"}}
+	got := indexConfigDependencies(m)
+	if len(got) != 2 || got[0] != "Secret/default/tls" || got[1] != "ConfigMap/default/upstreams" {
+		t.Fatalf("index values = %v", got)
+	}
+}
+
+func TestReconcileConfigDependencyRollsPods(t *testing.T) {
+	requireEnvtest(t)
+	ctx := context.Background()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "deps-rollout-tls", Namespace: "default"},
+		Data:       map[string][]byte{"tls.crt": []byte("old")},
+	}
+	if err := k8sClient.Create(ctx, secret); err != nil {
+		t.Fatalf("failed to create Secret: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = k8sClient.Delete(context.Background(), secret)
+	})
+
+	m := newTestNginxCluster("deps-rollout")
+	m.Spec.ConfigDependencies = []nginxv1.ObjectRef{{Kind: "Secret", Name: secret.Name}}
+	createTestNginxCluster(t, m)
+	key := client.ObjectKeyFromObject(m)
+
+	var oldHash string
+	eventually(t, func() error {
+		dep := &appsv1.Deployment{}
+		if err := k8sClient.Get(ctx, key, dep); err != nil {
+			return err
+		}
+		oldHash = dep.Spec.Template.Annotations["config-hash"]
+		if oldHash == "" || oldHash == calculateConfigHash(m.Spec.NginxConf) {
+			return fmt.Errorf("pod template config-hash %q doesn't include the Secret", oldHash)
+		}
+		return nil
+	})
+
+	eventually(t, func() error {
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(secret), secret); err != nil {
+			return err
+		}
+		secret.Data["tls.crt"] = []byte("new")
+		return k8sClient.Update(ctx, secret)
+	})
+
+	eventually(t, func() error {
+		dep := &appsv1.Deployment{}
+		if err := k8sClient.Get(ctx, key, dep); err != nil {
+			return err
+		}
+		if got := dep.Spec.Template.Annotations["config-hash"]; got == oldHash {
+			return fmt.Errorf("pod template config-hash unchanged after rotating the Secret")
+		}
+		if dep.Spec.Template.Annotations["restartedAt"] == "" {
+			return fmt.Errorf("pod template restartedAt annotation not set")
+		}
+		return nil
+	})
+}
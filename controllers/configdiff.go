@@ -0,0 +1,43 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+
+	"github.com/example/nginx-operator/internal/diff"
+)
+
+const (
+	// configDiffContext is the number of unchanged lines shown around a change
+	configDiffContext = 2
+
+	// maxConfigDiffBytes caps the diff in the ConfigChanged event, below the
+	// 1024 bytes event messages are limited to
+	maxConfigDiffBytes = 900
+)
+
+// configDiffMessage returns the event message describing a config change
+// from oldConf to newConf. Long diffs are cut at a line boundary.
+func configDiffMessage(oldConf, newConf string) string {
+	d := diff.Unified("nginx.conf", "nginx.conf", oldConf, newConf, configDiffContext)
+	if len(d) <= maxConfigDiffBytes {
+		return "Configuration changed:\n" + d
+	}
+	cut := strings.LastIndexByte(d[:maxConfigDiffBytes], '\n') + 1
+	return "Configuration changed:\n" + d[:cut] + "... (diff truncated)\n"
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestConfigDiffMessage(t *testing.T) {
+	msg := configDiffMessage("http {\n    listen 80;\n}\n", "http {\n    listen 8080;\n}\n")
+	for _, want := range []string{"-    listen 80;\n", "+    listen 8080;\n"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in the message, got %q", want, msg)
+		}
+	}
+}
+
+func TestConfigDiffMessageTruncated(t *testing.T) {
+	var newConf strings.Builder
+	for i := 0; i < 100; i++ {
+		fmt.Fprintf(&newConf, "location /path-%d { return 200; }\n", i)
+	}
+	msg := configDiffMessage("", newConf.String())
+	if len(msg) > 1024 {
+		t.Fatalf("message of %d bytes exceeds the event limit", len(msg))
+	}
+	if !strings.HasSuffix(msg, "}\n... (diff truncated)\n") {
+		t.Fatalf("expected the diff to be cut at a line boundary, got %q", msg[len(msg)-60:])
+	}
+}
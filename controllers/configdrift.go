@@ -0,0 +1,59 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// configDriftCondition reports whether the pods not being deleted were
+// started with configHash, which their config-hash annotation, copied from
+// the pod template, records
+func configDriftCondition(m *nginxv1.NginxCluster, pods []corev1.Pod, configHash string, rollback *nginxv1.RollbackStatus) metav1.Condition {
+	running, stale := 0, 0
+	for i := range pods {
+		if pods[i].DeletionTimestamp != nil {
+			continue
+		}
+		running++
+		if pods[i].Annotations["config-hash"] != configHash {
+			stale++
+		}
+	}
+	cond := metav1.Condition{
+		Type:               nginxv1.ConditionConfigDrift,
+		Status:             metav1.ConditionFalse,
+		Reason:             "ConfigInSync",
+		Message:            "All pods run the configuration of the spec",
+		ObservedGeneration: m.Generation,
+	}
+	if stale == 0 {
+		return cond
+	}
+	cond.Status = metav1.ConditionTrue
+	cond.Reason = "RestartPending"
+	if rollback != nil {
+		cond.Reason = "RolledBack"
+	}
+	cond.Message = fmt.Sprintf("%d of %d pods run another configuration than the spec", stale, running)
+	return cond
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+func TestConfigDriftCondition(t *testing.T) {
+	m := newTestNginxCluster("config-drift")
+	pod := func(hash string, deleting bool) corev1.Pod {
+		p := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"config-hash": hash}}}
+		if deleting {
+			p.DeletionTimestamp = &metav1.Time{}
+		}
+		return p
+	}
+
+	if cond := configDriftCondition(m, nil, "new", nil); cond.Status != metav1.ConditionFalse {
+		t.Fatalf("expected no drift without pods, got %+v", cond)
+	}
+	// Terminating pods of the previous rollout don't count
+	if cond := configDriftCondition(m, []corev1.Pod{pod("new", false), pod("old", true)}, "new", nil); cond.Status != metav1.ConditionFalse {
+		t.Fatalf("expected no drift, got %+v", cond)
+	}
+
+	pods := []corev1.Pod{pod("new", false), pod("old", false)}
+	cond := configDriftCondition(m, pods, "new", nil)
+	if cond.Status != metav1.ConditionTrue || cond.Reason != "RestartPending" || cond.Message != "1 of 2 pods run another configuration than the spec" {
+		t.Fatalf("unexpected condition %+v", cond)
+	}
+	if cond := configDriftCondition(m, pods, "new", &nginxv1.RollbackStatus{Revision: 2}); cond.Reason != "RolledBack" {
+		t.Fatalf("expected the rollback to be the reason, got %+v", cond)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// manageWorkload reports whether the operator runs nginx itself, which
+// spec.manageWorkload turns off
+func manageWorkload(m *nginxv1.NginxCluster) bool {
+	return m.Spec.ManageWorkload == nil || *m.Spec.ManageWorkload
+}
+
+// reconcileConfigOnly finishes the reconcile of a cluster whose Deployment is
+// managed elsewhere, once its ConfigMap is up to date. Config changes restart
+// the pods of spec.externalDeployment, if set, through the same annotations
+// as the operator's own rollouts; status reports that Deployment.
+func (r *NginxClusterReconciler) reconcileConfigOnly(ctx context.Context, m *nginxv1.NginxCluster, configHash, configMapResourceVersion, effectiveConfigConfigMap string, configPropagationWait time.Duration) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var dep *appsv1.Deployment
+	if name := m.Spec.ExternalDeployment; name != "" {
+		dep = &appsv1.Deployment{}
+		err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: workloadNamespace(m)}, dep)
+		if apierrors.IsNotFound(err) {
+			logger.Info("External Deployment not found", "Deployment.Namespace", workloadNamespace(m), "Deployment.Name", name)
+			dep = nil
+		} else if err != nil {
+			logger.Error(err, "Failed to get external Deployment", "Deployment.Name", name)
+			return ctrl.Result{}, err
+		}
+	}
+
+	if dep != nil && dep.Spec.Template.Annotations["config-hash"] != configHash {
+		if configPropagationWait > 0 {
+			logger.Info("Configuration changed, waiting for the ConfigMap to propagate before restarting", "After", configPropagationWait)
+			return ctrl.Result{RequeueAfter: configPropagationWait}, nil
+		}
+		logger.Info("Configuration changed, restarting the pods of the external Deployment", "Deployment.Namespace", dep.Namespace, "Deployment.Name", dep.Name)
+		restartedAt := time.Now().Format(time.RFC3339)
+		if err := r.updateWithRetry(ctx, dep, func() {
+			setConfigRolloutAnnotations(&dep.Spec.Template, m, configHash, restartedAt)
+		}); err != nil {
+			logger.Error(err, "Failed to update external Deployment for config change")
+			return ctrl.Result{}, err
+		}
+		r.recordEvent(m, corev1.EventTypeNormal, "RestartedExternalDeployment", fmt.Sprintf("Restarting the pods of Deployment %s for config %s", dep.Name, configHash))
+	}
+
+	now := metav1.Now()
+	err := r.updateStatusWithRetry(ctx, m, func() {
+		m.Status.ConfigOnly = true
+		m.Status.ConfigHash = configHash
+		m.Status.ConfigMapResourceVersion = configMapResourceVersion
+		m.Status.EffectiveConfigConfigMap = effectiveConfigConfigMap
+		m.Status.ObservedGeneration = m.Generation
+		m.Status.LastUpdateTime = &now
+		var status appsv1.DeploymentStatus
+		if dep != nil {
+			status = dep.Status
+		}
+		m.Status.Replicas = status.Replicas
+		m.Status.ReadyReplicas = status.ReadyReplicas
+		m.Status.UpdatedReplicas = status.UpdatedReplicas
+		m.Status.AvailableReplicas = status.AvailableReplicas
+	})
+	if err != nil {
+		logger.Error(err, "Failed to update NginxCluster status")
+	}
+	return ctrl.Result{}, err
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestValidateConfigOnly(t *testing.T) {
+	m := newTestNginxCluster("config-only")
+	m.Spec.ExternalDeployment = "helm-nginx"
+	if err := validateSpec(m); err == nil {
+		t.Fatalf("expected externalDeployment with a managed workload to be rejected")
+	}
+	disabled := false
+	m.Spec.ManageWorkload = &disabled
+	if err := validateSpec(m); err != nil {
+		t.Fatalf("validateSpec() error = %v", err)
+	}
+	if isConverged(m) {
+		t.Fatalf("clusters without a managed workload are reconciled every time")
+	}
+}
+
+func TestReconcileConfigOnly(t *testing.T) {
+	requireEnvtest(t)
+	ctx := context.Background()
+
+	m := newTestNginxCluster("config-only")
+	disabled := false
+	m.Spec.ManageWorkload = &disabled
+	m.Spec.ExternalDeployment = "helm-nginx"
+	external := preexistingDeployment(m)
+	external.Name = "helm-nginx"
+	if err := k8sClient.Create(ctx, external); err != nil {
+		t.Fatalf("failed to create Deployment: %v", err)
+	}
+	createTestNginxCluster(t, m)
+
+	eventually(t, func() error {
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(m), m); err != nil {
+			return err
+		}
+		if !m.Status.ConfigOnly || m.Status.ConfigHash == "" {
+			return fmt.Errorf("status not in config-only mode: %+v", m.Status)
+		}
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(external), external); err != nil {
+			return err
+		}
+		if got := external.Spec.Template.Annotations["config-hash"]; got != m.Status.ConfigHash {
+			return fmt.Errorf("external Deployment has config-hash %q, want %q", got, m.Status.ConfigHash)
+		}
+		return nil
+	})
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: m.Name + configMapNameSuffix, Namespace: m.Namespace}, &corev1.ConfigMap{}); err != nil {
+		t.Fatalf("ConfigMap not reconciled: %v", err)
+	}
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(m), &appsv1.Deployment{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected no Deployment, got %v", err)
+	}
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(m), &corev1.Service{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected no Service, got %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// configUpdatedAtAnnotation records on the generated ConfigMap when its
+// nginx.conf was last written
+const configUpdatedAtAnnotation = "nginx.example.com/config-updated-at"
+
+// DefaultConfigPropagationDelay is the default ConfigPropagationDelay.
+//
+// The kubelet reads ConfigMaps through a watch-based cache, which can lag
+// the API server by a few seconds, so pods started right after an update may
+// still mount the previous nginx.conf. Pods that are already running only see
+// the update after the kubelet sync period (--sync-frequency, one minute by
+// default), which is why config changes are rolled out with new pods rather
+// than picked up in place.
+const DefaultConfigPropagationDelay = 5 * time.Second
+
+// configPropagationWait returns how long to wait before rolling out the config
+// of cm, so the new pods don't start with stale content
+func (r *NginxClusterReconciler) configPropagationWait(cm *corev1.ConfigMap, now time.Time) time.Duration {
+	if r.ConfigPropagationDelay <= 0 {
+		return 0
+	}
+	updatedAt, err := time.Parse(time.RFC3339, cm.Annotations[configUpdatedAtAnnotation])
+	if err != nil {
+		return 0
+	}
+	if wait := updatedAt.Add(r.ConfigPropagationDelay).Sub(now); wait > 0 {
+		return wait
+	}
+	return 0
+}
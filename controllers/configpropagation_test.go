@@ -0,0 +1,51 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConfigPropagationWait(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		configUpdatedAtAnnotation: now.Add(-2 * time.Second).Format(time.RFC3339),
+	}}}
+
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	if wait := r.configPropagationWait(cm, now); wait != 0 {
+		t.Fatalf("expected no wait without a propagation delay, got %s", wait)
+	}
+
+	r.ConfigPropagationDelay = 5 * time.Second
+	if wait := r.configPropagationWait(cm, now); wait != 3*time.Second {
+		t.Fatalf("configPropagationWait() = %s, want 3s", wait)
+	}
+	if wait := r.configPropagationWait(cm, now.Add(time.Minute)); wait != 0 {
+		t.Fatalf("expected no wait once the delay passed, got %s", wait)
+	}
+
+	// ConfigMaps written before the annotation existed are not held back
+	delete(cm.Annotations, configUpdatedAtAnnotation)
+	if wait := r.configPropagationWait(cm, now); wait != 0 {
+		t.Fatalf("expected no wait without an update time, got %s", wait)
+	}
+}
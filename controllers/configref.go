@@ -0,0 +1,122 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// nginxConfFromIndex indexes NginxClusters by the name of the ConfigMap
+// referenced in spec.nginxConfFrom or spec.configTemplateFrom.
+const nginxConfFromIndex = ".spec.nginxConfFrom.name"
+
+// externalNginxConf returns the nginx configuration from the ConfigMap key
+// referenced by spec.nginxConfFrom, along with the ConfigMap's resource version.
+func (r *NginxClusterReconciler) externalNginxConf(ctx context.Context, m *nginxv1.NginxCluster) (string, string, error) {
+	ref := m.Spec.NginxConfFrom
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: workloadNamespace(m)}, cm); err != nil {
+		return "", "", err
+	}
+	conf, ok := cm.Data[ref.Key]
+	if !ok {
+		return "", "", fmt.Errorf("key %q not found in ConfigMap %s/%s", ref.Key, cm.Namespace, cm.Name)
+	}
+	return conf, cm.ResourceVersion, nil
+}
+
+// deleteGeneratedConfigMap removes the ConfigMap the operator generates from
+// spec.nginxConf, if it exists.
+func (r *NginxClusterReconciler) deleteGeneratedConfigMap(ctx context.Context, m *nginxv1.NginxCluster) error {
+	cm := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Name: m.Name + configMapNameSuffix, Namespace: workloadNamespace(m)}, cm)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !r.isOwnedBy(cm, m) {
+		return nil
+	}
+	log.FromContext(ctx).Info("Deleting generated ConfigMap", "ConfigMap.Namespace", cm.Namespace, "ConfigMap.Name", cm.Name)
+	if err := r.Delete(ctx, cm); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// configVolumeSource returns the source of the nginx-config volume, mapping the
+// referenced key to nginx.conf when the configuration is external.
+func configVolumeSource(m *nginxv1.NginxCluster) corev1.VolumeSource {
+	if ref := m.Spec.NginxConfFrom; ref != nil {
+		return corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: ref.LocalObjectReference,
+				Items: []corev1.KeyToPath{{
+					Key:  ref.Key,
+					Path: "nginx.conf",
+				}},
+			},
+		}
+	}
+	return corev1.VolumeSource{
+		ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: m.Name + configMapNameSuffix,
+			},
+		},
+	}
+}
+
+// indexNginxConfFrom extracts the referenced ConfigMap for the field index. The
+// ConfigMap lives in the workload namespace, which can differ from the
+// cluster's, so the key is namespace/name.
+func indexNginxConfFrom(obj client.Object) []string {
+	m := obj.(*nginxv1.NginxCluster)
+	switch {
+	case m.Spec.NginxConfFrom != nil:
+		return []string{workloadNamespace(m) + "/" + m.Spec.NginxConfFrom.Name}
+	case m.Spec.ConfigTemplateFrom != nil:
+		return []string{workloadNamespace(m) + "/" + m.Spec.ConfigTemplateFrom.Name}
+	}
+	return nil
+}
+
+// requestsForReferencedConfigMap enqueues the clusters whose configuration or
+// config template is read from the given ConfigMap.
+func (r *NginxClusterReconciler) requestsForReferencedConfigMap(ctx context.Context, obj client.Object) []reconcile.Request {
+	clusters := &nginxv1.NginxClusterList{}
+	if err := r.List(ctx, clusters, client.MatchingFields{nginxConfFromIndex: obj.GetNamespace() + "/" + obj.GetName()}); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list NginxClusters referencing ConfigMap", "ConfigMap.Namespace", obj.GetNamespace(), "ConfigMap.Name", obj.GetName())
+		return nil
+	}
+	var reqs []reconcile.Request
+	for _, m := range clusters.Items {
+		reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: m.Name, Namespace: m.Namespace}})
+	}
+	return reqs
+}
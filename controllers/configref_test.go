@@ -0,0 +1,77 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestValidateConfigSource(t *testing.T) {
+	m := newTestNginxCluster("config-source")
+	if err := validateSpec(m); err != nil {
+		t.Fatalf("inline config rejected: %v", err)
+	}
+
+	m.Spec.NginxConfFrom = &corev1.ConfigMapKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "gitops-nginx"},
+		Key:                  "nginx.conf",
+	}
+	if err := validateSpec(m); err == nil {
+		t.Fatalf("expected nginxConf and nginxConfFrom together to be rejected")
+	}
+
+	m.Spec.NginxConf = ""
+	if err := validateSpec(m); err != nil {
+		t.Fatalf("referenced config rejected: %v", err)
+	}
+
+	m.Spec.NginxConfFrom = nil
+	m.Spec.ConfigProfiles = map[string]string{"staging": "events {}\n"}
+	m.Spec.ActiveProfile = "production"
+	if err := validateSpec(m); err == nil {
+		t.Fatalf("expected an unknown active profile to be rejected")
+	}
+	m.Spec.ActiveProfile = "staging"
+	if err := validateSpec(m); err != nil {
+		t.Fatalf("active profile rejected: %v", err)
+	}
+}
+
+func TestDeploymentMountsReferencedConfigMap(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("config-ref")
+	m.Spec.NginxConf = ""
+	m.Spec.NginxConfFrom = &corev1.ConfigMapKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "gitops-nginx"},
+		Key:                  "site.conf",
+	}
+
+	dep := r.deploymentForNginxCluster(m, "hash")
+	source := dep.Spec.Template.Spec.Volumes[0].ConfigMap
+	if source == nil || source.Name != "gitops-nginx" {
+		t.Fatalf("expected volume from ConfigMap gitops-nginx, got %+v", dep.Spec.Template.Spec.Volumes[0])
+	}
+	if len(source.Items) != 1 || source.Items[0].Key != "site.conf" || source.Items[0].Path != "nginx.conf" {
+		t.Fatalf("expected site.conf mapped to nginx.conf, got %+v", source.Items)
+	}
+
+	if got := indexNginxConfFrom(m); len(got) != 1 || got[0] != "default/gitops-nginx" {
+		t.Fatalf("index value = %v, want [default/gitops-nginx]", got)
+	}
+}
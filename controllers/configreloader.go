@@ -0,0 +1,118 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+const (
+	configReloaderContainerName = "config-reloader"
+	// configReloaderDir is where the config volume is mounted as a whole when
+	// the reloader runs. A subPath mount would not see ConfigMap updates.
+	configReloaderDir = "/etc/nginx/operator"
+	// configReloaderInterval is how often, in seconds, the reloader compares
+	// the mounted config with the one nginx last loaded
+	configReloaderInterval = "5"
+)
+
+// configReloaderScript sends SIGHUP to the nginx master process, found via
+// the shared process namespace, whenever nginx.conf changes. nginx checks
+// the new config on reload and keeps the old workers when it is invalid.
+const configReloaderScript = `conf=` + configReloaderDir + `/nginx.conf
+last=$(md5sum "$conf")
+while sleep ` + configReloaderInterval + `; do
+  current=$(md5sum "$conf")
+  [ "$current" = "$last" ] && continue
+  last=$current
+  for p in /proc/[0-9]*; do
+    case "$(tr '\0' ' ' < "$p/cmdline" 2>/dev/null)" in
+      "nginx: master process"*) echo "Config changed, reloading nginx"; kill -HUP "${p#/proc/}" ;;
+    esac
+  done
+done
+`
+
+// reloadedConfigHash replaces the hash of nginx.conf when config changes
+// are applied without a restart, so they don't change the pod template
+var reloadedConfigHash = calculateConfigHash(configReloaderContainerName)
+
+// restartOnConfigChange reports whether nginx.conf changes roll the pods,
+// which spec.restartOnConfigChange turns off
+func restartOnConfigChange(m *nginxv1.NginxCluster) bool {
+	return m.Spec.RestartOnConfigChange == nil || *m.Spec.RestartOnConfigChange
+}
+
+// reloadsConfig reports whether nginx.conf changes reach the pods without a
+// restart, applied by the reloader sidecar or by the user
+func reloadsConfig(m *nginxv1.NginxCluster) bool {
+	return m.Spec.ConfigReloaderSidecar || !restartOnConfigChange(m)
+}
+
+// reloadableConfigHash returns the hash recorded on the pod template for
+// configHash, the hash of nginx.conf: reloadedConfigHash when config changes
+// are applied without a restart, configHash otherwise
+func reloadableConfigHash(m *nginxv1.NginxCluster, configHash string) string {
+	if reloadsConfig(m) {
+		return reloadedConfigHash
+	}
+	return configHash
+}
+
+// addConfigReloader mounts the config directory instead of nginx.conf alone
+// and points nginx at it when config changes are applied without a restart.
+// With ConfigReloaderSidecar it adds the reloader sidecar, which runs the
+// nginx image, already on the node, and shares the process namespace of the
+// pod to signal nginx.
+func addConfigReloader(podSpec *corev1.PodSpec, m *nginxv1.NginxCluster) {
+	if !reloadsConfig(m) {
+		return
+	}
+	nginx := &podSpec.Containers[0]
+	for i := range nginx.VolumeMounts {
+		if mount := &nginx.VolumeMounts[i]; mount.Name == "nginx-config" {
+			mount.MountPath = configReloaderDir
+			mount.SubPath = ""
+		}
+	}
+	nginx.Args = []string{"nginx", "-c", configReloaderDir + "/nginx.conf", "-g", "daemon off;"}
+	if !m.Spec.ConfigReloaderSidecar {
+		return
+	}
+
+	shareProcessNamespace := true
+	podSpec.ShareProcessNamespace = &shareProcessNamespace
+	podSpec.Containers = append(podSpec.Containers, corev1.Container{
+		Name:    configReloaderContainerName,
+		Image:   imageForNginxCluster(m),
+		Command: []string{"/bin/sh", "-c", configReloaderScript},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("5m"),
+				corev1.ResourceMemory: resource.MustParse("8Mi"),
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{{
+			Name:      "nginx-config",
+			MountPath: configReloaderDir,
+			ReadOnly:  true,
+		}},
+	})
+}
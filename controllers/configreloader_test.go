@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDeploymentConfigReloader(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("reloader")
+	m.Spec.ConfigReloaderSidecar = true
+	spec := r.deploymentForNginxCluster(m, "hash").Spec.Template.Spec
+
+	if spec.ShareProcessNamespace == nil || !*spec.ShareProcessNamespace {
+		t.Fatalf("expected the process namespace to be shared")
+	}
+	if len(spec.Containers) != 2 || spec.Containers[1].Name != configReloaderContainerName || spec.Containers[1].Image != m.Spec.Image {
+		t.Fatalf("unexpected containers %+v", spec.Containers)
+	}
+	nginx := spec.Containers[0]
+	if mount := nginx.VolumeMounts[0]; mount.MountPath != configReloaderDir || mount.SubPath != "" {
+		t.Fatalf("expected the config directory to be mounted, got %+v", mount)
+	}
+	if len(nginx.Args) != 5 || nginx.Args[2] != "/etc/nginx/operator/nginx.conf" {
+		t.Fatalf("nginx not started with the mounted config: %v", nginx.Args)
+	}
+}
+
+func TestReloadableConfigHash(t *testing.T) {
+	m := newTestNginxCluster("reloader")
+	if got := reloadableConfigHash(m, "hash"); got != "hash" {
+		t.Fatalf("reloadableConfigHash() = %s without the reloader", got)
+	}
+	m.Spec.ConfigReloaderSidecar = true
+	if reloadableConfigHash(m, "old") != reloadableConfigHash(m, "new") {
+		t.Fatalf("config changes alter the pod template with the reloader")
+	}
+}
+
+func TestDeploymentWithoutRestartOnConfigChange(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("no-restart")
+	disabled := false
+	m.Spec.RestartOnConfigChange = &disabled
+
+	old := r.deploymentForNginxCluster(m, reloadableConfigHash(m, calculateConfigHash(m.Spec.NginxConf)))
+	changed := r.deploymentForNginxCluster(m, reloadableConfigHash(m, calculateConfigHash("http {}\n")))
+	if !reflect.DeepEqual(old.Spec.Template, changed.Spec.Template) {
+		t.Fatalf("config change altered the pod template:\n%+v\n%+v", old.Spec.Template, changed.Spec.Template)
+	}
+	spec := changed.Spec.Template.Spec
+	if len(spec.Containers) != 1 || spec.ShareProcessNamespace != nil {
+		t.Fatalf("reloader sidecar added without configReloaderSidecar: %+v", spec)
+	}
+	if mount := spec.Containers[0].VolumeMounts[0]; mount.MountPath != configReloaderDir || mount.SubPath != "" {
+		t.Fatalf("expected the config directory to be mounted so updates reach the pods, got %+v", mount)
+	}
+}
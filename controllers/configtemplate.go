@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// configTemplateError is returned for a config template that doesn't parse
+// or execute, which retrying won't fix
+type configTemplateError struct {
+	err error
+}
+
+func (e *configTemplateError) Error() string {
+	return "invalid config template: " + e.err.Error()
+}
+
+func (e *configTemplateError) Unwrap() error {
+	return e.err
+}
+
+// renderConfigTemplate executes the template referenced by
+// spec.configTemplateFrom with spec.templateValues, and the share of
+// spec.totalConnections as .WorkerConnections unless a value sets it
+func (r *NginxClusterReconciler) renderConfigTemplate(ctx context.Context, m *nginxv1.NginxCluster) (string, error) {
+	ref := m.Spec.ConfigTemplateFrom
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: workloadNamespace(m)}, cm); err != nil {
+		return "", err
+	}
+	text, ok := cm.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("key %q not found in ConfigMap %s/%s", ref.Key, cm.Namespace, cm.Name)
+	}
+	return executeConfigTemplate(cm.Name+"/"+ref.Key, text, templateValuesForNginxCluster(m))
+}
+
+// workerConnectionsValue is the template value set from spec.totalConnections
+const workerConnectionsValue = "WorkerConnections"
+
+// templateValuesForNginxCluster returns the values config templates are
+// executed with
+func templateValuesForNginxCluster(m *nginxv1.NginxCluster) map[string]string {
+	n := workerConnections(m)
+	if _, ok := m.Spec.TemplateValues[workerConnectionsValue]; n == 0 || ok {
+		return m.Spec.TemplateValues
+	}
+	values := make(map[string]string, len(m.Spec.TemplateValues)+1)
+	for k, v := range m.Spec.TemplateValues {
+		values[k] = v
+	}
+	values[workerConnectionsValue] = strconv.Itoa(int(n))
+	return values
+}
+
+// executeConfigTemplate renders a config template. Missing values are errors
+// rather than "<no value>" in nginx.conf.
+func executeConfigTemplate(name, text string, values map[string]string) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", &configTemplateError{err: err}
+	}
+	if values == nil {
+		values = map[string]string{}
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, values); err != nil {
+		return "", &configTemplateError{err: err}
+	}
+	return out.String(), nil
+}
+
+// configTemplateCondition reports a config template that failed to render
+func configTemplateCondition(m *nginxv1.NginxCluster, err error) metav1.Condition {
+	return metav1.Condition{
+		Type:               nginxv1.ConditionDegraded,
+		Status:             metav1.ConditionTrue,
+		Reason:             "ConfigTemplateFailed",
+		Message:            err.Error(),
+		ObservedGeneration: m.Generation,
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+func TestExecuteConfigTemplate(t *testing.T) {
+	text := "http { upstream app { server {{ .backend }}; } }\n"
+	got, err := executeConfigTemplate("tmpl", text, map[string]string{"backend": "app:8080"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "http { upstream app { server app:8080; } }\n"; got != want {
+		t.Fatalf("rendered %q, want %q", got, want)
+	}
+
+	var templateErr *configTemplateError
+	if _, err := executeConfigTemplate("tmpl", text, nil); !errors.As(err, &templateErr) {
+		t.Fatalf("expected a missing value to fail the rendering, got %v", err)
+	}
+	if _, err := executeConfigTemplate("tmpl", "{{ .backend ", nil); !errors.As(err, &templateErr) {
+		t.Fatalf("expected a parse error, got %v", err)
+	}
+}
+
+func TestTemplateValuesWorkerConnections(t *testing.T) {
+	m := newTestNginxCluster("template-worker-connections")
+	m.Spec.TemplateValues = map[string]string{"backend": "app:8080"}
+	if got := templateValuesForNginxCluster(m); len(got) != 1 {
+		t.Fatalf("unexpected values without totalConnections: %v", got)
+	}
+	m.Spec.TotalConnections = 1000
+	got := templateValuesForNginxCluster(m)
+	if got["WorkerConnections"] != "500" || got["backend"] != "app:8080" {
+		t.Fatalf("unexpected values %v", got)
+	}
+	if _, ok := m.Spec.TemplateValues["WorkerConnections"]; ok {
+		t.Fatalf("spec values modified")
+	}
+	// A value set in the spec wins
+	m.Spec.TemplateValues["WorkerConnections"] = "2048"
+	if got := templateValuesForNginxCluster(m); got["WorkerConnections"] != "2048" {
+		t.Fatalf("spec value overridden: %v", got)
+	}
+}
+
+func TestValidateConfigTemplateSource(t *testing.T) {
+	m := newTestNginxCluster("config-template-source")
+	m.Spec.ConfigTemplateFrom = &corev1.ConfigMapKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "nginx-template"},
+		Key:                  "nginx.conf.tmpl",
+	}
+	if err := validateSpec(m); err == nil {
+		t.Fatalf("expected nginxConf and configTemplateFrom together to be rejected")
+	}
+	m.Spec.NginxConf = ""
+	if err := validateSpec(m); err != nil {
+		t.Fatalf("config template rejected: %v", err)
+	}
+	if got := indexNginxConfFrom(m); len(got) != 1 || got[0] != "default/nginx-template" {
+		t.Fatalf("index value = %v, want [default/nginx-template]", got)
+	}
+}
+
+func TestReconcileRendersConfigTemplate(t *testing.T) {
+	requireEnvtest(t)
+	ctx := context.Background()
+
+	tmpl := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "config-template-source", Namespace: "default"},
+		Data:       map[string]string{"nginx.conf.tmpl": "events {}\nhttp { server { listen {{ .port }}; } }\n"},
+	}
+	if err := k8sClient.Create(ctx, tmpl); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = k8sClient.Delete(context.Background(), tmpl) })
+
+	m := newTestNginxCluster("config-template")
+	m.Spec.NginxConf = ""
+	m.Spec.ConfigTemplateFrom = &corev1.ConfigMapKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: tmpl.Name},
+		Key:                  "nginx.conf.tmpl",
+	}
+	m.Spec.TemplateValues = map[string]string{"port": "8080"}
+	createTestNginxCluster(t, m)
+
+	want := "events {}\nhttp { server { listen 8080; } }\n"
+	eventually(t, func() error {
+		cm := &corev1.ConfigMap{}
+		if err := k8sClient.Get(ctx, types.NamespacedName{Name: m.Name + configMapNameSuffix, Namespace: m.Namespace}, cm); err != nil {
+			return err
+		}
+		if cm.Data["nginx.conf"] != want {
+			return fmt.Errorf("ConfigMap holds %q, want %q", cm.Data["nginx.conf"], want)
+		}
+		return nil
+	})
+
+	// A template referencing a missing value degrades the cluster and keeps
+	// the rendered config
+	eventually(t, func() error {
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(tmpl), tmpl); err != nil {
+			return err
+		}
+		tmpl.Data["nginx.conf.tmpl"] = "events {}\nhttp { server { listen {{ .listen }}; } }\n"
+		return k8sClient.Update(ctx, tmpl)
+	})
+	eventually(t, func() error {
+		current := &nginxv1.NginxCluster{}
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(m), current); err != nil {
+			return err
+		}
+		cond := meta.FindStatusCondition(current.Status.Conditions, nginxv1.ConditionDegraded)
+		if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != "ConfigTemplateFailed" {
+			return fmt.Errorf("unexpected Degraded condition %+v", cond)
+		}
+		return nil
+	})
+	cm := &corev1.ConfigMap{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: m.Name + configMapNameSuffix, Namespace: m.Namespace}, cm); err != nil || cm.Data["nginx.conf"] != want {
+		t.Fatalf("expected the rendered config to be kept, got %q, %v", cm.Data["nginx.conf"], err)
+	}
+}
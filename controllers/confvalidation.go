@@ -0,0 +1,140 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// validateNginxConf checks the structure of conf the way nginx tokenizes it:
+// blocks must be balanced, quotes closed and the last directive terminated.
+// Directives and their arguments are not checked, only nginx -t in the
+// image could; the check catches the truncated and mis-nested configs that
+// would otherwise crash-loop the new pods.
+func validateNginxConf(conf string) error {
+	line, depth := 1, 0
+	var openLines []int
+	inToken, quote := false, byte(0)
+	quoteLine := 0
+	for i := 0; i < len(conf); i++ {
+		c := conf[i]
+		if c == '\n' {
+			line++
+		}
+		if quote != 0 {
+			switch c {
+			case '\\':
+				i++
+			case quote:
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '#':
+			for i < len(conf) && conf[i] != '\n' {
+				i++
+			}
+			i--
+		case '"', '\'':
+			quote, quoteLine, inToken = c, line, true
+		case '\\':
+			i++
+			inToken = true
+		case ';':
+			inToken = false
+		case '{':
+			// ${name} is a variable, not a block
+			if i > 0 && conf[i-1] == '$' {
+				for i < len(conf) && conf[i] != '}' {
+					i++
+				}
+				continue
+			}
+			depth++
+			openLines = append(openLines, line)
+			inToken = false
+		case '}':
+			if inToken {
+				return fmt.Errorf("line %d: unexpected \"}\", the previous directive is not terminated by \";\"", line)
+			}
+			if depth == 0 {
+				return fmt.Errorf("line %d: unexpected \"}\"", line)
+			}
+			depth--
+			openLines = openLines[:depth]
+		case ' ', '\t', '\r', '\n':
+		default:
+			inToken = true
+		}
+	}
+	switch {
+	case quote != 0:
+		return fmt.Errorf("line %d: unterminated quoted string", quoteLine)
+	case inToken:
+		return fmt.Errorf("line %d: unexpected end of file, expecting \";\" or \"}\"", line)
+	case depth > 0:
+		return fmt.Errorf("line %d: block is not closed by \"}\"", openLines[depth-1])
+	}
+	return nil
+}
+
+// invalidConfigCondition reports a config held back by the validation
+func invalidConfigCondition(m *nginxv1.NginxCluster, err error) metav1.Condition {
+	return metav1.Condition{
+		Type:               nginxv1.ConditionDegraded,
+		Status:             metav1.ConditionTrue,
+		Reason:             "InvalidConfig",
+		Message:            fmt.Sprintf("Rollout held, the nginx config is invalid: %v", err),
+		ObservedGeneration: m.Generation,
+	}
+}
+
+// holdForInvalidConfig validates conf as spec.configValidationMode asks. In
+// Strict mode, the default, an invalid config sets the Degraded condition
+// and has to be held back, so the pods keep running the last one rolled out.
+// In Warn mode it is only reported in an event.
+func (r *NginxClusterReconciler) holdForInvalidConfig(ctx context.Context, m *nginxv1.NginxCluster, conf string) (bool, error) {
+	mode := m.Spec.ConfigValidationMode
+	if mode == nginxv1.ConfigValidationOff {
+		return false, nil
+	}
+	err := validateNginxConf(conf)
+	if err == nil {
+		return false, nil
+	}
+	degraded := invalidConfigCondition(m, err)
+	if mode == nginxv1.ConfigValidationWarn {
+		log.FromContext(ctx).Info("Rolling out invalid nginx config", "Reason", err.Error())
+		r.recordEvent(m, corev1.EventTypeWarning, degraded.Reason, fmt.Sprintf("Rolling out an invalid nginx config: %v", err))
+		return false, nil
+	}
+	if cond := meta.FindStatusCondition(m.Status.Conditions, nginxv1.ConditionDegraded); cond == nil || cond.Message != degraded.Message {
+		r.recordEvent(m, corev1.EventTypeWarning, degraded.Reason, degraded.Message)
+	}
+	return true, r.updateStatusWithRetry(ctx, m, func() {
+		meta.SetStatusCondition(&m.Status.Conditions, degraded)
+	})
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+	"testing"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+func TestValidateNginxConf(t *testing.T) {
+	m := newTestNginxCluster("conf-validation")
+	m.Spec.NginxConf = ""
+	m.Spec.LogSampling = &nginxv1.LogSamplingSpec{Rate: "0.1"}
+	m.Spec.ErrorPages = map[string]string{"404": "<h1>Not found</h1>"}
+	for _, conf := range []string{
+		effectiveNginxConf(m),
+		getReverseProxyNginxConf([]string{"backend:8080"}),
+		"events {}\nhttp {\n    # a } in a comment\n    log_format main '$remote_addr \"${request_id}\" {';\n    server { return 200 \"ok;}\"; }\n}\n",
+	} {
+		if err := validateNginxConf(conf); err != nil {
+			t.Errorf("validateNginxConf() error = %v for\n%s", err, conf)
+		}
+	}
+
+	for conf, want := range map[string]string{
+		"events {}\nhttp {\n    server {}\n":     "line 2: block is not closed",
+		"events {}\n}\n":                         "line 2: unexpected \"}\"",
+		"http { server { listen 80 } }\n":        "line 1: unexpected \"}\", the previous directive",
+		"worker_processes 2":                     "unexpected end of file",
+		"http { server { return 200 \"ok; } }\n": "line 1: unterminated quoted string",
+	} {
+		if err := validateNginxConf(conf); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("validateNginxConf(%q) error = %v, want %q", conf, err, want)
+		}
+	}
+}
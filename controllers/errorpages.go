@@ -0,0 +1,157 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+const (
+	errorPagesConfigMapSuffix = "-error-pages"
+	errorPagesVolumeName      = "error-pages"
+	// errorPagesRoot is the document root of the image's default config.
+	// Each page is mounted on its own, leaving the other files in place.
+	errorPagesRoot = "/usr/share/nginx/html"
+)
+
+// errorPageCodes returns the status codes of spec.errorPages in order
+func errorPageCodes(m *nginxv1.NginxCluster) []string {
+	codes := make([]string, 0, len(m.Spec.ErrorPages))
+	for code := range m.Spec.ErrorPages {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// errorPageDirectives returns the error_page directives serving the pages of
+// spec.errorPages
+func errorPageDirectives(m *nginxv1.NginxCluster) []string {
+	var directives []string
+	for _, code := range errorPageCodes(m) {
+		directives = append(directives, fmt.Sprintf("error_page %s /%s.html;", code, code))
+	}
+	return directives
+}
+
+// withErrorPages folds the content of the error pages into configHash, so
+// changing a page rolls the pods: the pages are mounted with subPath, which
+// doesn't pick up ConfigMap updates.
+func withErrorPages(m *nginxv1.NginxCluster, configHash string) string {
+	if len(m.Spec.ErrorPages) == 0 {
+		return configHash
+	}
+	var b strings.Builder
+	b.WriteString(configHash)
+	for _, code := range errorPageCodes(m) {
+		fmt.Fprintf(&b, "\nerror-page/%s:%s", code, calculateConfigHash(m.Spec.ErrorPages[code]))
+	}
+	return calculateConfigHash(b.String())
+}
+
+// addErrorPages mounts the pages of spec.errorPages into the document root of
+// the nginx container
+func addErrorPages(podSpec *corev1.PodSpec, m *nginxv1.NginxCluster) {
+	if len(m.Spec.ErrorPages) == 0 {
+		return
+	}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: errorPagesVolumeName,
+		VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: m.Name + errorPagesConfigMapSuffix},
+		}},
+	})
+	for _, code := range errorPageCodes(m) {
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      errorPagesVolumeName,
+			MountPath: errorPagesRoot + "/" + code + ".html",
+			SubPath:   code + ".html",
+			ReadOnly:  true,
+		})
+	}
+}
+
+// reconcileErrorPages creates or updates the ConfigMap holding the error
+// pages when the spec has some, and removes a previously created one
+// otherwise.
+func (r *NginxClusterReconciler) reconcileErrorPages(ctx context.Context, m *nginxv1.NginxCluster) error {
+	logger := log.FromContext(ctx)
+
+	cm := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Name: m.Name + errorPagesConfigMapSuffix, Namespace: workloadNamespace(m)}, cm)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+
+	if len(m.Spec.ErrorPages) == 0 {
+		if exists && r.isOwnedBy(cm, m) {
+			logger.Info("Deleting error pages ConfigMap", "ConfigMap.Namespace", cm.Namespace, "ConfigMap.Name", cm.Name)
+			if err := r.Delete(ctx, cm); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+		return nil
+	}
+
+	desired := r.errorPagesConfigMapForNginxCluster(m)
+	if !exists {
+		logger.Info("Creating a new error pages ConfigMap", "ConfigMap.Namespace", desired.Namespace, "ConfigMap.Name", desired.Name)
+		return r.createObject(ctx, desired)
+	}
+	if !r.isOwnedBy(cm, m) {
+		return fmt.Errorf("ConfigMap %s/%s exists and is not managed by the NginxCluster", cm.Namespace, cm.Name)
+	}
+
+	if !reflect.DeepEqual(cm.Data, desired.Data) {
+		logger.Info("Updating error pages ConfigMap", "ConfigMap.Namespace", cm.Namespace, "ConfigMap.Name", cm.Name)
+		return r.updateObject(ctx, cm, desired, func() {
+			cm.Data = desired.Data
+		})
+	}
+	return nil
+}
+
+// errorPagesConfigMapForNginxCluster returns the ConfigMap holding the error
+// pages, one <code>.html key per page
+func (r *NginxClusterReconciler) errorPagesConfigMapForNginxCluster(m *nginxv1.NginxCluster) *corev1.ConfigMap {
+	data := make(map[string]string, len(m.Spec.ErrorPages))
+	for code, page := range m.Spec.ErrorPages {
+		data[code+".html"] = page
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      m.Name + errorPagesConfigMapSuffix,
+			Namespace: workloadNamespace(m),
+		},
+		Data: data,
+	}
+	r.setOwner(m, cm)
+	return cm
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEffectiveNginxConfErrorPages(t *testing.T) {
+	m := newTestNginxCluster("error-pages")
+	m.Spec.NginxConf = ""
+	m.Spec.ErrorPages = map[string]string{"404": "<h1>Not here</h1>", "503": "<h1>Back soon</h1>"}
+	conf := effectiveNginxConf(m)
+	want := "        error_page 404 /404.html;\n        error_page 503 /503.html;\n        listen       80;"
+	if !strings.Contains(conf, want) {
+		t.Fatalf("error_page directives missing from the server block:\n%s", conf)
+	}
+
+	// Configs that already serve the pages are left alone
+	m.Spec.NginxConf = conf
+	if got := effectiveNginxConf(m); got != conf {
+		t.Fatalf("error_page directives merged twice:\n%s", got)
+	}
+}
+
+func TestDeploymentMountsErrorPages(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("error-pages")
+	m.Spec.ErrorPages = map[string]string{"404": "<h1>Not here</h1>"}
+	dep := r.deploymentForNginxCluster(m, "hash")
+	volumes := dep.Spec.Template.Spec.Volumes
+	if v := volumes[len(volumes)-1]; v.ConfigMap == nil || v.ConfigMap.Name != "error-pages-error-pages" {
+		t.Fatalf("unexpected error pages volume %+v", v)
+	}
+	mounts := dep.Spec.Template.Spec.Containers[0].VolumeMounts
+	if mount := mounts[len(mounts)-1]; mount.MountPath != "/usr/share/nginx/html/404.html" || mount.SubPath != "404.html" {
+		t.Fatalf("unexpected error page mount %+v", mount)
+	}
+
+	cm := r.errorPagesConfigMapForNginxCluster(m)
+	if cm.Data["404.html"] != "<h1>Not here</h1>" {
+		t.Fatalf("unexpected error pages ConfigMap data %v", cm.Data)
+	}
+}
+
+func TestWithErrorPages(t *testing.T) {
+	m := newTestNginxCluster("error-pages")
+	if got := withErrorPages(m, "hash"); got != "hash" {
+		t.Fatalf("hash changed without error pages: %s", got)
+	}
+	m.Spec.ErrorPages = map[string]string{"404": "<h1>Not here</h1>"}
+	first := withErrorPages(m, "hash")
+	m.Spec.ErrorPages["404"] = "<h1>Gone</h1>"
+	if first == "hash" || withErrorPages(m, "hash") == first {
+		t.Fatalf("page content not part of the config hash")
+	}
+}
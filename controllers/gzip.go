@@ -0,0 +1,62 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strconv"
+	"strings"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// defaultGzipTypes are compressed when spec.gzip.types is empty
+var defaultGzipTypes = []string{
+	"text/plain",
+	"text/css",
+	"text/xml",
+	"application/javascript",
+	"application/json",
+	"application/xml",
+	"image/svg+xml",
+}
+
+// gzipDirectives returns the directives of spec.gzip, none when it is unset
+// or disabled
+func gzipDirectives(m *nginxv1.NginxCluster) []string {
+	gz := m.Spec.Gzip
+	if gz == nil || (gz.Enabled != nil && !*gz.Enabled) {
+		return nil
+	}
+	types := gz.Types
+	if len(types) == 0 {
+		types = defaultGzipTypes
+	}
+	directives := []string{"gzip on;", "gzip_types " + strings.Join(types, " ") + ";"}
+	if gz.MinLength > 0 {
+		directives = append(directives, "gzip_min_length "+strconv.Itoa(int(gz.MinLength))+";")
+	}
+	if gz.Level > 0 {
+		directives = append(directives, "gzip_comp_level "+strconv.Itoa(int(gz.Level))+";")
+	}
+	return directives
+}
+
+// withGzip adds the directives of spec.gzip to the http block of a default
+// config
+func withGzip(m *nginxv1.NginxCluster, conf string) string {
+	return injectHTTPDirectives(conf, gzipDirectives(m))
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+	"testing"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+func TestEffectiveNginxConfGzip(t *testing.T) {
+	m := newTestNginxCluster("gzip")
+	m.Spec.NginxConf = ""
+	m.Spec.Gzip = &nginxv1.GzipSpec{Types: []string{"text/css", "application/javascript"}, MinLength: 1024, Level: 5}
+	conf := effectiveNginxConf(m)
+	want := "http {\n    gzip on;\n    gzip_types text/css application/javascript;\n    gzip_min_length 1024;\n    gzip_comp_level 5;\n"
+	if !strings.Contains(conf, want) {
+		t.Fatalf("gzip directives missing from the http block:\n%s", conf)
+	}
+	hash := calculateConfigHash(conf)
+
+	m.Spec.Gzip = &nginxv1.GzipSpec{}
+	conf = effectiveNginxConf(m)
+	if !strings.Contains(conf, "gzip_types text/plain text/css") || strings.Contains(conf, "gzip_comp_level") {
+		t.Fatalf("expected the default types only:\n%s", conf)
+	}
+	if calculateConfigHash(conf) == hash {
+		t.Fatalf("gzip settings not part of the config hash")
+	}
+
+	disabled := false
+	m.Spec.Gzip.Enabled = &disabled
+	if conf := effectiveNginxConf(m); strings.Contains(conf, "gzip") {
+		t.Fatalf("gzip directives added while disabled:\n%s", conf)
+	}
+
+	// Only the default configs are changed
+	m.Spec.Gzip.Enabled = nil
+	m.Spec.NginxConf = "events {}\nhttp {\n}\n"
+	if conf := effectiveNginxConf(m); strings.Contains(conf, "gzip") {
+		t.Fatalf("gzip directives added to the spec config:\n%s", conf)
+	}
+}
+
+func TestValidateGzip(t *testing.T) {
+	m := newTestNginxCluster("gzip")
+	m.Spec.Gzip = &nginxv1.GzipSpec{Level: 9}
+	if err := validateSpec(m); err != nil {
+		t.Fatalf("validateSpec() error = %v", err)
+	}
+	m.Spec.Gzip.Level = 10
+	if err := validateSpec(m); err == nil {
+		t.Fatalf("expected level 10 to be rejected")
+	}
+}
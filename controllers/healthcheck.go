@@ -0,0 +1,158 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/apimachinery/pkg/version"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// grpcHealthPortName names the container port of the gRPC health service
+const grpcHealthPortName = "grpc-health"
+
+// The dedicated health endpoint of spec.enableHealthEndpoint, on a port the
+// Service doesn't expose
+const (
+	healthEndpointPort     = 8086
+	healthEndpointPortName = "healthz"
+	healthEndpointPath     = "/healthz"
+)
+
+// defaultPIDFile is where the official nginx image writes the master pid
+const defaultPIDFile = "/var/run/nginx.pid"
+
+// grpcProbesMinVersion is the first Kubernetes version serving gRPC probes
+// by default
+var grpcProbesMinVersion = utilversion.MajorMinor(1, 24)
+
+// GRPCProbesSupported reports whether an API server of the given version
+// accepts gRPC probes. Unparsable versions are assumed to be recent.
+func GRPCProbesSupported(info *version.Info) bool {
+	v, err := utilversion.ParseGeneric(info.GitVersion)
+	if err != nil {
+		return true
+	}
+	return v.AtLeast(grpcProbesMinVersion)
+}
+
+// withHealthEndpoint adds the server of the dedicated health endpoint to the
+// http block of conf. The config is returned unchanged if it has no http
+// block.
+func withHealthEndpoint(conf string) string {
+	loc := httpBlockPattern.FindStringSubmatchIndex(conf)
+	if loc == nil {
+		return conf
+	}
+	indent := conf[loc[2]:loc[3]] + "    "
+	port := strconv.Itoa(healthEndpointPort)
+	var b strings.Builder
+	b.WriteString(conf[:loc[1]])
+	b.WriteString(indent + "server {\n")
+	b.WriteString(indent + "    listen " + port + ";\n")
+	b.WriteString(indent + "    location = " + healthEndpointPath + " {\n")
+	b.WriteString(indent + "        access_log off;\n")
+	// Probes send no credentials, whatever the http block requires
+	b.WriteString(indent + "        auth_basic off;\n")
+	b.WriteString(indent + "        return 200;\n")
+	b.WriteString(indent + "    }\n")
+	b.WriteString(indent + "}\n")
+	b.WriteString(conf[loc[1]:])
+	return b.String()
+}
+
+// hasHealthEndpoint reports whether conf already listens on the port of the
+// health endpoint
+func hasHealthEndpoint(conf string) bool {
+	return strings.Contains(conf, "listen "+strconv.Itoa(healthEndpointPort)+";")
+}
+
+// addHealthCheck sets the readiness and liveness probes of the nginx
+// container, and exposes the port of a gRPC health service or of the
+// dedicated health endpoint
+func (r *NginxClusterReconciler) addHealthCheck(spec *corev1.PodSpec, m *nginxv1.NginxCluster) {
+	hc := m.Spec.HealthCheck
+	if hc == nil && !m.Spec.EnableHealthEndpoint {
+		return
+	}
+	if hc == nil {
+		hc = &nginxv1.HealthCheckSpec{}
+	}
+	livenessType := hc.LivenessType
+	if livenessType == "" {
+		livenessType = hc.Type
+	}
+
+	container := &spec.Containers[0]
+	container.ReadinessProbe = &corev1.Probe{ProbeHandler: r.probeHandler(m, hc, hc.Type)}
+	container.LivenessProbe = &corev1.Probe{ProbeHandler: r.probeHandler(m, hc, livenessType)}
+	if m.Spec.EnableHealthEndpoint {
+		container.Ports = append(container.Ports, corev1.ContainerPort{
+			ContainerPort: healthEndpointPort,
+			Name:          healthEndpointPortName,
+		})
+	}
+	if usesGRPCHealthCheck(hc) && hc.Port != 80 {
+		container.Ports = append(container.Ports, corev1.ContainerPort{
+			ContainerPort: hc.Port,
+			Name:          grpcHealthPortName,
+		})
+	}
+}
+
+// usesGRPCHealthCheck reports whether either probe calls the gRPC health
+// service
+func usesGRPCHealthCheck(hc *nginxv1.HealthCheckSpec) bool {
+	return hc.Type == "GRPC" || hc.LivenessType == "GRPC"
+}
+
+// probeHandler returns the handler of a probe of the given type
+func (r *NginxClusterReconciler) probeHandler(m *nginxv1.NginxCluster, hc *nginxv1.HealthCheckSpec, probeType string) corev1.ProbeHandler {
+	var handler corev1.ProbeHandler
+	switch {
+	case probeType == "Exec":
+		pidFile := hc.PIDFile
+		if pidFile == "" {
+			pidFile = defaultPIDFile
+		}
+		handler.Exec = &corev1.ExecAction{Command: []string{"/bin/sh", "-c", `kill -0 "$(cat ` + pidFile + `)"`}}
+	case probeType == "GRPC" && r.DisableGRPCProbes:
+		// At least check that the health service accepts connections
+		handler.TCPSocket = &corev1.TCPSocketAction{Port: intstr.FromInt32(hc.Port)}
+	case probeType == "GRPC":
+		handler.GRPC = &corev1.GRPCAction{Port: hc.Port}
+		if hc.Service != "" {
+			service := hc.Service
+			handler.GRPC.Service = &service
+		}
+	case m.Spec.EnableHealthEndpoint:
+		handler.HTTPGet = &corev1.HTTPGetAction{Path: healthEndpointPath, Port: intstr.FromString(healthEndpointPortName)}
+	default:
+		path := hc.Path
+		if path == "" {
+			path = "/"
+		}
+		handler.HTTPGet = &corev1.HTTPGetAction{Path: path, Port: intstr.FromString("http")}
+	}
+	return handler
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/version"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+func TestGRPCProbesSupported(t *testing.T) {
+	for gitVersion, want := range map[string]bool{
+		"v1.23.17":         false,
+		"v1.24.0":          true,
+		"v1.28.3-gke.1200": true,
+		"unknown":          true,
+	} {
+		if got := GRPCProbesSupported(&version.Info{GitVersion: gitVersion}); got != want {
+			t.Errorf("GRPCProbesSupported(%q) = %v, want %v", gitVersion, got, want)
+		}
+	}
+}
+
+func TestDeploymentHTTPHealthCheck(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("healthcheck-http")
+	if c := r.deploymentForNginxCluster(m, "hash").Spec.Template.Spec.Containers[0]; c.ReadinessProbe != nil || c.LivenessProbe != nil {
+		t.Fatalf("expected no probes without healthCheck")
+	}
+
+	m.Spec.HealthCheck = &nginxv1.HealthCheckSpec{}
+	c := r.deploymentForNginxCluster(m, "hash").Spec.Template.Spec.Containers[0]
+	if c.ReadinessProbe == nil || c.LivenessProbe == nil {
+		t.Fatalf("expected readiness and liveness probes")
+	}
+	if get := c.ReadinessProbe.HTTPGet; get == nil || get.Path != "/" || get.Port.String() != "http" {
+		t.Fatalf("unexpected HTTP probe %+v", get)
+	}
+}
+
+func TestDeploymentHealthEndpoint(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("health-endpoint")
+	m.Spec.EnableHealthEndpoint = true
+	c := r.deploymentForNginxCluster(m, "hash").Spec.Template.Spec.Containers[0]
+	for _, probe := range []*corev1.Probe{c.ReadinessProbe, c.LivenessProbe} {
+		if probe == nil || probe.HTTPGet == nil || probe.HTTPGet.Path != "/healthz" || probe.HTTPGet.Port.String() != "healthz" {
+			t.Fatalf("expected the probes to request the health endpoint, got %+v", probe)
+		}
+	}
+	if ports := c.Ports; len(ports) != 2 || ports[1].Name != "healthz" || ports[1].ContainerPort != 8086 {
+		t.Fatalf("health endpoint port not exposed: %+v", ports)
+	}
+	if ports := servicePortsForNginxCluster(m); len(ports) != 1 {
+		t.Fatalf("health endpoint port exposed by the Service: %+v", ports)
+	}
+
+	// Other probe types are kept
+	m.Spec.HealthCheck = &nginxv1.HealthCheckSpec{Path: "/status", LivenessType: "Exec"}
+	c = r.deploymentForNginxCluster(m, "hash").Spec.Template.Spec.Containers[0]
+	if c.ReadinessProbe.HTTPGet == nil || c.ReadinessProbe.HTTPGet.Path != "/healthz" || c.LivenessProbe.Exec == nil {
+		t.Fatalf("unexpected probes %+v, %+v", c.ReadinessProbe, c.LivenessProbe)
+	}
+}
+
+func TestEffectiveNginxConfHealthEndpoint(t *testing.T) {
+	m := newTestNginxCluster("health-endpoint-conf")
+	m.Spec.EnableHealthEndpoint = true
+	m.Spec.NginxConf = "events {}\nhttp {\n    server {\n        listen 80;\n    }\n}\n"
+	want := "events {}\nhttp {\n    server {\n        listen 8086;\n        location = /healthz {\n" +
+		"            access_log off;\n            auth_basic off;\n            return 200;\n        }\n    }\n    server {\n        listen 80;\n    }\n}\n"
+	got := effectiveNginxConf(m)
+	if got != want {
+		t.Fatalf("unexpected effective config:\n%s\nwant:\n%s", got, want)
+	}
+	if err := validateNginxConf(got); err != nil {
+		t.Fatalf("health endpoint breaks the config: %v", err)
+	}
+
+	// Configs that already serve the port are left alone
+	m.Spec.NginxConf = want
+	if got := effectiveNginxConf(m); got != want {
+		t.Fatalf("health endpoint added twice:\n%s", got)
+	}
+}
+
+func TestDeploymentGRPCHealthCheck(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("healthcheck-grpc")
+	m.Spec.HealthCheck = &nginxv1.HealthCheckSpec{Type: "GRPC", Port: 9090, Service: "ready"}
+
+	c := r.deploymentForNginxCluster(m, "hash").Spec.Template.Spec.Containers[0]
+	grpc := c.LivenessProbe.GRPC
+	if grpc == nil || grpc.Port != 9090 || grpc.Service == nil || *grpc.Service != "ready" {
+		t.Fatalf("unexpected gRPC probe %+v", grpc)
+	}
+	if last := c.Ports[len(c.Ports)-1]; last.Name != grpcHealthPortName || last.ContainerPort != 9090 {
+		t.Fatalf("expected the health port to be exposed, got %+v", c.Ports)
+	}
+
+	// Clusters without gRPC probes get a TCP check on the same port
+	r.DisableGRPCProbes = true
+	c = r.deploymentForNginxCluster(m, "hash").Spec.Template.Spec.Containers[0]
+	if c.ReadinessProbe.GRPC != nil || c.ReadinessProbe.TCPSocket == nil || c.ReadinessProbe.TCPSocket.Port.IntValue() != 9090 {
+		t.Fatalf("expected a TCP fallback probe, got %+v", c.ReadinessProbe)
+	}
+}
+
+func TestDeploymentExecLivenessProbe(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("healthcheck-exec")
+	m.Spec.HealthCheck = &nginxv1.HealthCheckSpec{LivenessType: "Exec"}
+
+	c := r.deploymentForNginxCluster(m, "hash").Spec.Template.Spec.Containers[0]
+	if c.ReadinessProbe.HTTPGet == nil {
+		t.Fatalf("expected an HTTP readiness probe, got %+v", c.ReadinessProbe)
+	}
+	exec := c.LivenessProbe.Exec
+	if exec == nil || len(exec.Command) != 3 || exec.Command[2] != `kill -0 "$(cat /var/run/nginx.pid)"` {
+		t.Fatalf("unexpected exec liveness probe %+v", c.LivenessProbe)
+	}
+
+	m.Spec.HealthCheck.PIDFile = "/tmp/nginx.pid"
+	c = r.deploymentForNginxCluster(m, "hash").Spec.Template.Spec.Containers[0]
+	if cmd := c.LivenessProbe.Exec.Command[2]; cmd != `kill -0 "$(cat /tmp/nginx.pid)"` {
+		t.Fatalf("pid file not used: %s", cmd)
+	}
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// httpRouteGVK is the Gateway API HTTPRoute kind. Like Routes, HTTPRoutes
+// are handled as unstructured objects since the CRD is optional.
+var httpRouteGVK = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"}
+
+// newHTTPRoute returns an empty unstructured HTTPRoute
+func newHTTPRoute() *unstructured.Unstructured {
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(httpRouteGVK)
+	return route
+}
+
+// reconcileHTTPRoute creates or updates the HTTPRoute when it is requested in
+// the spec, and removes a previously created one otherwise. Clusters without
+// the Gateway API are skipped.
+func (r *NginxClusterReconciler) reconcileHTTPRoute(ctx context.Context, m *nginxv1.NginxCluster) error {
+	logger := log.FromContext(ctx)
+
+	available, err := apiAvailable(r.RESTMapper(), httpRouteGVK)
+	if err != nil {
+		return err
+	}
+	if !available {
+		if m.Spec.HTTPRoute != nil {
+			logger.Info("Gateway API gateway.networking.k8s.io/v1 not available, skipping HTTPRoute")
+		}
+		return nil
+	}
+
+	route := newHTTPRoute()
+	err = r.Get(ctx, types.NamespacedName{Name: m.Name, Namespace: workloadNamespace(m)}, route)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+
+	if m.Spec.HTTPRoute == nil {
+		if exists && r.isOwnedBy(route, m) {
+			logger.Info("Deleting HTTPRoute", "HTTPRoute.Namespace", route.GetNamespace(), "HTTPRoute.Name", route.GetName())
+			if err := r.Delete(ctx, route); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+		return nil
+	}
+
+	desired := r.httpRouteForNginxCluster(m)
+	if !exists {
+		logger.Info("Creating a new HTTPRoute", "HTTPRoute.Namespace", desired.GetNamespace(), "HTTPRoute.Name", desired.GetName())
+		return r.createObject(ctx, desired)
+	}
+
+	if syncHTTPRouteSpec(route.DeepCopy(), desired) {
+		logger.Info("Updating HTTPRoute", "HTTPRoute.Namespace", route.GetNamespace(), "HTTPRoute.Name", route.GetName())
+		return r.updateObject(ctx, route, desired, func() {
+			syncHTTPRouteSpec(route, desired)
+		})
+	}
+	return nil
+}
+
+// httpRouteForNginxCluster returns an HTTPRoute sending the requests matched
+// by the spec to the http port of the cluster's Service. The fields the API
+// server defaults are set explicitly, so the stored object doesn't differ
+// from the desired one.
+func (r *NginxClusterReconciler) httpRouteForNginxCluster(m *nginxv1.NginxCluster) *unstructured.Unstructured {
+	spec := m.Spec.HTTPRoute
+	serviceName := m.Name
+	if m.Spec.SharedServiceName != "" {
+		serviceName = m.Spec.SharedServiceName
+	}
+
+	parentRef := map[string]interface{}{
+		"group": httpRouteGVK.Group,
+		"kind":  "Gateway",
+		"name":  spec.ParentRef.Name,
+	}
+	if spec.ParentRef.Namespace != "" {
+		parentRef["namespace"] = spec.ParentRef.Namespace
+	}
+	if spec.ParentRef.SectionName != "" {
+		parentRef["sectionName"] = spec.ParentRef.SectionName
+	}
+
+	matches := []interface{}{}
+	for _, path := range spec.Paths {
+		matchType := path.Type
+		if matchType == "" {
+			matchType = "PathPrefix"
+		}
+		matches = append(matches, map[string]interface{}{
+			"path": map[string]interface{}{
+				"type":  matchType,
+				"value": path.Value,
+			},
+		})
+	}
+	if len(matches) == 0 {
+		matches = append(matches, map[string]interface{}{
+			"path": map[string]interface{}{
+				"type":  "PathPrefix",
+				"value": "/",
+			},
+		})
+	}
+
+	routeSpec := map[string]interface{}{
+		"parentRefs": []interface{}{parentRef},
+		"rules": []interface{}{
+			map[string]interface{}{
+				"matches": matches,
+				"backendRefs": []interface{}{
+					map[string]interface{}{
+						"group":  "",
+						"kind":   "Service",
+						"name":   serviceName,
+						"port":   int64(80),
+						"weight": int64(1),
+					},
+				},
+			},
+		},
+	}
+	if len(spec.Hostnames) > 0 {
+		hostnames := make([]interface{}, 0, len(spec.Hostnames))
+		for _, hostname := range spec.Hostnames {
+			hostnames = append(hostnames, hostname)
+		}
+		routeSpec["hostnames"] = hostnames
+	}
+
+	route := newHTTPRoute()
+	route.SetName(m.Name)
+	route.SetNamespace(workloadNamespace(m))
+	route.Object["spec"] = routeSpec
+	r.setOwner(m, route)
+	return route
+}
+
+// syncHTTPRouteSpec copies the fields the operator manages from desired onto
+// existing and reports whether anything changed
+func syncHTTPRouteSpec(existing, desired *unstructured.Unstructured) bool {
+	changed := false
+	for _, field := range []string{"parentRefs", "hostnames", "rules"} {
+		want, wantFound, _ := unstructured.NestedFieldCopy(desired.Object, "spec", field)
+		got, found, _ := unstructured.NestedFieldNoCopy(existing.Object, "spec", field)
+		switch {
+		case !wantFound && found:
+			unstructured.RemoveNestedField(existing.Object, "spec", field)
+			changed = true
+		case wantFound && !equality.Semantic.DeepEqual(got, want):
+			_ = unstructured.SetNestedField(existing.Object, want, "spec", field)
+			changed = true
+		}
+	}
+	return changed
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+func TestHTTPRouteForNginxCluster(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("http-route")
+	m.UID = "http-route-uid"
+	m.Spec.HTTPRoute = &nginxv1.HTTPRouteSpec{
+		ParentRef: nginxv1.GatewayReference{Name: "public", Namespace: "gateways", SectionName: "https"},
+		Hostnames: []string{"www.example.com"},
+		Paths:     []nginxv1.HTTPRoutePath{{Value: "/static"}, {Type: "Exact", Value: "/"}},
+	}
+
+	route := r.httpRouteForNginxCluster(m)
+	if route.GetKind() != "HTTPRoute" || route.GetAPIVersion() != "gateway.networking.k8s.io/v1" {
+		t.Fatalf("unexpected kind %s %s", route.GetAPIVersion(), route.GetKind())
+	}
+	parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+	if len(parentRefs) != 1 {
+		t.Fatalf("expected one parent, got %v", parentRefs)
+	}
+	parent := parentRefs[0].(map[string]interface{})
+	if parent["kind"] != "Gateway" || parent["name"] != "public" || parent["namespace"] != "gateways" || parent["sectionName"] != "https" {
+		t.Errorf("unexpected parent %v", parent)
+	}
+	if hostnames, _, _ := unstructured.NestedStringSlice(route.Object, "spec", "hostnames"); len(hostnames) != 1 || hostnames[0] != "www.example.com" {
+		t.Errorf("unexpected hostnames %v", hostnames)
+	}
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	rule := rules[0].(map[string]interface{})
+	matches := rule["matches"].([]interface{})
+	for i, want := range [][2]string{{"PathPrefix", "/static"}, {"Exact", "/"}} {
+		path := matches[i].(map[string]interface{})["path"].(map[string]interface{})
+		if path["type"] != want[0] || path["value"] != want[1] {
+			t.Errorf("match %d = %v, want %v", i, path, want)
+		}
+	}
+	backend := rule["backendRefs"].([]interface{})[0].(map[string]interface{})
+	if backend["name"] != m.Name || backend["port"] != int64(80) {
+		t.Errorf("unexpected backend %v", backend)
+	}
+	if err := checkControllerOwner(route, m); err != nil {
+		t.Error(err)
+	}
+
+	m.Spec.SharedServiceName = "edge"
+	rules, _, _ = unstructured.NestedSlice(r.httpRouteForNginxCluster(m).Object, "spec", "rules")
+	if name := rules[0].(map[string]interface{})["backendRefs"].([]interface{})[0].(map[string]interface{})["name"]; name != "edge" {
+		t.Errorf("expected the HTTPRoute to target the shared Service, got %v", name)
+	}
+}
+
+func TestSyncHTTPRouteSpec(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("http-route-sync")
+	m.Spec.HTTPRoute = &nginxv1.HTTPRouteSpec{
+		ParentRef: nginxv1.GatewayReference{Name: "public"},
+		Hostnames: []string{"www.example.com"},
+	}
+
+	existing := r.httpRouteForNginxCluster(m)
+	if syncHTTPRouteSpec(existing, r.httpRouteForNginxCluster(m)) {
+		t.Fatalf("unchanged HTTPRoute counted as drift")
+	}
+
+	m.Spec.HTTPRoute.Hostnames = nil
+	m.Spec.HTTPRoute.Paths = []nginxv1.HTTPRoutePath{{Value: "/api"}}
+	if !syncHTTPRouteSpec(existing, r.httpRouteForNginxCluster(m)) {
+		t.Fatalf("expected the hostname and path changes to be synced")
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(existing.Object, "spec", "hostnames"); found {
+		t.Errorf("expected the hostnames to be removed")
+	}
+	rules, _, _ := unstructured.NestedSlice(existing.Object, "spec", "rules")
+	path := rules[0].(map[string]interface{})["matches"].([]interface{})[0].(map[string]interface{})["path"].(map[string]interface{})
+	if path["value"] != "/api" {
+		t.Errorf("unexpected path %v", path)
+	}
+}
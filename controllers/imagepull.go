@@ -0,0 +1,62 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// imagePullCheckInterval is how often the pods of a rollout in progress are
+// checked for failed image pulls
+const imagePullCheckInterval = 30 * time.Second
+
+// imagePullFailure returns a message naming the image and pull error of the
+// first container, including init containers, the kubelet fails to pull an
+// image for, or "" when all images could be pulled
+func imagePullFailure(pods []corev1.Pod) string {
+	for i := range pods {
+		if pods[i].DeletionTimestamp != nil {
+			continue
+		}
+		statuses := append(append([]corev1.ContainerStatus(nil), pods[i].Status.InitContainerStatuses...), pods[i].Status.ContainerStatuses...)
+		for _, cs := range statuses {
+			waiting := cs.State.Waiting
+			if waiting == nil || (waiting.Reason != "ImagePullBackOff" && waiting.Reason != "ErrImagePull") {
+				continue
+			}
+			return fmt.Sprintf("Pod %s cannot pull image %s: %s", pods[i].Name, cs.Image, waiting.Message)
+		}
+	}
+	return ""
+}
+
+// imagePullCondition reports a failed image pull as Degraded
+func imagePullCondition(m *nginxv1.NginxCluster, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:               nginxv1.ConditionDegraded,
+		Status:             metav1.ConditionTrue,
+		Reason:             "ImagePullFailed",
+		Message:            message,
+		ObservedGeneration: m.Generation,
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestImagePullFailure(t *testing.T) {
+	pod := func(name, reason string) corev1.Pod {
+		p := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		p.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name:  "nginx",
+			Image: "nginx:1.255",
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+				Reason:  reason,
+				Message: `Back-off pulling image "nginx:1.255"`,
+			}},
+		}}
+		return p
+	}
+
+	if msg := imagePullFailure([]corev1.Pod{pod("starting", "ContainerCreating")}); msg != "" {
+		t.Fatalf("unexpected image pull failure %q", msg)
+	}
+	pods := []corev1.Pod{pod("starting", "ContainerCreating"), pod("typo", "ImagePullBackOff")}
+	want := `Pod typo cannot pull image nginx:1.255: Back-off pulling image "nginx:1.255"`
+	if msg := imagePullFailure(pods); msg != want {
+		t.Fatalf("imagePullFailure() = %q, want %q", msg, want)
+	}
+	if cond := imagePullCondition(newTestNginxCluster("typo"), want); cond.Status != metav1.ConditionTrue || cond.Reason != "ImagePullFailed" {
+		t.Fatalf("unexpected condition %+v", cond)
+	}
+}
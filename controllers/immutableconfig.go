@@ -0,0 +1,65 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// configMapImmutable returns the immutable flag of the generated ConfigMap
+func configMapImmutable(m *nginxv1.NginxCluster) *bool {
+	if !m.Spec.ImmutableConfig {
+		return nil
+	}
+	immutable := true
+	return &immutable
+}
+
+func isImmutable(cm *corev1.ConfigMap) bool {
+	return cm.Immutable != nil && *cm.Immutable
+}
+
+// configMapNeedsRecreate reports whether the generated ConfigMap live has to
+// be replaced to hold the config of configHash with the flag m asks for. The
+// data of an immutable ConfigMap can't be changed and the flag can't be
+// unset; its labels and annotations can still be updated.
+func configMapNeedsRecreate(live *corev1.ConfigMap, m *nginxv1.NginxCluster, configHash string) bool {
+	if !isImmutable(live) {
+		return false
+	}
+	return calculateConfigHash(live.Data["nginx.conf"]) != configHash || !m.Spec.ImmutableConfig
+}
+
+// recreateConfigMap replaces the immutable ConfigMap live with desired. The
+// deletion is conditional on live being unchanged, so a ConfigMap replaced
+// in the meantime is left alone. Running pods keep the files they mounted,
+// kubelets don't refresh immutable ConfigMaps, until the config hash rolls
+// them; should the creation fail, the next reconcile creates the ConfigMap.
+func (r *NginxClusterReconciler) recreateConfigMap(ctx context.Context, live, desired *corev1.ConfigMap) error {
+	uid, resourceVersion := live.UID, live.ResourceVersion
+	err := r.Delete(ctx, live, client.Preconditions{UID: &uid, ResourceVersion: &resourceVersion})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return r.createObject(ctx, desired)
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestConfigMapNeedsRecreate(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("immutable-config")
+	hash := calculateConfigHash(m.Spec.NginxConf)
+	if live := r.configMapForNginxCluster(m, m.Spec.NginxConf, hash); live.Immutable != nil || configMapNeedsRecreate(live, m, "other") {
+		t.Fatalf("mutable ConfigMap %+v needs no recreate", live)
+	}
+
+	m.Spec.ImmutableConfig = true
+	live := r.configMapForNginxCluster(m, m.Spec.NginxConf, hash)
+	if !isImmutable(live) {
+		t.Fatalf("ConfigMap not marked immutable")
+	}
+	if configMapNeedsRecreate(live, m, hash) {
+		t.Fatalf("unchanged immutable ConfigMap recreated")
+	}
+	if !configMapNeedsRecreate(live, m, calculateConfigHash("http {}\n")) {
+		t.Fatalf("expected a config change to recreate the ConfigMap")
+	}
+	// The flag can't be unset either
+	m.Spec.ImmutableConfig = false
+	if !configMapNeedsRecreate(live, m, hash) {
+		t.Fatalf("expected turning immutableConfig off to recreate the ConfigMap")
+	}
+
+	m.Spec.ConfigReloaderSidecar = true
+	m.Spec.ImmutableConfig = true
+	if err := validateSpec(m); err == nil {
+		t.Fatalf("expected immutableConfig with the reloader sidecar to be rejected")
+	}
+	m.Spec.ConfigReloaderSidecar = false
+	disabled := false
+	m.Spec.RestartOnConfigChange = &disabled
+	if err := validateSpec(m); err == nil {
+		t.Fatalf("expected immutableConfig without restarts on config changes to be rejected")
+	}
+}
+
+func TestReconcileRecreatesImmutableConfigMap(t *testing.T) {
+	requireEnvtest(t)
+	ctx := context.Background()
+
+	m := newTestNginxCluster("immutable-change")
+	m.Spec.ImmutableConfig = true
+	createTestNginxCluster(t, m)
+	key := client.ObjectKeyFromObject(m)
+	cmKey := types.NamespacedName{Name: m.Name + configMapNameSuffix, Namespace: m.Namespace}
+
+	var oldUID types.UID
+	eventually(t, func() error {
+		cm := &corev1.ConfigMap{}
+		if err := k8sClient.Get(ctx, cmKey, cm); err != nil {
+			return err
+		}
+		if !isImmutable(cm) {
+			return fmt.Errorf("ConfigMap not immutable")
+		}
+		oldUID = cm.UID
+		return nil
+	})
+
+	newConf := "events {}\nhttp { server { listen 80; } }\n"
+	eventually(t, func() error {
+		if err := k8sClient.Get(ctx, key, m); err != nil {
+			return err
+		}
+		m.Spec.NginxConf = newConf
+		return k8sClient.Update(ctx, m)
+	})
+
+	eventually(t, func() error {
+		cm := &corev1.ConfigMap{}
+		if err := k8sClient.Get(ctx, cmKey, cm); err != nil {
+			return err
+		}
+		if cm.UID == oldUID || cm.Data["nginx.conf"] != newConf || !isImmutable(cm) {
+			return fmt.Errorf("ConfigMap not recreated yet")
+		}
+		return nil
+	})
+	eventually(t, func() error {
+		dep := &appsv1.Deployment{}
+		if err := k8sClient.Get(ctx, key, dep); err != nil {
+			return err
+		}
+		if got := dep.Spec.Template.Annotations["config-hash"]; got != calculateConfigHash(newConf) {
+			return fmt.Errorf("pod template config-hash is %q", got)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// applyServiceIPFamilies sets the IP family policy and families requested by
+// the spec on srv. Unset ones are left to the API server's defaults.
+// ExternalName Services have no cluster IPs, so no families either.
+func applyServiceIPFamilies(srv *corev1.Service, m *nginxv1.NginxCluster) {
+	if isExternalName(m) {
+		return
+	}
+	if policy := m.Spec.IPFamilyPolicy; policy != nil {
+		p := *policy
+		srv.Spec.IPFamilyPolicy = &p
+	}
+	if len(m.Spec.IPFamilies) > 0 {
+		srv.Spec.IPFamilies = append([]corev1.IPFamily(nil), m.Spec.IPFamilies...)
+	}
+}
+
+// syncServiceIPFamilies brings the IP family settings of existing in line
+// with the ones desired sets and reports whether anything changed. Settings
+// desired leaves unset keep the values the API server defaulted.
+func syncServiceIPFamilies(existing, desired *corev1.Service) bool {
+	changed := false
+	if p := desired.Spec.IPFamilyPolicy; p != nil && (existing.Spec.IPFamilyPolicy == nil || *existing.Spec.IPFamilyPolicy != *p) {
+		policy := *p
+		existing.Spec.IPFamilyPolicy = &policy
+		changed = true
+	}
+	if f := desired.Spec.IPFamilies; len(f) > 0 && !reflect.DeepEqual(existing.Spec.IPFamilies, f) {
+		existing.Spec.IPFamilies = append([]corev1.IPFamily(nil), f...)
+		changed = true
+	}
+	return changed
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestServiceIPFamilies(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("ip-families")
+
+	// The API server defaults a dual-stack Service
+	existing := r.serviceForNginxCluster(m)
+	preferDualStack := corev1.IPFamilyPolicyPreferDualStack
+	existing.Spec.IPFamilyPolicy = &preferDualStack
+	existing.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}
+	if syncServiceIPFamilies(existing.DeepCopy(), r.serviceForNginxCluster(m)) {
+		t.Fatalf("defaulted IP families reported as drift")
+	}
+
+	singleStack := corev1.IPFamilyPolicySingleStack
+	m.Spec.IPFamilyPolicy = &singleStack
+	m.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv6Protocol}
+	desired := r.serviceForNginxCluster(m)
+	if !syncServiceIPFamilies(existing, desired) {
+		t.Fatalf("expected the IP family policy to be synced")
+	}
+	if *existing.Spec.IPFamilyPolicy != singleStack || len(existing.Spec.IPFamilies) != 1 || existing.Spec.IPFamilies[0] != corev1.IPv6Protocol {
+		t.Fatalf("unexpected IP families %v %v", *existing.Spec.IPFamilyPolicy, existing.Spec.IPFamilies)
+	}
+	if syncServiceIPFamilies(existing, desired) {
+		t.Fatalf("synced IP families reported as drift")
+	}
+}
+
+func TestValidateIPFamilies(t *testing.T) {
+	m := newTestNginxCluster("ip-families")
+	singleStack := corev1.IPFamilyPolicySingleStack
+	m.Spec.IPFamilyPolicy = &singleStack
+	m.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}
+	if err := validateSpec(m); err == nil {
+		t.Fatalf("expected two families to be rejected for SingleStack")
+	}
+	m.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv6Protocol}
+	if err := validateSpec(m); err != nil {
+		t.Fatalf("valid IP families rejected: %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+const (
+	// loadBalancerReleaseTimeout bounds how long deletion of a NginxCluster
+	// waits for the cloud provider to release its load balancer
+	loadBalancerReleaseTimeout      = 10 * time.Minute
+	loadBalancerReleasePollInterval = 10 * time.Second
+)
+
+// waitForLoadBalancerRelease deletes the cluster's LoadBalancer Service and
+// returns how long to wait before checking again whether it is gone. Cloud
+// providers hold the Service with a finalizer until the load balancer is
+// released, so removing the Service is what signals the release. A zero
+// duration means finalization can proceed.
+func (r *NginxClusterReconciler) waitForLoadBalancerRelease(ctx context.Context, m *nginxv1.NginxCluster) (time.Duration, error) {
+	logger := log.FromContext(ctx)
+
+	service := &corev1.Service{}
+	key := types.NamespacedName{Name: m.Name, Namespace: workloadNamespace(m)}
+	err := r.Get(ctx, key, service)
+	if errors.IsNotFound(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	if service.Spec.Type != corev1.ServiceTypeLoadBalancer || !r.isOwnedBy(service, m) {
+		return 0, nil
+	}
+
+	if service.DeletionTimestamp == nil {
+		logger.Info("Deleting LoadBalancer Service", "Service.Namespace", service.Namespace, "Service.Name", service.Name)
+		if err := r.Delete(ctx, service); err != nil && !errors.IsNotFound(err) {
+			return 0, err
+		}
+		if err := r.Get(ctx, key, service); errors.IsNotFound(err) {
+			return 0, nil
+		} else if err != nil {
+			return 0, err
+		}
+	}
+
+	if m.DeletionTimestamp != nil && time.Since(m.DeletionTimestamp.Time) > loadBalancerReleaseTimeout {
+		logger.Info("Timed out waiting for the load balancer to be released, finalizing anyway",
+			"Service.Namespace", service.Namespace, "Service.Name", service.Name, "Ingress", service.Status.LoadBalancer.Ingress)
+		return 0, nil
+	}
+	logger.Info("Waiting for the load balancer to be released", "Service.Namespace", service.Namespace, "Service.Name", service.Name)
+	return loadBalancerReleasePollInterval, nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// cloudCleanupFinalizer is what cloud providers use to hold a LoadBalancer
+// Service until its load balancer is released.
+const cloudCleanupFinalizer = "service.kubernetes.io/load-balancer-cleanup"
+
+func TestFinalizerWaitsForLoadBalancerRelease(t *testing.T) {
+	requireEnvtest(t)
+	ctx := context.Background()
+
+	m := newTestNginxCluster("lb-release")
+	createTestNginxCluster(t, m)
+	key := client.ObjectKeyFromObject(m)
+
+	// Turn the Service into a LoadBalancer held by the cloud provider
+	eventually(t, func() error {
+		srv := &corev1.Service{}
+		if err := k8sClient.Get(ctx, key, srv); err != nil {
+			return err
+		}
+		srv.Spec.Type = corev1.ServiceTypeLoadBalancer
+		controllerutil.AddFinalizer(srv, cloudCleanupFinalizer)
+		return k8sClient.Update(ctx, srv)
+	})
+
+	if err := k8sClient.Delete(ctx, m); err != nil {
+		t.Fatalf("failed to delete NginxCluster: %v", err)
+	}
+
+	srv := &corev1.Service{}
+	eventually(t, func() error {
+		if err := k8sClient.Get(ctx, key, srv); err != nil {
+			return err
+		}
+		if srv.DeletionTimestamp == nil {
+			return fmt.Errorf("LoadBalancer Service was not deleted")
+		}
+		return nil
+	})
+	got := &nginxv1.NginxCluster{}
+	if err := k8sClient.Get(ctx, key, got); err != nil {
+		t.Fatalf("expected the NginxCluster to wait for the load balancer, got %v", err)
+	}
+
+	// The cloud provider releases the load balancer
+	controllerutil.RemoveFinalizer(srv, cloudCleanupFinalizer)
+	if err := k8sClient.Update(ctx, srv); err != nil {
+		t.Fatalf("failed to release the Service: %v", err)
+	}
+	eventually(t, func() error {
+		err := k8sClient.Get(ctx, key, &nginxv1.NginxCluster{})
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("NginxCluster still present: %v", err)
+	})
+}
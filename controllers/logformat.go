@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// jsonLogFormat is the format of the json log format preset
+var jsonLogFormat = `{` +
+	`"time":"$time_iso8601",` +
+	`"remote_addr":"$remote_addr",` +
+	`"request_id":"$request_id",` +
+	`"method":"$request_method",` +
+	`"uri":"$request_uri",` +
+	`"protocol":"$server_protocol",` +
+	`"status":$status,` +
+	`"body_bytes_sent":$body_bytes_sent,` +
+	`"request_time":$request_time,` +
+	`"upstream_addr":"$upstream_addr",` +
+	`"upstream_response_time":"$upstream_response_time",` +
+	`"http_referer":"$http_referer",` +
+	`"http_user_agent":"$http_user_agent",` +
+	`"http_x_forwarded_for":"$http_x_forwarded_for"` +
+	`}`
+
+// logFormatDirectives returns the log_format of spec.logFormat and the
+// access_log using it, none when it is unset
+func logFormatDirectives(m *nginxv1.NginxCluster) []string {
+	lf := m.Spec.LogFormat
+	if lf == nil {
+		return nil
+	}
+	name := lf.Name
+	if name == "" {
+		name = "main"
+	}
+	format, escape := lf.Format, lf.Escape
+	if lf.Preset == "json" {
+		format, escape = jsonLogFormat, "json"
+	}
+	directive := "log_format " + name
+	if escape != "" {
+		directive += " escape=" + escape
+	}
+	// Single quotes and backslashes are the escapes of a quoted nginx string
+	quoted := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(format)
+	return []string{
+		directive + " '" + quoted + "';",
+		"access_log /var/log/nginx/access.log " + name + ";",
+	}
+}
+
+// withLogFormat adds the log format of spec.logFormat to the http block of a
+// default config
+func withLogFormat(m *nginxv1.NginxCluster, conf string) string {
+	return injectHTTPDirectives(conf, logFormatDirectives(m))
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+	"testing"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+func TestEffectiveNginxConfLogFormat(t *testing.T) {
+	m := newTestNginxCluster("log-format")
+	m.Spec.NginxConf = ""
+	m.Spec.LogFormat = &nginxv1.LogFormatSpec{Name: "upstream", Format: `$remote_addr "$request" $status 'it''s'`}
+	conf := effectiveNginxConf(m)
+	want := "http {\n" +
+		`    log_format upstream '$remote_addr "$request" $status \'it\'\'s\'';` + "\n" +
+		"    access_log /var/log/nginx/access.log upstream;\n"
+	if !strings.Contains(conf, want) {
+		t.Fatalf("log format missing from the http block:\n%s", conf)
+	}
+	hash := calculateConfigHash(conf)
+
+	m.Spec.LogFormat = &nginxv1.LogFormatSpec{Preset: "json"}
+	conf = effectiveNginxConf(m)
+	if !strings.Contains(conf, `    log_format main escape=json '{"time":"$time_iso8601",`) || !strings.Contains(conf, "access_log /var/log/nginx/access.log main;") {
+		t.Fatalf("json preset missing from the http block:\n%s", conf)
+	}
+	if calculateConfigHash(conf) == hash {
+		t.Fatalf("log format not part of the config hash")
+	}
+
+	// Sampling applies to the access_log of the format
+	m.Spec.LogSampling = &nginxv1.LogSamplingSpec{Rate: "0.5"}
+	if conf := effectiveNginxConf(m); !strings.Contains(conf, "access_log /var/log/nginx/access.log main if="+logSampledVariable+";") || strings.Count(conf, "access_log") != 1 {
+		t.Fatalf("expected the sampled access_log of the format only:\n%s", conf)
+	}
+
+	// Only the default configs are changed
+	m.Spec.LogSampling = nil
+	m.Spec.NginxConf = "events {}\nhttp {\n}\n"
+	if conf := effectiveNginxConf(m); strings.Contains(conf, "log_format") {
+		t.Fatalf("log format added to the spec config:\n%s", conf)
+	}
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// rolloutDurationSeconds observes how long it took completed config rollouts
+// to reach all replicas
+var rolloutDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name: "nginxcluster_rollout_duration_seconds",
+	Help: "Time from a config change of a NginxCluster until all its replicas run the config",
+	// 5s to about 40m
+	Buckets: prometheus.ExponentialBuckets(5, 2, 10),
+}, []string{"namespace", "name"})
+
+func init() {
+	// Served on the manager's metrics endpoint
+	metrics.Registry.MustRegister(rolloutDurationSeconds)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+const (
+	metricsServiceNameSuffix = "-metrics"
+	// metricsServiceLabel tags the metrics Services for ServiceMonitors to
+	// select
+	metricsServiceLabel = "nginx.example.com/metrics"
+)
+
+// reconcileMetricsService creates or updates the metrics Service when it is
+// requested in the spec, and removes a previously created one otherwise.
+// A Service of the name the operator doesn't manage, e.g. the one of a
+// cluster named <name>-metrics, is left alone.
+func (r *NginxClusterReconciler) reconcileMetricsService(ctx context.Context, m *nginxv1.NginxCluster) error {
+	logger := log.FromContext(ctx)
+
+	srv := &corev1.Service{}
+	err := r.Get(ctx, types.NamespacedName{Name: m.Name + metricsServiceNameSuffix, Namespace: workloadNamespace(m)}, srv)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+	if exists && !r.isOwnedBy(srv, m) {
+		if m.Spec.MetricsService {
+			logger.Info("Metrics Service name is taken, leaving it alone", "Service.Namespace", srv.Namespace, "Service.Name", srv.Name)
+		}
+		return nil
+	}
+
+	if !m.Spec.MetricsService {
+		if exists {
+			logger.Info("Deleting metrics Service", "Service.Namespace", srv.Namespace, "Service.Name", srv.Name)
+			if err := r.Delete(ctx, srv); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+		return nil
+	}
+
+	desired := r.metricsServiceForNginxCluster(m)
+	if !exists {
+		logger.Info("Creating a new metrics Service", "Service.Namespace", desired.Namespace, "Service.Name", desired.Name)
+		return r.createObject(ctx, desired)
+	}
+	if !sameServicePorts(srv.Spec.Ports, desired.Spec.Ports) || srv.Labels[metricsServiceLabel] != "true" {
+		logger.Info("Updating metrics Service", "Service.Namespace", srv.Namespace, "Service.Name", srv.Name)
+		return r.updateObject(ctx, srv, desired, func() {
+			srv.Spec.Ports = desired.Spec.Ports
+			if srv.Labels == nil {
+				srv.Labels = map[string]string{}
+			}
+			srv.Labels[metricsServiceLabel] = "true"
+		})
+	}
+	return nil
+}
+
+// metricsServiceForNginxCluster returns the metrics Service, selecting the
+// pods of m like the main Service does
+func (r *NginxClusterReconciler) metricsServiceForNginxCluster(m *nginxv1.NginxCluster) *corev1.Service {
+	labels := labelsForNginxCluster(m)
+	labels[metricsServiceLabel] = "true"
+	port := metricsPortForNginxCluster(m)
+	srv := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      m.Name + metricsServiceNameSuffix,
+			Namespace: workloadNamespace(m),
+			Labels:    labels,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeClusterIP,
+			Selector: labelsForNginxCluster(m),
+			Ports: []corev1.ServicePort{{
+				Name:       "metrics",
+				Port:       port,
+				TargetPort: intstr.FromInt32(port),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	}
+	r.setOwner(m, srv)
+	return srv
+}
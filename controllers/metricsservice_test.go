@@ -0,0 +1,75 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestMetricsServiceForNginxCluster(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("metrics-service")
+	m.Spec.MetricsPort = 9145
+	srv := r.metricsServiceForNginxCluster(m)
+	if srv.Name != "metrics-service-metrics" || srv.Labels[metricsServiceLabel] != "true" || srv.Spec.Type != corev1.ServiceTypeClusterIP {
+		t.Fatalf("unexpected metrics Service %+v", srv.ObjectMeta)
+	}
+	if len(srv.Spec.Ports) != 1 || srv.Spec.Ports[0].Name != "metrics" || srv.Spec.Ports[0].Port != 9145 || srv.Spec.Ports[0].TargetPort.IntVal != 9145 {
+		t.Fatalf("unexpected ports %+v", srv.Spec.Ports)
+	}
+	if _, ok := srv.Spec.Selector[metricsServiceLabel]; ok {
+		t.Fatalf("the metrics label is part of the pod selector")
+	}
+	if err := checkControllerOwner(srv, m); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReconcileMetricsServiceLifecycle(t *testing.T) {
+	requireEnvtest(t)
+	ctx := context.Background()
+
+	m := newTestNginxCluster("metrics-lifecycle")
+	m.Spec.MetricsService = true
+	createTestNginxCluster(t, m)
+	key := types.NamespacedName{Name: m.Name + metricsServiceNameSuffix, Namespace: m.Namespace}
+	eventually(t, func() error {
+		return k8sClient.Get(ctx, key, &corev1.Service{})
+	})
+
+	eventually(t, func() error {
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(m), m); err != nil {
+			return err
+		}
+		m.Spec.MetricsService = false
+		return k8sClient.Update(ctx, m)
+	})
+	eventually(t, func() error {
+		err := k8sClient.Get(ctx, key, &corev1.Service{})
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("metrics Service still present: %v", err)
+	})
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// nameConflictRetryInterval is how often a cluster whose resource names are
+// taken checks whether they were released
+const nameConflictRetryInterval = time.Minute
+
+// conflictingOwner returns the namespace/name of another NginxCluster
+// managing obj, or "" if obj is free or managed by m. Clusters with the same
+// name in different namespaces collide when they share a target namespace.
+func conflictingOwner(obj metav1.Object, m *nginxv1.NginxCluster) string {
+	if ref := metav1.GetControllerOf(obj); ref != nil && ref.Kind == "NginxCluster" {
+		if gv, err := schema.ParseGroupVersion(ref.APIVersion); err == nil && gv.Group == nginxv1.GroupVersion.Group && ref.UID != m.UID {
+			return obj.GetNamespace() + "/" + ref.Name
+		}
+	}
+	labels := obj.GetLabels()
+	name, namespace := labels[managedByNameLabel], labels[managedByNamespaceLabel]
+	if name != "" && namespace != "" && (name != m.Name || namespace != m.Namespace) {
+		return namespace + "/" + name
+	}
+	return ""
+}
+
+// reportNameConflict marks m as degraded because obj, which m would manage,
+// belongs to another NginxCluster. obj is left alone, and m retries
+// periodically so it heals once the other cluster releases the name.
+func (r *NginxClusterReconciler) reportNameConflict(ctx context.Context, m *nginxv1.NginxCluster, obj client.Object, kind, other string) (ctrl.Result, error) {
+	degraded := metav1.Condition{
+		Type:               nginxv1.ConditionDegraded,
+		Status:             metav1.ConditionTrue,
+		Reason:             "NameConflict",
+		Message:            fmt.Sprintf("%s %s/%s is managed by NginxCluster %s", kind, obj.GetNamespace(), obj.GetName(), other),
+		ObservedGeneration: m.Generation,
+	}
+	if cond := meta.FindStatusCondition(m.Status.Conditions, nginxv1.ConditionDegraded); cond == nil || cond.Message != degraded.Message {
+		r.recordEvent(m, corev1.EventTypeWarning, degraded.Reason, degraded.Message)
+	}
+	err := r.updateStatusWithRetry(ctx, m, func() {
+		meta.SetStatusCondition(&m.Status.Conditions, degraded)
+	})
+	return ctrl.Result{RequeueAfter: nameConflictRetryInterval}, err
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestConflictingOwnerInSharedTargetNamespace(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	a := newTestNginxCluster("web")
+	a.Namespace = "team-a"
+	a.Spec.TargetNamespace = "shared"
+	b := a.DeepCopy()
+	b.Namespace = "team-b"
+
+	for _, obj := range []client.Object{
+		r.configMapForNginxCluster(a, effectiveNginxConf(a), "hash"),
+		r.deploymentForNginxCluster(a, "hash"),
+	} {
+		if other := conflictingOwner(obj, a); other != "" {
+			t.Errorf("%T of its own cluster reported as owned by %s", obj, other)
+		}
+		if other := conflictingOwner(obj, b); other != "team-a/web" {
+			t.Errorf("%T: conflictingOwner() = %q, want team-a/web", obj, other)
+		}
+	}
+}
+
+func TestConflictingOwnerReference(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	old := newTestNginxCluster("recreated")
+	old.UID = "old-uid"
+	m := old.DeepCopy()
+	m.UID = "new-uid"
+
+	dep := r.deploymentForNginxCluster(old, "hash")
+	if other := conflictingOwner(dep, m); other != "default/recreated" {
+		t.Fatalf("conflictingOwner() = %q, want default/recreated", other)
+	}
+
+	// Resources created outside the operator can be adopted
+	dep.OwnerReferences = nil
+	if other := conflictingOwner(dep, m); other != "" {
+		t.Fatalf("unowned Deployment reported as owned by %s", other)
+	}
+}
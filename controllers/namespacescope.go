@@ -0,0 +1,36 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+// inNamespaceScope reports whether NginxClusters in namespace are managed by
+// this reconciler, according to WatchNamespaces and ExcludeNamespaces
+func (r *NginxClusterReconciler) inNamespaceScope(namespace string) bool {
+	for _, ns := range r.ExcludeNamespaces {
+		if ns == namespace {
+			return false
+		}
+	}
+	if len(r.WatchNamespaces) == 0 {
+		return true
+	}
+	for _, ns := range r.WatchNamespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
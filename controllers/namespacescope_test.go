@@ -0,0 +1,50 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestInNamespaceScope(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	if !r.inNamespaceScope("default") {
+		t.Fatalf("expected all namespaces to be managed by default")
+	}
+
+	r.WatchNamespaces = []string{"team-a", "team-b"}
+	r.ExcludeNamespaces = []string{"team-b"}
+	for ns, want := range map[string]bool{"team-a": true, "team-b": false, "default": false} {
+		if got := r.inNamespaceScope(ns); got != want {
+			t.Errorf("inNamespaceScope(%q) = %v, want %v", ns, got, want)
+		}
+	}
+}
+
+func TestReconcileSkipsExcludedNamespace(t *testing.T) {
+	// Without a client, anything but an early return would panic
+	r := &NginxClusterReconciler{Scheme: testScheme, ExcludeNamespaces: []string{"excluded"}}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "skipped", Namespace: "excluded"}}
+	result, err := r.Reconcile(context.Background(), req)
+	if err != nil || result != (ctrl.Result{}) {
+		t.Fatalf("Reconcile() = %+v, %v for an excluded namespace", result, err)
+	}
+}
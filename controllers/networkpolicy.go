@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// reconcileNetworkPolicy creates or updates the NetworkPolicy when it is
+// requested in the spec, and removes a previously created one otherwise.
+func (r *NginxClusterReconciler) reconcileNetworkPolicy(ctx context.Context, m *nginxv1.NginxCluster) error {
+	logger := log.FromContext(ctx)
+
+	policy := &networkingv1.NetworkPolicy{}
+	err := r.Get(ctx, types.NamespacedName{Name: m.Name, Namespace: workloadNamespace(m)}, policy)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+
+	if m.Spec.NetworkPolicy == nil {
+		if exists && r.isOwnedBy(policy, m) {
+			logger.Info("Deleting NetworkPolicy", "NetworkPolicy.Namespace", policy.Namespace, "NetworkPolicy.Name", policy.Name)
+			if err := r.Delete(ctx, policy); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+		return nil
+	}
+
+	desired := r.networkPolicyForNginxCluster(m)
+	if !exists {
+		logger.Info("Creating a new NetworkPolicy", "NetworkPolicy.Namespace", desired.Namespace, "NetworkPolicy.Name", desired.Name)
+		return r.createObject(ctx, desired)
+	}
+
+	if !reflect.DeepEqual(policy.Spec, desired.Spec) {
+		logger.Info("Updating NetworkPolicy", "NetworkPolicy.Namespace", policy.Namespace, "NetworkPolicy.Name", policy.Name)
+		return r.updateObject(ctx, policy, desired, func() {
+			policy.Spec = desired.Spec
+		})
+	}
+	return nil
+}
+
+// networkPolicyForNginxCluster returns a NetworkPolicy object
+func (r *NginxClusterReconciler) networkPolicyForNginxCluster(m *nginxv1.NginxCluster) *networkingv1.NetworkPolicy {
+	spec := m.Spec.NetworkPolicy
+
+	var ingress []networkingv1.NetworkPolicyIngressRule
+	if len(spec.AllowedIngressPorts) > 0 || len(spec.AllowedNamespaceSelectors) > 0 {
+		rule := networkingv1.NetworkPolicyIngressRule{}
+		for _, port := range spec.AllowedIngressPorts {
+			protocol := corev1.ProtocolTCP
+			p := intstr.FromInt32(port)
+			rule.Ports = append(rule.Ports, networkingv1.NetworkPolicyPort{
+				Protocol: &protocol,
+				Port:     &p,
+			})
+		}
+		for i := range spec.AllowedNamespaceSelectors {
+			rule.From = append(rule.From, networkingv1.NetworkPolicyPeer{
+				NamespaceSelector: spec.AllowedNamespaceSelectors[i].DeepCopy(),
+			})
+		}
+		ingress = append(ingress, rule)
+	}
+
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      m.Name,
+			Namespace: workloadNamespace(m),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: labelsForNginxCluster(m),
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress:     ingress,
+		},
+	}
+	r.setOwner(m, policy)
+	return policy
+}
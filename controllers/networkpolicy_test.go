@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+func TestNetworkPolicyDefaultDeny(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("netpol-deny")
+	m.Spec.NetworkPolicy = &nginxv1.NetworkPolicySpec{}
+
+	policy := r.networkPolicyForNginxCluster(m)
+	if len(policy.Spec.Ingress) != 0 {
+		t.Fatalf("expected no ingress rules, got %d", len(policy.Spec.Ingress))
+	}
+	if len(policy.Spec.PolicyTypes) != 1 || policy.Spec.PolicyTypes[0] != "Ingress" {
+		t.Fatalf("unexpected policy types %v", policy.Spec.PolicyTypes)
+	}
+	if policy.Spec.PodSelector.MatchLabels["cluster"] != m.Name {
+		t.Fatalf("pod selector does not select cluster pods: %v", policy.Spec.PodSelector.MatchLabels)
+	}
+}
+
+func TestNetworkPolicyAllowedPeers(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("netpol-allow")
+	m.Spec.NetworkPolicy = &nginxv1.NetworkPolicySpec{
+		AllowedIngressPorts: []int32{80, 8080},
+		AllowedNamespaceSelectors: []metav1.LabelSelector{{
+			MatchLabels: map[string]string{"team": "web"},
+		}},
+	}
+
+	policy := r.networkPolicyForNginxCluster(m)
+	if len(policy.Spec.Ingress) != 1 {
+		t.Fatalf("expected one ingress rule, got %d", len(policy.Spec.Ingress))
+	}
+	rule := policy.Spec.Ingress[0]
+	if len(rule.Ports) != 2 || rule.Ports[1].Port.IntVal != 8080 {
+		t.Fatalf("unexpected ports %v", rule.Ports)
+	}
+	if len(rule.From) != 1 || rule.From[0].NamespaceSelector.MatchLabels["team"] != "web" {
+		t.Fatalf("unexpected peers %v", rule.From)
+	}
+	if metav1.GetControllerOf(policy) == nil {
+		t.Fatalf("expected controller reference on NetworkPolicy")
+	}
+}
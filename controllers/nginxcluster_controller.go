@@ -19,18 +19,25 @@ package controllers
 import (
 	"context"
 	"crypto/sha256"
+	"encoding/json"
+	stderrors "errors"
 	"fmt"
+	"strings"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	nginxv1 "github.com/example/nginx-operator/api/v1"
@@ -39,26 +46,162 @@ import (
 const (
 	nginxClusterFinalizer = "nginx.example.com/finalizer"
 	configMapNameSuffix   = "-nginx-config"
+
+	// effectiveConfigLabel marks the ConfigMap holding the rendered nginx.conf
+	effectiveConfigLabel = "nginx.example.com/effective-config"
+
+	defaultRevisionHistoryLimit    int32 = 3
+	defaultProgressDeadlineSeconds int32 = 120
+
+	cacheVolumeName      = "nginx-cache"
+	cacheVolumeMountPath = "/var/cache/nginx"
 )
 
 // NginxClusterReconciler reconciles a NginxCluster object
 type NginxClusterReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// Recorder emits events on NginxClusters. Events are dropped when nil.
+	Recorder record.EventRecorder
+
+	// DisableOwnerReferences tags managed resources with management labels
+	// instead of owner references, for clusters where the NginxCluster does not
+	// live next to its resources. Cleanup is then done by the finalizer.
+	DisableOwnerReferences bool
+
+	// DisableBlockOwnerDeletion sets blockOwnerDeletion to false on the owner
+	// references of the managed resources, so foreground deletion of a
+	// NginxCluster doesn't wait for them. The garbage collector then deletes
+	// them in the background after their owner.
+	DisableBlockOwnerDeletion bool
+
+	// ConfigPropagationDelay is how long a config change to the generated
+	// ConfigMap is held back before the pods are restarted with it, so the
+	// kubelet has seen the update. Pods are restarted right away when zero.
+	ConfigPropagationDelay time.Duration
+
+	// RolloutPollInterval is how often a cluster is reconciled while its
+	// Deployment rolls out, so the Progressing condition and the replica
+	// counts follow the rollout without waiting for the next Deployment event.
+	// Polling stops once the rollout completes, and falls back to
+	// imagePullCheckInterval when zero.
+	RolloutPollInterval time.Duration
+
+	// DisableGRPCProbes replaces gRPC health checks with TCP socket probes,
+	// for API servers older than Kubernetes 1.24
+	DisableGRPCProbes bool
+
+	// WatchNamespaces limits the reconciler to NginxClusters in these
+	// namespaces; all namespaces are managed when empty. ExcludeNamespaces
+	// takes precedence. Unlike the namespaces of the manager's cache, these
+	// only filter requests, so another operator instance can manage the rest.
+	WatchNamespaces   []string
+	ExcludeNamespaces []string
+
+	// UseServerSideApply writes the managed resources with server-side apply
+	// as the nginx-operator field manager, so fields other controllers set on
+	// them are left alone. Shared Services, which several clusters write, and
+	// the NginxCluster itself are still updated.
+	UseServerSideApply bool
+
+	// UpstreamDNSPreflight looks up the upstream hosts of the nginx config
+	// from the operator before rolling it out. A config with a host that
+	// doesn't resolve, which nginx would fail to start with, is held back and
+	// reported in the Degraded condition.
+	UpstreamDNSPreflight bool
+
+	// resolver serves the upstream DNS preflight, net.DefaultResolver when nil
+	resolver hostResolver
+
+	// MaxConcurrentRollouts limits how many clusters roll out at the same
+	// time. Config rollouts beyond the limit wait, with the
+	// WaitingForRolloutSlot condition, until another cluster completes its
+	// rollout. Unlimited when zero.
+	MaxConcurrentRollouts int
+
+	// ServiceReachabilityCheck sends a GET to the health check path through
+	// the cluster IP of each cluster's Service, and reports the result in the
+	// ServiceReachable condition. The operator must run in the cluster
+	// network for the check to succeed. Converged clusters are then
+	// reconciled every serviceReachabilityInterval too.
+	ServiceReachabilityCheck bool
+
+	// SkipSelectorOverlapCheck turns off the check for pods not run by the
+	// cluster's Deployment that match its Service selector, reported in the
+	// SelectorOverlap condition. The check looks up the owner of every such
+	// pod, which adds up in namespaces with many of them.
+	SkipSelectorOverlapCheck bool
+
+	// httpClient sends the Service reachability checks, http.DefaultClient
+	// when nil
+	httpClient httpDoer
+
+	// LockConfigMap pauses the reconciliation of all clusters while it has
+	// paused: "true", e.g. during maintenance. Paused clusters check it
+	// again every lockRecheckInterval. Disabled when the name is empty.
+	LockConfigMap types.NamespacedName
+
+	// ReconcileHistorySize is the number of recent reconciles kept per
+	// cluster for ReconcileHistoryHandler. None are kept when zero.
+	ReconcileHistorySize int
+
+	// ReconcileSink receives the summary of every reconcile, e.g. to push
+	// it to an audit pipeline. Send must not block. None when nil.
+	ReconcileSink ReconcileSink
+
+	dirty    dirtyClusters
+	rollouts rolloutSlots
+	history  reconcileHistory
 }
 
 //+kubebuilder:rbac:groups=nginx.example.com,resources=nginxclusters,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=nginx.example.com,resources=nginxclusters/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=nginx.example.com,resources=nginxclusters/finalizers,verbs=update
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps,resources=replicasets,verbs=get;list;watch
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=resourcequotas,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=autoscaling.k8s.io,resources=verticalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=route.openshift.io,resources=routes/custom-host,verbs=create
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 func (r *NginxClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if !r.inNamespaceScope(req.Namespace) {
+		log.FromContext(ctx).V(1).Info("Namespace not managed by this operator, ignoring NginxCluster")
+		return ctrl.Result{}, nil
+	}
+	if paused, err := r.pausedByLock(ctx); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to get lock ConfigMap", "configMap", r.LockConfigMap)
+		return ctrl.Result{}, err
+	} else if paused {
+		log.FromContext(ctx).V(1).Info("Operator paused by lock ConfigMap, skipping NginxCluster", "configMap", r.LockConfigMap)
+		return ctrl.Result{RequeueAfter: lockRecheckInterval}, nil
+	}
+	run := &reconcileRun{}
+	result, err := r.reconcile(ctx, req, run)
+	r.recordReconcile(req.NamespacedName, run, result, err)
+	if err != nil || result.Requeue {
+		// The retry must not be skipped as converged. Delayed requeues wait for
+		// a scheduled restart, which the converged check accounts for itself.
+		r.dirty.mark(req.NamespacedName)
+	}
+	return result, err
+}
+
+func (r *NginxClusterReconciler) reconcile(ctx context.Context, req ctrl.Request, run *reconcileRun) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
+	dirty := r.dirty.take(req.NamespacedName)
 
 	// Fetch the NginxCluster instance
 	nginxCluster := &nginxv1.NginxCluster{}
@@ -67,16 +210,28 @@ func (r *NginxClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		if errors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
 			logger.Info("NginxCluster resource not found. Ignoring since object must be deleted")
+			r.rollouts.track(req.NamespacedName, false)
+			r.history.forget(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		logger.Error(err, "Failed to get NginxCluster")
 		return ctrl.Result{}, err
 	}
 
-	// Add finalizer for this CR
-	if !controllerutil.ContainsFinalizer(nginxCluster, nginxClusterFinalizer) {
-		controllerutil.AddFinalizer(nginxCluster, nginxClusterFinalizer)
-		err = r.Update(ctx, nginxCluster)
+	// Add finalizer for this CR, or drop the one added before it was disabled
+	hasFinalizer := controllerutil.ContainsFinalizer(nginxCluster, nginxClusterFinalizer)
+	if nginxCluster.Spec.DisableFinalizer && hasFinalizer {
+		logger.Info("Removing finalizer, it is disabled in the spec")
+		err = r.updateWithRetry(ctx, nginxCluster, func() {
+			controllerutil.RemoveFinalizer(nginxCluster, nginxClusterFinalizer)
+		})
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	} else if !nginxCluster.Spec.DisableFinalizer && !hasFinalizer {
+		err = r.updateWithRetry(ctx, nginxCluster, func() {
+			controllerutil.AddFinalizer(nginxCluster, nginxClusterFinalizer)
+		})
 		if err != nil {
 			return ctrl.Result{}, err
 		}
@@ -85,15 +240,22 @@ func (r *NginxClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	// Check if the NginxCluster instance is marked to be deleted
 	isNginxClusterMarkedToBeDeleted := nginxCluster.GetDeletionTimestamp() != nil
 	if isNginxClusterMarkedToBeDeleted {
+		r.rollouts.track(req.NamespacedName, false)
 		if controllerutil.ContainsFinalizer(nginxCluster, nginxClusterFinalizer) {
 			// Run finalization logic
-			if err := r.finalizeNginxCluster(ctx, nginxCluster); err != nil {
+			requeueAfter, err := r.finalizeNginxCluster(ctx, nginxCluster)
+			if err != nil {
 				return ctrl.Result{}, err
 			}
+			if requeueAfter > 0 {
+				// Keep the finalizer until the load balancer is released
+				return ctrl.Result{RequeueAfter: requeueAfter}, nil
+			}
 
 			// Remove finalizer
-			controllerutil.RemoveFinalizer(nginxCluster, nginxClusterFinalizer)
-			err := r.Update(ctx, nginxCluster)
+			err = r.updateWithRetry(ctx, nginxCluster, func() {
+				controllerutil.RemoveFinalizer(nginxCluster, nginxClusterFinalizer)
+			})
 			if err != nil {
 				return ctrl.Result{}, err
 			}
@@ -101,47 +263,241 @@ func (r *NginxClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, nil
 	}
 
+	if err := validateSpec(nginxCluster); err != nil {
+		logger.Error(err, "Invalid NginxCluster spec")
+		return r.reportInvalidSpec(ctx, nginxCluster, err)
+	}
+	if ns := nginxCluster.Spec.TargetNamespace; ns != "" {
+		// Retry with backoff, the namespace may still be created
+		if err := r.Get(ctx, types.NamespacedName{Name: ns}, &corev1.Namespace{}); err != nil {
+			logger.Error(err, "Failed to get target namespace", "Namespace", ns)
+			return ctrl.Result{}, err
+		}
+	}
+	// The reachability check has to run even when nothing changed
+	if !dirty && !r.ServiceReachabilityCheck && isConverged(nginxCluster) && !scheduledRestartDue(nginxCluster, time.Now()) {
+		logger.V(1).Info("NginxCluster is converged, skipping reconcile")
+		return ctrl.Result{}, nil
+	}
+
+	if nginxCluster.Spec.EnableHTTP3 {
+		if warning := quicSupportWarning(imageForNginxCluster(nginxCluster)); warning != "" {
+			logger.Info(warning, "Image", imageForNginxCluster(nginxCluster))
+		}
+	}
+
 	// Calculate config hash
-	configHash := calculateConfigHash(nginxCluster.Spec.NginxConf)
+	nginxConf := effectiveNginxConf(nginxCluster)
+	if nginxCluster.Spec.ConfigTemplateFrom != nil {
+		rendered, err := r.renderConfigTemplate(ctx, nginxCluster)
+		var templateErr *configTemplateError
+		if stderrors.As(err, &templateErr) {
+			// Keep the running config until the template or values are fixed
+			logger.Error(err, "Failed to render config template", "ConfigMap.Name", nginxCluster.Spec.ConfigTemplateFrom.Name)
+			degraded := configTemplateCondition(nginxCluster, templateErr)
+			if cond := meta.FindStatusCondition(nginxCluster.Status.Conditions, nginxv1.ConditionDegraded); cond == nil || cond.Message != degraded.Message {
+				r.recordEvent(nginxCluster, corev1.EventTypeWarning, degraded.Reason, degraded.Message)
+			}
+			err = r.updateStatusWithRetry(ctx, nginxCluster, func() {
+				meta.SetStatusCondition(&nginxCluster.Status.Conditions, degraded)
+			})
+			return ctrl.Result{}, err
+		} else if err != nil {
+			logger.Error(err, "Failed to read config template", "ConfigMap.Name", nginxCluster.Spec.ConfigTemplateFrom.Name)
+			return ctrl.Result{}, err
+		}
+		nginxConf = withFeatureDirectives(nginxCluster, rendered)
+	}
+	if nginxCluster.Spec.NginxConfFrom == nil {
+		if held, err := r.holdForInvalidConfig(ctx, nginxCluster, nginxConf); held || err != nil {
+			return ctrl.Result{}, err
+		}
+		// Keep the running config rather than crash-looping on an upstream
+		// that doesn't exist (yet)
+		if held, err := r.holdForUnresolvableUpstreams(ctx, nginxCluster, nginxConf); held || err != nil {
+			return ctrl.Result{RequeueAfter: upstreamDNSRetryInterval}, err
+		}
+	}
+	// Pods of a rollout would not start without the htpasswd Secret
+	if held, err := r.holdForMissingBasicAuthSecret(ctx, nginxCluster); held || err != nil {
+		return ctrl.Result{}, err
+	}
+	configHash := calculateConfigHash(nginxConf)
+	var configMapResourceVersion string
+	var configPropagationWait time.Duration
+	effectiveConfigConfigMap := nginxCluster.Name + configMapNameSuffix
 
-	// Check if ConfigMap already exists, if not create a new one
-	configMap := &corev1.ConfigMap{}
-	err = r.Get(ctx, types.NamespacedName{Name: nginxCluster.Name + configMapNameSuffix, Namespace: nginxCluster.Namespace}, configMap)
-	if err != nil && errors.IsNotFound(err) {
-		// Define a new ConfigMap
-		cm := r.configMapForNginxCluster(nginxCluster, configHash)
-		logger.Info("Creating a new ConfigMap", "ConfigMap.Namespace", cm.Namespace, "ConfigMap.Name", cm.Name)
-		err = r.Create(ctx, cm)
+	if nginxCluster.Spec.NginxConfFrom != nil {
+		// The configuration lives in a user-managed ConfigMap
+		nginxConf, resourceVersion, err := r.externalNginxConf(ctx, nginxCluster)
 		if err != nil {
-			logger.Error(err, "Failed to create new ConfigMap", "ConfigMap.Namespace", cm.Namespace, "ConfigMap.Name", cm.Name)
+			logger.Error(err, "Failed to read referenced nginx configuration", "ConfigMap.Name", nginxCluster.Spec.NginxConfFrom.Name)
+			return ctrl.Result{}, err
+		}
+		if held, err := r.holdForInvalidConfig(ctx, nginxCluster, nginxConf); held || err != nil {
+			return ctrl.Result{}, err
+		}
+		if held, err := r.holdForUnresolvableUpstreams(ctx, nginxCluster, nginxConf); held || err != nil {
+			// The converged check doesn't see changes to the referenced config
+			r.dirty.mark(req.NamespacedName)
+			return ctrl.Result{RequeueAfter: upstreamDNSRetryInterval}, err
+		}
+		configHash = calculateConfigHash(nginxConf)
+		configMapResourceVersion = resourceVersion
+		effectiveConfigConfigMap = nginxCluster.Spec.NginxConfFrom.Name
+
+		// Remove the ConfigMap generated before the reference was set
+		if err := r.deleteGeneratedConfigMap(ctx, nginxCluster); err != nil {
+			logger.Error(err, "Failed to delete generated ConfigMap")
 			return ctrl.Result{}, err
 		}
-	} else if err != nil {
-		logger.Error(err, "Failed to get ConfigMap")
-		return ctrl.Result{}, err
 	} else {
-		// ConfigMap exists, check if config has changed
-		currentConfigHash := configMap.Annotations["config-hash"]
-		if currentConfigHash != configHash {
-			logger.Info("Configuration changed, updating ConfigMap and triggering restart")
-			configMap.Data["nginx.conf"] = nginxCluster.Spec.NginxConf
-			configMap.Annotations["config-hash"] = configHash
-			err = r.Update(ctx, configMap)
+		// Check if ConfigMap already exists, if not create a new one
+		configMap := &corev1.ConfigMap{}
+		err = r.Get(ctx, types.NamespacedName{Name: nginxCluster.Name + configMapNameSuffix, Namespace: workloadNamespace(nginxCluster)}, configMap)
+		if err != nil && errors.IsNotFound(err) {
+			// Define a new ConfigMap
+			cm := r.configMapForNginxCluster(nginxCluster, nginxConf, configHash)
+			cm.Annotations[configUpdatedAtAnnotation] = time.Now().Format(time.RFC3339)
+			logger.Info("Creating a new ConfigMap", "ConfigMap.Namespace", cm.Namespace, "ConfigMap.Name", cm.Name)
+			err = r.createObject(ctx, cm)
 			if err != nil {
-				logger.Error(err, "Failed to update ConfigMap")
+				logger.Error(err, "Failed to create new ConfigMap", "ConfigMap.Namespace", cm.Namespace, "ConfigMap.Name", cm.Name)
 				return ctrl.Result{}, err
 			}
+			configMapResourceVersion = cm.ResourceVersion
+			configPropagationWait = r.configPropagationWait(cm, time.Now())
+		} else if err != nil {
+			logger.Error(err, "Failed to get ConfigMap")
+			return ctrl.Result{}, err
+		} else {
+			// ConfigMap exists, check if config has changed. A ConfigMap created
+			// before the cluster, e.g. during a migration, is adopted and keeps
+			// its content when it already matches.
+			if other := conflictingOwner(configMap, nginxCluster); other != "" {
+				logger.Info("ConfigMap belongs to another NginxCluster", "ConfigMap.Namespace", configMap.Namespace, "ConfigMap.Name", configMap.Name, "NginxCluster", other)
+				return r.reportNameConflict(ctx, nginxCluster, configMap, "ConfigMap", other)
+			}
+			adopt := !r.isOwnedBy(configMap, nginxCluster)
+			if owner := metav1.GetControllerOf(configMap); adopt && owner != nil {
+				err = fmt.Errorf("ConfigMap %s/%s is controlled by %s %s", configMap.Namespace, configMap.Name, owner.Kind, owner.Name)
+				logger.Error(err, "Failed to adopt ConfigMap")
+				return ctrl.Result{}, err
+			}
+			if configMapNeedsRecreate(configMap, nginxCluster, configHash) {
+				oldConf := configMap.Data["nginx.conf"]
+				cm := r.configMapForNginxCluster(nginxCluster, nginxConf, configHash)
+				cm.Annotations[configUpdatedAtAnnotation] = time.Now().Format(time.RFC3339)
+				logger.Info("Recreating immutable ConfigMap", "ConfigMap.Namespace", cm.Namespace, "ConfigMap.Name", cm.Name)
+				if err := r.recreateConfigMap(ctx, configMap, cm); err != nil {
+					logger.Error(err, "Failed to recreate ConfigMap", "ConfigMap.Namespace", cm.Namespace, "ConfigMap.Name", cm.Name)
+					return ctrl.Result{}, err
+				}
+				if oldConf != nginxConf {
+					r.recordEvent(nginxCluster, corev1.EventTypeNormal, "ConfigChanged", configDiffMessage(oldConf, nginxConf))
+				}
+				configMap, adopt = cm, false
+			}
+			currentConfigHash := configMap.Annotations["config-hash"]
+			if adopt || currentConfigHash != configHash || configMap.Labels[effectiveConfigLabel] != "true" || isImmutable(configMap) != nginxCluster.Spec.ImmutableConfig {
+				dataChanged := calculateConfigHash(configMap.Data["nginx.conf"]) != configHash
+				if adopt {
+					logger.Info("Adopting existing ConfigMap", "ConfigMap.Namespace", configMap.Namespace, "ConfigMap.Name", configMap.Name, "Overwrite", dataChanged)
+				} else if dataChanged {
+					logger.Info("Configuration changed, updating ConfigMap and triggering restart")
+				}
+				oldConf := configMap.Data["nginx.conf"]
+				updatedAt := time.Now().Format(time.RFC3339)
+				applied := r.configMapForNginxCluster(nginxCluster, nginxConf, configHash)
+				if dataChanged {
+					applied.Annotations[configUpdatedAtAnnotation] = updatedAt
+				} else if at, ok := configMap.Annotations[configUpdatedAtAnnotation]; ok {
+					applied.Annotations[configUpdatedAtAnnotation] = at
+				}
+				err = r.updateObject(ctx, configMap, applied, func() {
+					if configMap.Data == nil {
+						configMap.Data = map[string]string{}
+					}
+					if configMap.Annotations == nil {
+						configMap.Annotations = map[string]string{}
+					}
+					if configMap.Labels == nil {
+						configMap.Labels = map[string]string{}
+					}
+					if calculateConfigHash(configMap.Data["nginx.conf"]) != configHash {
+						configMap.Data["nginx.conf"] = nginxConf
+						configMap.Annotations[configUpdatedAtAnnotation] = updatedAt
+					}
+					configMap.Annotations["config-hash"] = configHash
+					configMap.Labels[effectiveConfigLabel] = "true"
+					configMap.Immutable = configMapImmutable(nginxCluster)
+					r.setOwner(nginxCluster, configMap)
+				})
+				if err != nil {
+					logger.Error(err, "Failed to update ConfigMap")
+					return ctrl.Result{}, err
+				}
+				if adopt {
+					r.recordEvent(nginxCluster, corev1.EventTypeNormal, "AdoptedConfigMap", fmt.Sprintf("Adopted existing ConfigMap %s", configMap.Name))
+				} else if dataChanged {
+					r.recordEvent(nginxCluster, corev1.EventTypeNormal, "ConfigChanged", configDiffMessage(oldConf, nginxConf))
+				}
+			}
+			configMapResourceVersion = configMap.ResourceVersion
+			configPropagationWait = r.configPropagationWait(configMap, time.Now())
 		}
 	}
 
+	// The reloader sidecar applies nginx.conf changes without a restart
+	configHash = reloadableConfigHash(nginxCluster, configHash)
+
+	if err := r.reconcileErrorPages(ctx, nginxCluster); err != nil {
+		logger.Error(err, "Failed to reconcile error pages ConfigMap")
+		return ctrl.Result{}, err
+	}
+	configHash = withErrorPages(nginxCluster, configHash)
+
+	if err := r.reconcileNjsScripts(ctx, nginxCluster); err != nil {
+		logger.Error(err, "Failed to reconcile njs scripts ConfigMap")
+		return ctrl.Result{}, err
+	}
+	configHash = withNjsScripts(nginxCluster, configHash)
+
+	// Rotating a dependency such as a mounted certificate changes the hash too
+	configHash, err = r.withConfigDependencies(ctx, nginxCluster, configHash)
+	if err != nil {
+		logger.Error(err, "Failed to read config dependencies")
+		return ctrl.Result{}, err
+	}
+	run.configHash = configHash
+
+	// Only the config is distributed to a Deployment managed elsewhere
+	if !manageWorkload(nginxCluster) {
+		return r.reconcileConfigOnly(ctx, nginxCluster, configHash, configMapResourceVersion, effectiveConfigConfigMap, configPropagationWait)
+	}
+
 	// Check if the Deployment already exists, if not create a new one
 	deployment := &appsv1.Deployment{}
-	err = r.Get(ctx, types.NamespacedName{Name: nginxCluster.Name, Namespace: nginxCluster.Namespace}, deployment)
+	err = r.Get(ctx, types.NamespacedName{Name: nginxCluster.Name, Namespace: workloadNamespace(nginxCluster)}, deployment)
 	if err != nil && errors.IsNotFound(err) {
+		// The first pods mount the generated ConfigMap right away, so only
+		// create the Deployment once the ConfigMap can be read back
+		if nginxCluster.Spec.NginxConfFrom == nil {
+			present, err := r.generatedConfigMapPresent(ctx, nginxCluster)
+			if err != nil {
+				logger.Error(err, "Failed to get ConfigMap")
+				return ctrl.Result{}, err
+			}
+			if !present {
+				logger.Info("Waiting for the ConfigMap before creating the Deployment")
+				return ctrl.Result{Requeue: true}, nil
+			}
+		}
+
 		// Define a new deployment
 		dep := r.deploymentForNginxCluster(nginxCluster, configHash)
 		logger.Info("Creating a new Deployment", "Deployment.Namespace", dep.Namespace, "Deployment.Name", dep.Name)
-		err = r.Create(ctx, dep)
+		err = r.createObject(ctx, dep)
 		if err != nil {
 			logger.Error(err, "Failed to create new Deployment", "Deployment.Namespace", dep.Namespace, "Deployment.Name", dep.Name)
 			return ctrl.Result{}, err
@@ -152,12 +508,75 @@ func (r *NginxClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		logger.Error(err, "Failed to get Deployment")
 		return ctrl.Result{}, err
 	}
+	if other := conflictingOwner(deployment, nginxCluster); other != "" {
+		logger.Info("Deployment belongs to another NginxCluster", "Deployment.Namespace", deployment.Namespace, "Deployment.Name", deployment.Name, "NginxCluster", other)
+		return r.reportNameConflict(ctx, nginxCluster, deployment, "Deployment", other)
+	}
+	if nginxCluster.Spec.AdoptExisting && !r.isOwnedBy(deployment, nginxCluster) {
+		rejected, err := r.adoptDeployment(ctx, nginxCluster, deployment)
+		if err != nil {
+			logger.Error(err, "Failed to adopt Deployment", "Deployment.Namespace", deployment.Namespace, "Deployment.Name", deployment.Name)
+			return ctrl.Result{}, err
+		}
+		if rejected {
+			return ctrl.Result{RequeueAfter: nameConflictRetryInterval}, nil
+		}
+	}
+
+	desired := r.deploymentForNginxCluster(nginxCluster, configHash)
+	desired.Spec.MinReadySeconds = rampMinReadySeconds(nginxCluster, deployment, desired, configHash)
 
 	// Ensure the deployment replicas is the same as the spec
-	replicas := nginxCluster.Spec.Replicas
+	replicas := workloadReplicas(nginxCluster)
 	if *deployment.Spec.Replicas != replicas {
-		deployment.Spec.Replicas = &replicas
-		err = r.Update(ctx, deployment)
+		err = r.updateDeployment(ctx, deployment, desired, func(dep *appsv1.Deployment) {
+			dep.Spec.Replicas = &replicas
+		})
+		if err != nil {
+			logger.Error(err, "Failed to update Deployment", "Deployment.Namespace", deployment.Namespace, "Deployment.Name", deployment.Name)
+			return ctrl.Result{}, err
+		}
+		// Spec updated - return and requeue
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// A rollback pins the pod template to an earlier revision instead of the
+	// one derived from the spec
+	replicaSets, err := r.replicaSetsForDeployment(ctx, deployment)
+	if err != nil {
+		logger.Error(err, "Failed to list ReplicaSets")
+		return ctrl.Result{}, err
+	}
+	rollback, rolledBack, err := r.reconcileRollback(ctx, nginxCluster, deployment, desired, replicaSets)
+	if err != nil {
+		logger.Error(err, "Failed to roll back Deployment")
+		return ctrl.Result{}, err
+	}
+	if rolledBack {
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// Stop a rollout whose new pods keep restarting
+	circuit, err := r.rolloutCircuitCondition(ctx, nginxCluster, replicaSets)
+	if err != nil {
+		logger.Error(err, "Failed to check the rollout circuit breaker")
+		return ctrl.Result{}, err
+	}
+	circuitOpen := circuit.Status == metav1.ConditionTrue
+	if circuitOpen && !meta.IsStatusConditionTrue(nginxCluster.Status.Conditions, nginxv1.ConditionRolloutCircuitOpen) {
+		logger.Info("Pausing rollout", "Reason", circuit.Message)
+		r.recordEvent(nginxCluster, corev1.EventTypeWarning, "RolloutCircuitOpen", circuit.Message)
+	}
+
+	// Ensure the remaining deployment settings derived from the spec are up to date
+	if circuitOpen {
+		desired.Spec.Paused = true
+	}
+	if rollback == nil && syncDeploymentSpec(deployment.DeepCopy(), desired) {
+		logger.Info("Deployment spec drifted, updating", "Deployment.Namespace", deployment.Namespace, "Deployment.Name", deployment.Name)
+		err = r.updateDeployment(ctx, deployment, desired, func(dep *appsv1.Deployment) {
+			syncDeploymentSpec(dep, desired)
+		})
 		if err != nil {
 			logger.Error(err, "Failed to update Deployment", "Deployment.Namespace", deployment.Namespace, "Deployment.Name", deployment.Name)
 			return ctrl.Result{}, err
@@ -168,62 +587,306 @@ func (r *NginxClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	// Check if config has changed and trigger rolling update
 	currentPodConfigHash := deployment.Spec.Template.Annotations["config-hash"]
-	if currentPodConfigHash != configHash {
+	if rollback == nil && currentPodConfigHash != configHash {
+		if configPropagationWait > 0 {
+			logger.Info("Configuration changed, waiting for the ConfigMap to propagate before restarting", "After", configPropagationWait)
+			return ctrl.Result{RequeueAfter: configPropagationWait}, nil
+		}
+		if !r.rollouts.acquire(req.NamespacedName, r.MaxConcurrentRollouts) {
+			waiting := waitingForRolloutSlotCondition(nginxCluster, r.rollouts.inFlight(), r.MaxConcurrentRollouts)
+			logger.Info("Configuration changed, waiting for a rollout slot", "Reason", waiting.Message)
+			err = r.updateStatusWithRetry(ctx, nginxCluster, func() {
+				meta.SetStatusCondition(&nginxCluster.Status.Conditions, waiting)
+			})
+			// A referenced config may be the only change, which the converged
+			// check doesn't see
+			r.dirty.mark(req.NamespacedName)
+			return ctrl.Result{RequeueAfter: rolloutSlotRetryInterval}, err
+		}
 		logger.Info("Configuration changed, triggering rolling update of pods")
-		deployment.Spec.Template.Annotations["config-hash"] = configHash
-		// Update restart timestamp to force pod recreation
-		deployment.Spec.Template.Annotations["restartedAt"] = time.Now().Format(time.RFC3339)
-		err = r.Update(ctx, deployment)
+		restartedAt := time.Now().Format(time.RFC3339)
+		err = r.updateDeployment(ctx, deployment, desired, func(dep *appsv1.Deployment) {
+			setConfigRolloutAnnotations(&dep.Spec.Template, nginxCluster, configHash, restartedAt)
+		})
 		if err != nil {
 			logger.Error(err, "Failed to update Deployment for config change")
 			return ctrl.Result{}, err
 		}
 	}
 
-	// Check if the Service already exists, if not create a new one
-	service := &corev1.Service{}
-	err = r.Get(ctx, types.NamespacedName{Name: nginxCluster.Name, Namespace: nginxCluster.Namespace}, service)
-	if err != nil && errors.IsNotFound(err) {
-		// Define a new service
-		srv := r.serviceForNginxCluster(nginxCluster)
-		logger.Info("Creating a new Service", "Service.Namespace", srv.Namespace, "Service.Name", srv.Name)
-		err = r.Create(ctx, srv)
+	// Restart the pods when the restart schedule is due. The schedule starts
+	// counting when it is first observed rather than at creation, so adding it
+	// to an old cluster doesn't restart the pods right away.
+	lastScheduledRestart := nginxCluster.Status.LastScheduledRestartTime
+	if nginxCluster.Spec.RestartSchedule == "" {
+		lastScheduledRestart = nil
+	} else if lastScheduledRestart == nil {
+		observed := metav1.Now()
+		lastScheduledRestart = &observed
+	} else if rollback == nil && scheduledRestartDue(nginxCluster, time.Now()) {
+		logger.Info("Scheduled restart due, triggering rolling update of pods", "RestartSchedule", nginxCluster.Spec.RestartSchedule)
+		restartedAt := metav1.Now()
+		err = r.updateDeployment(ctx, deployment, desired, func(dep *appsv1.Deployment) {
+			if dep.Spec.Template.Annotations == nil {
+				dep.Spec.Template.Annotations = map[string]string{}
+			}
+			dep.Spec.Template.Annotations["restartedAt"] = restartedAt.Format(time.RFC3339)
+		})
 		if err != nil {
-			logger.Error(err, "Failed to create new Service", "Service.Namespace", srv.Namespace, "Service.Name", srv.Name)
+			logger.Error(err, "Failed to update Deployment for scheduled restart")
 			return ctrl.Result{}, err
 		}
-	} else if err != nil {
-		logger.Error(err, "Failed to get Service")
+		lastScheduledRestart = &restartedAt
+	}
+
+	if nginxCluster.Spec.SharedServiceName != "" {
+		// Join the shared Service instead of owning a per-cluster one
+		if err := r.reconcileSharedService(ctx, nginxCluster); err != nil {
+			logger.Error(err, "Failed to reconcile shared Service", "Service.Name", nginxCluster.Spec.SharedServiceName)
+			return ctrl.Result{}, err
+		}
+	} else {
+		// Check if the Service already exists, if not create a new one
+		service := &corev1.Service{}
+		err = r.Get(ctx, types.NamespacedName{Name: nginxCluster.Name, Namespace: workloadNamespace(nginxCluster)}, service)
+		if err != nil && errors.IsNotFound(err) {
+			// Define a new service
+			srv := r.serviceForNginxCluster(nginxCluster)
+			logger.Info("Creating a new Service", "Service.Namespace", srv.Namespace, "Service.Name", srv.Name)
+			err = r.createObject(ctx, srv)
+			if err != nil {
+				logger.Error(err, "Failed to create new Service", "Service.Namespace", srv.Namespace, "Service.Name", srv.Name)
+				return ctrl.Result{}, err
+			}
+		} else if err != nil {
+			logger.Error(err, "Failed to get Service")
+			return ctrl.Result{}, err
+		} else if desired := r.serviceForNginxCluster(nginxCluster); loadBalancerClassChanged(service, desired) {
+			if service.DeletionTimestamp == nil {
+				logger.Info("Recreating Service for the new load balancer class", "Service.Namespace", service.Namespace, "Service.Name", service.Name)
+				if err := r.Delete(ctx, service); err != nil && !errors.IsNotFound(err) {
+					logger.Error(err, "Failed to delete Service", "Service.Namespace", service.Namespace, "Service.Name", service.Name)
+					return ctrl.Result{}, err
+				}
+				r.recordEvent(nginxCluster, corev1.EventTypeNormal, "RecreatingService", "Recreating the Service, the class of its load balancer cannot be changed")
+			}
+			// Wait for the cloud provider to release the old load balancer
+			return ctrl.Result{RequeueAfter: loadBalancerReleasePollInterval}, nil
+		} else if syncServiceSelector(service.DeepCopy(), desired) || !sameServicePorts(service.Spec.Ports, desired.Spec.Ports) || syncServiceLabels(service.DeepCopy(), desired) || syncServiceIPFamilies(service.DeepCopy(), desired) || syncServiceType(service.DeepCopy(), desired, nginxCluster) {
+			// Service exists, bring its selector, ports and labels in line with
+			// the spec. The selector check comes first, as it also carries the
+			// selector keys added by others over to desired.
+			logger.Info("Updating Service", "Service.Namespace", service.Namespace, "Service.Name", service.Name)
+			err = r.updateObject(ctx, service, desired, func() {
+				syncServiceSelector(service, desired)
+				service.Spec.Ports = withNodePorts(desired.Spec.Ports, service.Spec.Ports)
+				syncServiceLabels(service, desired)
+				syncServiceIPFamilies(service, desired)
+				syncServiceType(service, desired, nginxCluster)
+			})
+			if err != nil {
+				logger.Error(err, "Failed to update Service", "Service.Namespace", service.Namespace, "Service.Name", service.Name)
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	// Leave any shared Service this cluster no longer participates in
+	if err := r.releaseSharedServices(ctx, nginxCluster); err != nil {
+		logger.Error(err, "Failed to release shared Services")
+		return ctrl.Result{}, err
+	}
+
+	// Create, update or remove the optional metrics Service
+	if err := r.reconcileMetricsService(ctx, nginxCluster); err != nil {
+		logger.Error(err, "Failed to reconcile metrics Service")
+		return ctrl.Result{}, err
+	}
+
+	// Create, update or remove the optional NetworkPolicy
+	if err := r.reconcileNetworkPolicy(ctx, nginxCluster); err != nil {
+		logger.Error(err, "Failed to reconcile NetworkPolicy")
+		return ctrl.Result{}, err
+	}
+
+	// Create, update or remove the optional OpenShift Route
+	if err := r.reconcileRoute(ctx, nginxCluster); err != nil {
+		logger.Error(err, "Failed to reconcile Route")
+		return ctrl.Result{}, err
+	}
+
+	// Create, update or remove the optional Gateway API HTTPRoute
+	if err := r.reconcileHTTPRoute(ctx, nginxCluster); err != nil {
+		logger.Error(err, "Failed to reconcile HTTPRoute")
+		return ctrl.Result{}, err
+	}
+
+	// Create, update or remove the optional VerticalPodAutoscaler
+	if err := r.reconcileVPA(ctx, nginxCluster); err != nil {
+		logger.Error(err, "Failed to reconcile VerticalPodAutoscaler")
 		return ctrl.Result{}, err
 	}
 
 	// Update the NginxCluster status
-	nginxCluster.Status.Replicas = deployment.Status.Replicas
-	nginxCluster.Status.ReadyReplicas = deployment.Status.ReadyReplicas
-	nginxCluster.Status.ConfigHash = configHash
 	now := metav1.Now()
-	nginxCluster.Status.LastUpdateTime = &now
-
-	err = r.Status().Update(ctx, nginxCluster)
+	pods, err := r.podsForNginxCluster(ctx, nginxCluster)
+	if err != nil {
+		logger.Error(err, "Failed to list pods")
+		return ctrl.Result{}, err
+	}
+	oldestPodAge, newestPodAge := podAges(pods, now.Time)
+	configDrift := configDriftCondition(nginxCluster, pods, configHash, rollback)
+	rolloutState := nextRolloutState(nginxCluster, deployment, configHash, configDrift, rollback, now)
+	rolloutDuration := completedRolloutDuration(nginxCluster.Status.RolloutState, rolloutState)
+	// Point out replicas the namespace quota won't admit instead of leaving the
+	// rollout stuck. Best effort, so a failed check doesn't fail the reconcile.
+	// Skipped when a VPA sets the pod resources, as the template's don't apply.
+	var quotaMessage string
+	if !vpaManagesResources(nginxCluster) {
+		quotaMessage, err = r.resourceQuotaViolation(ctx, nginxCluster, deployment, &desired.Spec.Template)
+		if err != nil {
+			logger.Error(err, "Failed to check ResourceQuotas")
+		}
+	}
+	if quotaMessage != "" && !meta.IsStatusConditionTrue(nginxCluster.Status.Conditions, nginxv1.ConditionDegraded) {
+		logger.Info("Replicas exceed ResourceQuota", "Reason", quotaMessage)
+		r.recordEvent(nginxCluster, corev1.EventTypeWarning, "QuotaExceeded", quotaMessage)
+	}
+	// Best effort as well, the URL is only informational
+	accessURL, err := r.accessURL(ctx, nginxCluster)
+	if err != nil {
+		logger.Error(err, "Failed to determine the access URL")
+		accessURL = nginxCluster.Status.AccessURL
+	}
+	var reachable *metav1.Condition
+	if r.ServiceReachabilityCheck {
+		cond, err := r.serviceReachableCondition(ctx, nginxCluster)
+		if err != nil {
+			logger.Error(err, "Failed to check the Service reachability")
+		} else {
+			reachable = &cond
+		}
+	}
+	var configValid *metav1.Condition
+	if nginxCluster.Spec.ValidationSidecar {
+		cond := r.configValidCondition(ctx, nginxCluster, pods)
+		configValid = &cond
+		if prev := meta.FindStatusCondition(nginxCluster.Status.Conditions, nginxv1.ConditionConfigValid); cond.Status == metav1.ConditionFalse && (prev == nil || prev.Status != metav1.ConditionFalse) {
+			logger.Info("Validation sidecar found the config invalid", "Reason", cond.Message)
+			r.recordEvent(nginxCluster, corev1.EventTypeWarning, "ConfigInvalid", cond.Message)
+		}
+	}
+	// Pods of other workloads with the same labels would receive part of the
+	// Service's traffic
+	var overlap *metav1.Condition
+	if !r.SkipSelectorOverlapCheck {
+		foreign, err := r.foreignPods(ctx, pods, deployment, replicaSets)
+		if err != nil {
+			logger.Error(err, "Failed to check the Service selector for foreign pods")
+		} else {
+			cond := selectorOverlapCondition(nginxCluster, foreign)
+			overlap = &cond
+			if cond.Status == metav1.ConditionTrue && !meta.IsStatusConditionTrue(nginxCluster.Status.Conditions, nginxv1.ConditionSelectorOverlap) {
+				logger.Info("Foreign pods match the Service selector", "Reason", cond.Message)
+				r.recordEvent(nginxCluster, corev1.EventTypeWarning, "SelectorOverlap", cond.Message)
+			}
+		}
+	}
+	progressing := progressingCondition(nginxCluster, deployment)
+	// Rollouts count against the limit until they complete
+	r.rollouts.track(req.NamespacedName, progressing.Status == metav1.ConditionTrue)
+	degraded := degradedCondition(nginxCluster, quotaMessage)
+	// A typo in the image tag would otherwise only show as a stuck rollout
+	if pullMessage := imagePullFailure(pods); pullMessage != "" {
+		degraded = imagePullCondition(nginxCluster, pullMessage)
+		if cond := meta.FindStatusCondition(nginxCluster.Status.Conditions, nginxv1.ConditionDegraded); cond == nil || cond.Reason != degraded.Reason {
+			logger.Info("Failed to pull image", "Reason", pullMessage)
+			r.recordEvent(nginxCluster, corev1.EventTypeWarning, degraded.Reason, pullMessage)
+		}
+	}
+	err = r.updateStatusWithRetry(ctx, nginxCluster, func() {
+		nginxCluster.Status.Replicas = deployment.Status.Replicas
+		nginxCluster.Status.ReadyReplicas = deployment.Status.ReadyReplicas
+		nginxCluster.Status.UpdatedReplicas = deployment.Status.UpdatedReplicas
+		nginxCluster.Status.AvailableReplicas = deployment.Status.AvailableReplicas
+		nginxCluster.Status.ConfigHash = configHash
+		nginxCluster.Status.ConfigOnly = false
+		nginxCluster.Status.ConfigMapResourceVersion = configMapResourceVersion
+		nginxCluster.Status.EffectiveConfigConfigMap = effectiveConfigConfigMap
+		nginxCluster.Status.ObservedGeneration = nginxCluster.Generation
+		nginxCluster.Status.LastUpdateTime = &now
+		nginxCluster.Status.OldestPodAge = oldestPodAge
+		nginxCluster.Status.NewestPodAge = newestPodAge
+		nginxCluster.Status.LastScheduledRestartTime = lastScheduledRestart
+		nginxCluster.Status.Revisions = revisionsForReplicaSets(replicaSets)
+		nginxCluster.Status.Rollback = rollback
+		nginxCluster.Status.ConfigInSync = configDrift.Status == metav1.ConditionFalse
+		nginxCluster.Status.RolloutState = rolloutState
+		nginxCluster.Status.AccessURL = accessURL
+		if rolloutDuration != nil {
+			nginxCluster.Status.LastRolloutDuration = rolloutDuration
+		}
+		if reachable != nil {
+			nginxCluster.Status.ServiceReachable = reachable.Status == metav1.ConditionTrue
+			meta.SetStatusCondition(&nginxCluster.Status.Conditions, *reachable)
+		} else if !r.ServiceReachabilityCheck {
+			nginxCluster.Status.ServiceReachable = false
+			meta.RemoveStatusCondition(&nginxCluster.Status.Conditions, nginxv1.ConditionServiceReachable)
+		}
+		if configValid != nil {
+			nginxCluster.Status.ConfigValid = configValid.Status == metav1.ConditionTrue
+			meta.SetStatusCondition(&nginxCluster.Status.Conditions, *configValid)
+		} else {
+			nginxCluster.Status.ConfigValid = false
+			meta.RemoveStatusCondition(&nginxCluster.Status.Conditions, nginxv1.ConditionConfigValid)
+		}
+		if overlap != nil {
+			meta.SetStatusCondition(&nginxCluster.Status.Conditions, *overlap)
+		} else if r.SkipSelectorOverlapCheck {
+			meta.RemoveStatusCondition(&nginxCluster.Status.Conditions, nginxv1.ConditionSelectorOverlap)
+		}
+		meta.SetStatusCondition(&nginxCluster.Status.Conditions, rolloutPausedCondition(nginxCluster, deployment))
+		meta.SetStatusCondition(&nginxCluster.Status.Conditions, progressing)
+		meta.SetStatusCondition(&nginxCluster.Status.Conditions, degraded)
+		meta.SetStatusCondition(&nginxCluster.Status.Conditions, circuit)
+		meta.SetStatusCondition(&nginxCluster.Status.Conditions, configDrift)
+		meta.RemoveStatusCondition(&nginxCluster.Status.Conditions, nginxv1.ConditionReady)
+		if meta.FindStatusCondition(nginxCluster.Status.Conditions, nginxv1.ConditionWaitingForRolloutSlot) != nil {
+			meta.SetStatusCondition(&nginxCluster.Status.Conditions, rolloutSlotAcquiredCondition(nginxCluster))
+		}
+	})
 	if err != nil {
 		logger.Error(err, "Failed to update NginxCluster status")
 		return ctrl.Result{}, err
 	}
+	// Reported once the completion is recorded, so a failed status update
+	// doesn't count the rollout twice
+	r.completeRollout(nginxCluster, rolloutState, rolloutDuration, deployment.Status.UpdatedReplicas)
 
-	return ctrl.Result{}, nil
-}
-
-// configMapForNginxCluster returns a ConfigMap object
-func (r *NginxClusterReconciler) configMapForNginxCluster(m *nginxv1.NginxCluster, configHash string) *corev1.ConfigMap {
-	nginxConf := m.Spec.NginxConf
-	if nginxConf == "" {
-		nginxConf = getDefaultNginxConf()
+	// Come back for the next scheduled restart, if any
+	requeueAfter := requeueForRestartSchedule(nginxCluster, now.Time)
+	// Follow a rollout in progress without waiting for Deployment events
+	requeueAfter = r.rolloutRequeueAfter(progressing, requeueAfter)
+	// Nothing else signals that nginx stopped answering through the Service
+	if r.ServiceReachabilityCheck && (requeueAfter == 0 || requeueAfter > serviceReachabilityInterval) {
+		requeueAfter = serviceReachabilityInterval
+	}
+	// nor that the validation sidecars found the config broken
+	if nginxCluster.Spec.ValidationSidecar && (requeueAfter == 0 || requeueAfter > validationRecheckInterval) {
+		requeueAfter = validationRecheckInterval
 	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
 
+// configMapForNginxCluster returns the generated ConfigMap holding nginxConf
+func (r *NginxClusterReconciler) configMapForNginxCluster(m *nginxv1.NginxCluster, nginxConf, configHash string) *corev1.ConfigMap {
 	cm := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      m.Name + configMapNameSuffix,
-			Namespace: m.Namespace,
+			Namespace: workloadNamespace(m),
+			Labels: map[string]string{
+				effectiveConfigLabel: "true",
+			},
 			Annotations: map[string]string{
 				"config-hash": configHash,
 			},
@@ -231,116 +894,561 @@ func (r *NginxClusterReconciler) configMapForNginxCluster(m *nginxv1.NginxCluste
 		Data: map[string]string{
 			"nginx.conf": nginxConf,
 		},
+		Immutable: configMapImmutable(m),
 	}
-	// Set NginxCluster instance as the owner and controller
-	ctrl.SetControllerReference(m, cm, r.Scheme)
+	stampOperatorVersion(cm)
+	r.setOwner(m, cm)
 	return cm
 }
 
+// generatedConfigMapPresent reports whether the generated ConfigMap of m can
+// be read. A ConfigMap just created may not be in the cache yet.
+func (r *NginxClusterReconciler) generatedConfigMapPresent(ctx context.Context, m *nginxv1.NginxCluster) (bool, error) {
+	err := r.Get(ctx, types.NamespacedName{Name: m.Name + configMapNameSuffix, Namespace: workloadNamespace(m)}, &corev1.ConfigMap{})
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
 // deploymentForNginxCluster returns a Deployment object
 func (r *NginxClusterReconciler) deploymentForNginxCluster(m *nginxv1.NginxCluster, configHash string) *appsv1.Deployment {
-	replicas := m.Spec.Replicas
-	image := m.Spec.Image
-	if image == "" {
-		image = "nginx:latest"
+	replicas := workloadReplicas(m)
+	image := imageForNginxCluster(m)
+	revisionHistoryLimit := defaultRevisionHistoryLimit
+	if m.Spec.RevisionHistoryLimit != nil {
+		revisionHistoryLimit = *m.Spec.RevisionHistoryLimit
+	}
+	progressDeadlineSeconds := defaultProgressDeadlineSeconds
+	if m.Spec.ProgressDeadlineSeconds != nil {
+		progressDeadlineSeconds = *m.Spec.ProgressDeadlineSeconds
 	}
 
-	labels := map[string]string{
-		"app":     "nginx",
-		"cluster": m.Name,
+	var minReadySeconds int32
+	if ramp := m.Spec.RolloutRamp; ramp != nil && len(ramp.Steps) > 0 {
+		minReadySeconds = ramp.Steps[0].MinReadySeconds
 	}
 
+	labels := labelsForNginxCluster(m)
+
 	dep := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      m.Name,
-			Namespace: m.Namespace,
+			Namespace: workloadNamespace(m),
 		},
 		Spec: appsv1.DeploymentSpec{
-			Replicas: &replicas,
+			Replicas:                &replicas,
+			RevisionHistoryLimit:    &revisionHistoryLimit,
+			ProgressDeadlineSeconds: &progressDeadlineSeconds,
+			MinReadySeconds:         minReadySeconds,
+			Paused:                  m.Spec.HoldRollout,
 			Selector: &metav1.LabelSelector{
 				MatchLabels: labels,
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: labels,
+					Labels: podLabelsForNginxCluster(m),
 					Annotations: map[string]string{
 						"config-hash": configHash,
 					},
 				},
 				Spec: corev1.PodSpec{
+					Affinity:        affinityForNginxCluster(m),
+					SecurityContext: podSecurityContextForNginxCluster(m),
+					SchedulingGates: append([]corev1.PodSchedulingGate(nil), m.Spec.SchedulingGates...),
+					ReadinessGates:  append([]corev1.PodReadinessGate(nil), m.Spec.ReadinessGates...),
 					Containers: []corev1.Container{{
-						Image: image,
-						Name:  "nginx",
-						Ports: []corev1.ContainerPort{{
-							ContainerPort: 80,
-							Name:          "http",
-						}},
+						Image:                    image,
+						Name:                     containerNameForNginxCluster(m),
+						WorkingDir:               m.Spec.WorkingDir,
+						Env:                      envForNginxCluster(m),
+						Ports:                    containerPortsForNginxCluster(m),
+						TerminationMessagePolicy: m.Spec.TerminationMessagePolicy,
+						TerminationMessagePath:   m.Spec.TerminationMessagePath,
+						SecurityContext:          containerSecurityContextForNginxCluster(m),
+						Resources:                resourcesForNginxCluster(m),
 						VolumeMounts: []corev1.VolumeMount{{
 							Name:      "nginx-config",
 							MountPath: "/etc/nginx/nginx.conf",
 							SubPath:   "nginx.conf",
+							ReadOnly:  !m.Spec.ConfigWritable,
 						}},
 					}},
 					Volumes: []corev1.Volume{{
-						Name: "nginx-config",
-						VolumeSource: corev1.VolumeSource{
-							ConfigMap: &corev1.ConfigMapVolumeSource{
-								LocalObjectReference: corev1.LocalObjectReference{
-									Name: m.Name + configMapNameSuffix,
-								},
-							},
-						},
+						Name:         "nginx-config",
+						VolumeSource: configVolumeSource(m),
 					}},
 				},
 			},
 		},
 	}
-	// Set NginxCluster instance as the owner and controller
-	ctrl.SetControllerReference(m, dep, r.Scheme)
+	for k, v := range scrapeAnnotations(m) {
+		dep.Spec.Template.Annotations[k] = v
+	}
+	addCacheVolume(&dep.Spec.Template.Spec, m)
+	addWritableDirs(&dep.Spec.Template.Spec, m)
+	addErrorPages(&dep.Spec.Template.Spec, m)
+	addNjsScripts(&dep.Spec.Template.Spec, m)
+	addBasicAuth(&dep.Spec.Template.Spec, m)
+	addConfigReloader(&dep.Spec.Template.Spec, m)
+	addShutdownDrain(&dep.Spec.Template.Spec, m)
+	addValidationSidecar(&dep.Spec.Template.Spec, m)
+	r.addHealthCheck(&dep.Spec.Template.Spec, m)
+	// Reconcile rejects invalid patches before the Deployment is built
+	_ = applyPodTemplatePatch(&dep.Spec.Template, m)
+	// The patch must not break the selector
+	if dep.Spec.Template.Labels == nil {
+		dep.Spec.Template.Labels = map[string]string{}
+	}
+	for k, v := range labels {
+		dep.Spec.Template.Labels[k] = v
+	}
+	// Record the pod template fingerprint so drift from the spec can be detected
+	dep.Annotations = map[string]string{
+		"pod-spec-hash": calculatePodSpecHash(&dep.Spec.Template),
+	}
+	stampOperatorVersion(dep)
+	r.setOwner(m, dep)
 	return dep
 }
 
 // serviceForNginxCluster returns a Service object
 func (r *NginxClusterReconciler) serviceForNginxCluster(m *nginxv1.NginxCluster) *corev1.Service {
-	labels := map[string]string{
-		"app":     "nginx",
-		"cluster": m.Name,
-	}
+	labels := labelsForNginxCluster(m)
 
 	srv := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      m.Name,
-			Namespace: m.Namespace,
+			Namespace: workloadNamespace(m),
 		},
 		Spec: corev1.ServiceSpec{
 			Selector: labels,
-			Ports: []corev1.ServicePort{{
-				Port:     80,
-				Name:     "http",
-				Protocol: corev1.ProtocolTCP,
-			}},
-			Type: corev1.ServiceTypeClusterIP,
+			Ports:    servicePortsForNginxCluster(m),
 		},
 	}
-	// Set NginxCluster instance as the owner and controller
-	ctrl.SetControllerReference(m, srv, r.Scheme)
+	applyServiceType(srv, m)
+	applyServiceLabels(srv, m)
+	applyServiceIPFamilies(srv, m)
+	stampOperatorVersion(srv)
+	r.setOwner(m, srv)
 	return srv
 }
 
-func (r *NginxClusterReconciler) finalizeNginxCluster(ctx context.Context, m *nginxv1.NginxCluster) error {
+// addCacheVolume mounts the emptyDir requested by spec.cacheVolume into the
+// nginx container
+func addCacheVolume(podSpec *corev1.PodSpec, m *nginxv1.NginxCluster) {
+	if m.Spec.CacheVolume == nil {
+		return
+	}
+	emptyDir := &corev1.EmptyDirVolumeSource{Medium: m.Spec.CacheVolume.Medium}
+	if m.Spec.CacheVolume.SizeLimit != nil {
+		sizeLimit := m.Spec.CacheVolume.SizeLimit.DeepCopy()
+		emptyDir.SizeLimit = &sizeLimit
+	}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name:         cacheVolumeName,
+		VolumeSource: corev1.VolumeSource{EmptyDir: emptyDir},
+	})
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      cacheVolumeName,
+		MountPath: cacheVolumeMountPath,
+	})
+}
+
+// addShutdownDrain lets nginx drain for spec.shutdownDrainSeconds before the
+// pod is killed
+func addShutdownDrain(podSpec *corev1.PodSpec, m *nginxv1.NginxCluster) {
+	drain := m.Spec.ShutdownDrainSeconds
+	if drain <= 0 {
+		return
+	}
+	podSpec.Containers[0].Lifecycle = &corev1.Lifecycle{
+		PreStop: &corev1.LifecycleHandler{
+			Exec: &corev1.ExecAction{
+				Command: []string{"/bin/sh", "-c", fmt.Sprintf("nginx -s quit; sleep %d", drain)},
+			},
+		},
+	}
+	gracePeriod := int64(drain) + nginxv1.ShutdownDrainBufferSeconds
+	podSpec.TerminationGracePeriodSeconds = &gracePeriod
+}
+
+// rolloutPausedCondition reports whether the Deployment is paused by
+// spec.holdRollout or the rollout circuit breaker
+func rolloutPausedCondition(m *nginxv1.NginxCluster, dep *appsv1.Deployment) metav1.Condition {
+	cond := metav1.Condition{
+		Type:               nginxv1.ConditionRolloutPaused,
+		Status:             metav1.ConditionFalse,
+		Reason:             "RolloutActive",
+		Message:            "Pod template changes are rolled out",
+		ObservedGeneration: m.Generation,
+	}
+	if dep.Spec.Paused && m.Spec.HoldRollout {
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "HoldRollout"
+		cond.Message = "Deployment is paused by spec.holdRollout; clear it to roll out pending changes"
+	} else if dep.Spec.Paused {
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "RolloutCircuitOpen"
+		cond.Message = "Deployment is paused by the rollout circuit breaker; update the spec to resume"
+	}
+	return cond
+}
+
+// progressingCondition reports the rollout progress of the Deployment, e.g.
+// "3 of 5 updated, 4 available"
+func progressingCondition(m *nginxv1.NginxCluster, dep *appsv1.Deployment) metav1.Condition {
+	desired := int32(1)
+	if dep.Spec.Replicas != nil {
+		desired = *dep.Spec.Replicas
+	}
+	status := dep.Status
+	cond := metav1.Condition{
+		Type:               nginxv1.ConditionProgressing,
+		Status:             metav1.ConditionFalse,
+		Reason:             "RolloutComplete",
+		Message:            fmt.Sprintf("%d of %d updated, %d available", status.UpdatedReplicas, desired, status.AvailableReplicas),
+		ObservedGeneration: m.Generation,
+	}
+	if dc := deploymentCondition(dep, appsv1.DeploymentProgressing); dc != nil && dc.Reason == "ProgressDeadlineExceeded" {
+		cond.Reason = "ProgressDeadlineExceeded"
+		return cond
+	}
+	// Old replicas still running count as an unfinished rollout too
+	if status.ObservedGeneration < dep.Generation || status.UpdatedReplicas < desired ||
+		status.Replicas > status.UpdatedReplicas || status.AvailableReplicas < status.UpdatedReplicas {
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "RollingOut"
+	}
+	return cond
+}
+
+// deploymentCondition returns the condition of the given type, if present
+func deploymentCondition(dep *appsv1.Deployment, condType appsv1.DeploymentConditionType) *appsv1.DeploymentCondition {
+	for i := range dep.Status.Conditions {
+		if dep.Status.Conditions[i].Type == condType {
+			return &dep.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// affinityForNginxCluster returns the pod affinity. Unless disabled or
+// overridden, pods of multi-replica clusters prefer to run on different nodes.
+func affinityForNginxCluster(m *nginxv1.NginxCluster) *corev1.Affinity {
+	if m.Spec.Affinity != nil {
+		return m.Spec.Affinity.DeepCopy()
+	}
+	// Set regardless of the replicas, so scaling doesn't change the pod
+	// template and roll the pods. It has no effect on a single pod.
+	if m.Spec.DefaultPodAntiAffinity != nil && !*m.Spec.DefaultPodAntiAffinity {
+		return nil
+	}
+	return &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+				Weight: 100,
+				PodAffinityTerm: corev1.PodAffinityTerm{
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: labelsForNginxCluster(m),
+					},
+					TopologyKey: corev1.LabelHostname,
+				},
+			}},
+		},
+	}
+}
+
+// envForNginxCluster returns the environment of the nginx container
+func envForNginxCluster(m *nginxv1.NginxCluster) []corev1.EnvVar {
+	var env []corev1.EnvVar
+	if m.Spec.InjectPodMetadataEnv {
+		for _, v := range []struct{ name, fieldPath string }{
+			{"POD_NAME", "metadata.name"},
+			{"POD_NAMESPACE", "metadata.namespace"},
+			{"NODE_NAME", "spec.nodeName"},
+			{"POD_IP", "status.podIP"},
+		} {
+			env = append(env, corev1.EnvVar{
+				Name: v.name,
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: v.fieldPath},
+				},
+			})
+		}
+	}
+	return env
+}
+
+// imageForNginxCluster returns the nginx image, falling back to the default
+func imageForNginxCluster(m *nginxv1.NginxCluster) string {
+	if m.Spec.Image == "" {
+		return "nginx:latest"
+	}
+	return m.Spec.Image
+}
+
+// containerNameForNginxCluster returns the name of the nginx container,
+// falling back to the default
+func containerNameForNginxCluster(m *nginxv1.NginxCluster) string {
+	if m.Spec.ContainerName == "" {
+		return "nginx"
+	}
+	return m.Spec.ContainerName
+}
+
+// podSecurityContextForNginxCluster returns the pod security context, or nil
+// to leave it to the defaults
+func podSecurityContextForNginxCluster(m *nginxv1.NginxCluster) *corev1.PodSecurityContext {
+	if m.Spec.FSGroup == nil && len(m.Spec.Sysctls) == 0 {
+		return nil
+	}
+	sc := &corev1.PodSecurityContext{
+		Sysctls: append([]corev1.Sysctl(nil), m.Spec.Sysctls...),
+	}
+	if m.Spec.FSGroup != nil {
+		fsGroup := *m.Spec.FSGroup
+		sc.FSGroup = &fsGroup
+	}
+	return sc
+}
+
+// containerSecurityContextForNginxCluster returns the security context of the
+// nginx container, or nil to leave it to the image
+func containerSecurityContextForNginxCluster(m *nginxv1.NginxCluster) *corev1.SecurityContext {
+	if m.Spec.RunAsUser == nil && m.Spec.RunAsGroup == nil && !m.Spec.ReadOnlyRootFilesystem {
+		return nil
+	}
+	sc := &corev1.SecurityContext{}
+	if m.Spec.ReadOnlyRootFilesystem {
+		readOnly := true
+		sc.ReadOnlyRootFilesystem = &readOnly
+	}
+	if m.Spec.RunAsUser != nil {
+		uid := *m.Spec.RunAsUser
+		sc.RunAsUser = &uid
+	}
+	if m.Spec.RunAsGroup != nil {
+		gid := *m.Spec.RunAsGroup
+		sc.RunAsGroup = &gid
+	}
+	return sc
+}
+
+// containerPortsForNginxCluster returns the ports of the nginx container
+func containerPortsForNginxCluster(m *nginxv1.NginxCluster) []corev1.ContainerPort {
+	ports := []corev1.ContainerPort{{
+		ContainerPort: 80,
+		Name:          "http",
+	}}
+	if m.Spec.EnableHTTP3 {
+		ports = append(ports, corev1.ContainerPort{
+			ContainerPort: 443,
+			Name:          "http3",
+			Protocol:      corev1.ProtocolUDP,
+		})
+	}
+	return ports
+}
+
+// servicePortsForNginxCluster returns the ports exposed by the cluster's Service
+func servicePortsForNginxCluster(m *nginxv1.NginxCluster) []corev1.ServicePort {
+	ports := []corev1.ServicePort{{
+		Port:     80,
+		Name:     "http",
+		Protocol: corev1.ProtocolTCP,
+	}}
+	if m.Spec.EnableHTTP3 {
+		ports = append(ports, corev1.ServicePort{
+			Port:     443,
+			Name:     "http3",
+			Protocol: corev1.ProtocolUDP,
+		})
+	}
+	return ports
+}
+
+func (r *NginxClusterReconciler) finalizeNginxCluster(ctx context.Context, m *nginxv1.NginxCluster) (time.Duration, error) {
 	logger := log.FromContext(ctx)
+	// Let the pods finish in-flight requests before their Deployment goes
+	requeueAfter, err := r.waitForPodsDrained(ctx, m)
+	if err != nil || requeueAfter > 0 {
+		return requeueAfter, err
+	}
+	// Without owner references nothing garbage collects the managed resources
+	if !r.usesOwnerReferences(m) {
+		if err := r.deleteManagedResources(ctx, m); err != nil {
+			return 0, err
+		}
+	}
+	requeueAfter, err = r.waitForLoadBalancerRelease(ctx, m)
+	if err != nil || requeueAfter > 0 {
+		return requeueAfter, err
+	}
+	rolloutDurationSeconds.DeleteLabelValues(m.Namespace, m.Name)
 	logger.Info("Successfully finalized nginxCluster")
-	return nil
+	return 0, nil
+}
+
+// recordEvent emits an event on m when a recorder is configured
+func (r *NginxClusterReconciler) recordEvent(m *nginxv1.NginxCluster, eventtype, reason, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(m, eventtype, reason, message)
+	}
+	if r.recordsReconciles() {
+		r.history.recordAction(client.ObjectKeyFromObject(m), ReconcileAction{Type: eventtype, Reason: reason, Message: message})
+	}
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *NginxClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	// Index clusters by the ConfigMap their configuration is read from
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &nginxv1.NginxCluster{}, nginxConfFromIndex, indexNginxConfFrom); err != nil {
+		return err
+	}
+	// Index clusters by the Secrets and ConfigMaps their config depends on
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &nginxv1.NginxCluster{}, configDependenciesIndex, indexConfigDependencies); err != nil {
+		return err
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&nginxv1.NginxCluster{}).
-		Owns(&appsv1.Deployment{}).
-		Owns(&corev1.ConfigMap{}).
-		Owns(&corev1.Service{}).
-		Complete(r)
+		Watches(&corev1.ConfigMap{}, r.markDirty(handler.EnqueueRequestsFromMapFunc(r.requestsForReferencedConfigMap))).
+		Watches(&corev1.ConfigMap{}, r.markDirty(handler.EnqueueRequestsFromMapFunc(r.requestsForConfigDependency("ConfigMap")))).
+		Watches(&corev1.Secret{}, r.markDirty(handler.EnqueueRequestsFromMapFunc(r.requestsForConfigDependency("Secret"))))
+	managed := []client.Object{
+		&appsv1.Deployment{},
+		&corev1.ConfigMap{},
+		&corev1.Service{},
+		&networkingv1.NetworkPolicy{},
+	}
+	// Routes can only be watched on OpenShift
+	routes, err := routeAPIAvailable(mgr.GetRESTMapper())
+	if err != nil {
+		return err
+	}
+	if routes {
+		managed = append(managed, newRoute())
+	}
+	// HTTPRoutes only when the Gateway API CRDs are installed
+	httpRoutes, err := apiAvailable(mgr.GetRESTMapper(), httpRouteGVK)
+	if err != nil {
+		return err
+	}
+	if httpRoutes {
+		managed = append(managed, newHTTPRoute())
+	}
+	// VPAs only when the VPA CRD is installed
+	vpas, err := apiAvailable(mgr.GetRESTMapper(), vpaGVK)
+	if err != nil {
+		return err
+	}
+	if vpas {
+		managed = append(managed, newVPA())
+	}
+	for _, obj := range managed {
+		// Changes to managed resources always get a full reconcile
+		if !r.DisableOwnerReferences {
+			b = b.Watches(obj, r.markDirty(handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &nginxv1.NginxCluster{}, handler.OnlyControllerOwner())))
+		}
+		// Resources in a target namespace are only tied to their cluster by labels
+		b = b.Watches(obj, r.markDirty(handler.EnqueueRequestsFromMapFunc(requestForManagedObject)))
+	}
+	return b.Complete(r)
+}
+
+// syncDeploymentSpec copies the spec-derived settings of desired onto existing
+// and reports whether anything changed. Replicas and the config-hash rollout
+// are handled separately in Reconcile.
+func syncDeploymentSpec(existing, desired *appsv1.Deployment) bool {
+	changed := false
+	if !equalInt32Ptr(existing.Spec.RevisionHistoryLimit, desired.Spec.RevisionHistoryLimit) {
+		existing.Spec.RevisionHistoryLimit = desired.Spec.RevisionHistoryLimit
+		changed = true
+	}
+	if !equalInt32Ptr(existing.Spec.ProgressDeadlineSeconds, desired.Spec.ProgressDeadlineSeconds) {
+		existing.Spec.ProgressDeadlineSeconds = desired.Spec.ProgressDeadlineSeconds
+		changed = true
+	}
+	if existing.Spec.MinReadySeconds != desired.Spec.MinReadySeconds {
+		existing.Spec.MinReadySeconds = desired.Spec.MinReadySeconds
+		changed = true
+	}
+	if existing.Spec.Paused != desired.Spec.Paused {
+		existing.Spec.Paused = desired.Spec.Paused
+		changed = true
+	}
+	// The pod labels and spec are compared by fingerprint rather than field by
+	// field, so values defaulted by the API server don't count as drift.
+	if desiredHash := desired.Annotations["pod-spec-hash"]; existing.Annotations["pod-spec-hash"] != desiredHash {
+		existing.Spec.Template.Labels = desired.Spec.Template.Labels
+		existing.Spec.Template.Annotations = syncScrapeAnnotations(existing.Spec.Template.Annotations, desired.Spec.Template.Annotations)
+		existing.Spec.Template.Spec = desired.Spec.Template.Spec
+		if existing.Annotations == nil {
+			existing.Annotations = map[string]string{}
+		}
+		existing.Annotations["pod-spec-hash"] = desiredHash
+		changed = true
+	}
+	return changed
+}
+
+// setConfigRolloutAnnotations records a config change on the pod template so
+// the pods are replaced. The config hash alone changes the template; the
+// restart timestamp is kept for visibility unless disabled.
+func setConfigRolloutAnnotations(template *corev1.PodTemplateSpec, m *nginxv1.NginxCluster, configHash, restartedAt string) {
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations["config-hash"] = configHash
+	if !m.Spec.DisableRestartTimestamp {
+		template.Annotations["restartedAt"] = restartedAt
+	}
+}
+
+// calculatePodSpecHash calculates a hash of the generated pod labels and spec.
+// Template annotations are excluded since they drive the config rollout, all
+// but the scrape annotations derived from the spec.
+func calculatePodSpecHash(template *corev1.PodTemplateSpec) string {
+	data, _ := json.Marshal(struct {
+		Labels      map[string]string `json:"labels"`
+		Annotations map[string]string `json:"annotations,omitempty"`
+		Spec        *corev1.PodSpec   `json:"spec"`
+	}{template.Labels, templateScrapeAnnotations(template.Annotations), &template.Spec})
+	return calculateConfigHash(string(data))
+}
+
+// equalInt32Ptr reports whether two optional int32 values are equal
+func equalInt32Ptr(a, b *int32) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// labelsForNginxCluster returns the labels selecting the nginx pods of a cluster
+func labelsForNginxCluster(m *nginxv1.NginxCluster) map[string]string {
+	return map[string]string{
+		"app":     "nginx",
+		"cluster": m.Name,
+	}
+}
+
+// podLabelsForNginxCluster returns the labels applied to the nginx pods. They
+// extend the selector labels with spec.podLabels and the shared Service
+// membership label, which are not part of the selector.
+func podLabelsForNginxCluster(m *nginxv1.NginxCluster) map[string]string {
+	labels := make(map[string]string, len(m.Spec.PodLabels)+3)
+	for k, v := range m.Spec.PodLabels {
+		labels[k] = v
+	}
+	for k, v := range labelsForNginxCluster(m) {
+		labels[k] = v
+	}
+	if m.Spec.SharedServiceName != "" {
+		labels[sharedServiceLabel] = m.Spec.SharedServiceName
+	}
+	return labels
 }
 
 // calculateConfigHash calculates a hash of the nginx configuration
@@ -349,8 +1457,16 @@ func calculateConfigHash(config string) string {
 	return fmt.Sprintf("%x", hash)[:16]
 }
 
-// getDefaultNginxConf returns default nginx configuration
-func getDefaultNginxConf() string {
+// getDefaultNginxConf returns default nginx configuration serving the static
+// files under root, /usr/share/nginx/html if empty, with the index files
+// index, index.html and index.htm if empty
+func getDefaultNginxConf(root string, index []string) string {
+	if root == "" {
+		root = "/usr/share/nginx/html"
+	}
+	if len(index) == 0 {
+		index = []string{"index.html", "index.htm"}
+	}
 	return `
 events {
     worker_connections 1024;
@@ -368,8 +1484,8 @@ http {
         server_name  localhost;
 
         location / {
-            root   /usr/share/nginx/html;
-            index  index.html index.htm;
+            root   ` + root + `;
+            index  ` + strings.Join(index, " ") + `;
         }
 
         error_page   500 502 503 504  /50x.html;
@@ -381,3 +1497,39 @@ http {
 `
 }
 
+// getReverseProxyNginxConf returns the default configuration proxying all
+// requests to upstreams
+func getReverseProxyNginxConf(upstreams []string) string {
+	var servers strings.Builder
+	for _, upstream := range upstreams {
+		fmt.Fprintf(&servers, "        server %s;\n", upstream)
+	}
+	return `
+events {
+    worker_connections 1024;
+}
+
+http {
+    include       /etc/nginx/mime.types;
+    default_type  application/octet-stream;
+
+    keepalive_timeout  65;
+
+    upstream backend {
+` + servers.String() + `    }
+
+    server {
+        listen       80;
+        server_name  localhost;
+
+        location / {
+            proxy_pass http://backend;
+            proxy_set_header Host $host;
+            proxy_set_header X-Real-IP $remote_addr;
+            proxy_set_header X-Forwarded-For $proxy_add_x_forwarded_for;
+            proxy_set_header X-Forwarded-Proto $scheme;
+        }
+    }
+}
+`
+}
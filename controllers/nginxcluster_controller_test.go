@@ -0,0 +1,861 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// newTestNginxCluster returns a minimal NginxCluster in the default namespace.
+func newTestNginxCluster(name string) *nginxv1.NginxCluster {
+	return &nginxv1.NginxCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: nginxv1.NginxClusterSpec{
+			Replicas:  2,
+			Image:     "nginx:1.25",
+			NginxConf: "events {}\nhttp {}\n",
+		},
+	}
+}
+
+// createTestNginxCluster creates m and deletes it when the test finishes.
+func createTestNginxCluster(t *testing.T, m *nginxv1.NginxCluster) {
+	t.Helper()
+	ctx := context.Background()
+	if err := k8sClient.Create(ctx, m); err != nil {
+		t.Fatalf("failed to create NginxCluster: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = k8sClient.Delete(context.Background(), m)
+	})
+}
+
+// checkControllerOwner verifies that obj is controlled by the given NginxCluster.
+func checkControllerOwner(obj metav1.Object, m *nginxv1.NginxCluster) error {
+	ref := metav1.GetControllerOf(obj)
+	if ref == nil {
+		return fmt.Errorf("%s has no controller reference", obj.GetName())
+	}
+	if ref.Kind != "NginxCluster" || ref.Name != m.Name || ref.UID != m.UID {
+		return fmt.Errorf("%s is controlled by %s/%s (%s), want NginxCluster/%s (%s)",
+			obj.GetName(), ref.Kind, ref.Name, ref.UID, m.Name, m.UID)
+	}
+	return nil
+}
+
+// laggingCacheClient keeps objects in memory and hides a ConfigMap from the
+// first Get after its creation, as the cache of the manager's client does
+// until the watch event arrives. It records the kinds it creates.
+type laggingCacheClient struct {
+	client.Client
+	objects map[string]client.Object
+	hidden  map[string]bool
+	created []string
+}
+
+func (c *laggingCacheClient) key(obj client.Object, key client.ObjectKey) string {
+	return fmt.Sprintf("%T/%s", obj, key)
+}
+
+func (c *laggingCacheClient) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	k := c.key(obj, key)
+	stored, ok := c.objects[k]
+	if !ok || c.hidden[k] {
+		delete(c.hidden, k)
+		return apierrors.NewNotFound(schema.GroupResource{}, key.Name)
+	}
+	return testScheme.Convert(stored.DeepCopyObject(), obj, nil)
+}
+
+func (c *laggingCacheClient) Create(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+	k := c.key(obj, client.ObjectKeyFromObject(obj))
+	if _, ok := obj.(*corev1.ConfigMap); ok {
+		c.hidden[k] = true
+	}
+	c.created = append(c.created, fmt.Sprintf("%T", obj))
+	c.objects[k] = obj.DeepCopyObject().(client.Object)
+	return nil
+}
+
+func (c *laggingCacheClient) Update(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	c.objects[c.key(obj, client.ObjectKeyFromObject(obj))] = obj.DeepCopyObject().(client.Object)
+	return nil
+}
+
+func TestReconcileCreatesConfigMapBeforeDeployment(t *testing.T) {
+	m := newTestNginxCluster("first-create")
+	c := &laggingCacheClient{
+		objects: map[string]client.Object{},
+		hidden:  map[string]bool{},
+	}
+	c.objects[c.key(m, client.ObjectKeyFromObject(m))] = m
+	r := &NginxClusterReconciler{Client: c, Scheme: testScheme}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(m)}
+
+	// The ConfigMap is created but not visible yet
+	result, err := r.Reconcile(context.Background(), req)
+	if err != nil || !result.Requeue {
+		t.Fatalf("Reconcile() = %+v, %v, want a requeue", result, err)
+	}
+	if len(c.created) != 1 || c.created[0] != "*v1.ConfigMap" {
+		t.Fatalf("created %v before the ConfigMap was visible", c.created)
+	}
+
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if len(c.created) != 2 || c.created[1] != "*v1.Deployment" {
+		t.Fatalf("expected the Deployment to follow the ConfigMap, created %v", c.created)
+	}
+}
+
+func TestReconcileDisableFinalizer(t *testing.T) {
+	for _, existing := range [][]string{nil, {nginxClusterFinalizer}} {
+		m := newTestNginxCluster("no-finalizer")
+		m.Spec.DisableFinalizer = true
+		m.Finalizers = existing
+		c := &laggingCacheClient{
+			objects: map[string]client.Object{},
+			hidden:  map[string]bool{},
+		}
+		key := c.key(m, client.ObjectKeyFromObject(m))
+		c.objects[key] = m
+		r := &NginxClusterReconciler{Client: c, Scheme: testScheme}
+
+		if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(m)}); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		if finalizers := c.objects[key].GetFinalizers(); len(finalizers) != 0 {
+			t.Fatalf("expected no finalizer with %v before, got %v", existing, finalizers)
+		}
+	}
+}
+
+func TestReconcileCreatesOwnedResources(t *testing.T) {
+	requireEnvtest(t)
+	ctx := context.Background()
+
+	m := newTestNginxCluster("create-owned")
+	createTestNginxCluster(t, m)
+
+	// Pick up the UID assigned by the API server.
+	key := client.ObjectKeyFromObject(m)
+	if err := k8sClient.Get(ctx, key, m); err != nil {
+		t.Fatalf("failed to get NginxCluster: %v", err)
+	}
+
+	eventually(t, func() error {
+		cm := &corev1.ConfigMap{}
+		if err := k8sClient.Get(ctx, types.NamespacedName{Name: m.Name + configMapNameSuffix, Namespace: m.Namespace}, cm); err != nil {
+			return err
+		}
+		if cm.Data["nginx.conf"] != m.Spec.NginxConf {
+			return fmt.Errorf("unexpected nginx.conf %q", cm.Data["nginx.conf"])
+		}
+		if cm.Labels[effectiveConfigLabel] != "true" {
+			return fmt.Errorf("ConfigMap is missing the %s label", effectiveConfigLabel)
+		}
+		return checkControllerOwner(cm, m)
+	})
+
+	eventually(t, func() error {
+		dep := &appsv1.Deployment{}
+		if err := k8sClient.Get(ctx, key, dep); err != nil {
+			return err
+		}
+		if dep.Spec.Replicas == nil || *dep.Spec.Replicas != m.Spec.Replicas {
+			return fmt.Errorf("unexpected replicas %v", dep.Spec.Replicas)
+		}
+		if got := dep.Spec.Template.Spec.Containers[0].Image; got != m.Spec.Image {
+			return fmt.Errorf("unexpected image %q", got)
+		}
+		return checkControllerOwner(dep, m)
+	})
+
+	eventually(t, func() error {
+		srv := &corev1.Service{}
+		if err := k8sClient.Get(ctx, key, srv); err != nil {
+			return err
+		}
+		return checkControllerOwner(srv, m)
+	})
+
+	eventually(t, func() error {
+		got := &nginxv1.NginxCluster{}
+		if err := k8sClient.Get(ctx, key, got); err != nil {
+			return err
+		}
+		if want := m.Name + configMapNameSuffix; got.Status.EffectiveConfigConfigMap != want {
+			return fmt.Errorf("expected effectiveConfigConfigMap %q, got %q", want, got.Status.EffectiveConfigConfigMap)
+		}
+		return nil
+	})
+}
+
+func TestReconcileConfigChangeRollsPods(t *testing.T) {
+	requireEnvtest(t)
+	ctx := context.Background()
+
+	m := newTestNginxCluster("config-change")
+	createTestNginxCluster(t, m)
+	key := client.ObjectKeyFromObject(m)
+
+	var oldHash string
+	eventually(t, func() error {
+		dep := &appsv1.Deployment{}
+		if err := k8sClient.Get(ctx, key, dep); err != nil {
+			return err
+		}
+		oldHash = dep.Spec.Template.Annotations["config-hash"]
+		if oldHash != calculateConfigHash(m.Spec.NginxConf) {
+			return fmt.Errorf("unexpected pod template config-hash %q", oldHash)
+		}
+		return nil
+	})
+
+	newConf := "events {}\nhttp { server { listen 80; } }\n"
+	eventually(t, func() error {
+		if err := k8sClient.Get(ctx, key, m); err != nil {
+			return err
+		}
+		m.Spec.NginxConf = newConf
+		return k8sClient.Update(ctx, m)
+	})
+
+	newHash := calculateConfigHash(newConf)
+	eventually(t, func() error {
+		cm := &corev1.ConfigMap{}
+		if err := k8sClient.Get(ctx, types.NamespacedName{Name: m.Name + configMapNameSuffix, Namespace: m.Namespace}, cm); err != nil {
+			return err
+		}
+		if cm.Data["nginx.conf"] != newConf {
+			return fmt.Errorf("ConfigMap not updated yet")
+		}
+		return nil
+	})
+
+	eventually(t, func() error {
+		dep := &appsv1.Deployment{}
+		if err := k8sClient.Get(ctx, key, dep); err != nil {
+			return err
+		}
+		got := dep.Spec.Template.Annotations["config-hash"]
+		if got == oldHash || got != newHash {
+			return fmt.Errorf("pod template config-hash is %q, want %q", got, newHash)
+		}
+		if dep.Spec.Template.Annotations["restartedAt"] == "" {
+			return fmt.Errorf("pod template restartedAt annotation not set")
+		}
+		return nil
+	})
+}
+
+func TestSetConfigRolloutAnnotations(t *testing.T) {
+	m := newTestNginxCluster("rollout-annotations")
+	template := &corev1.PodTemplateSpec{}
+	setConfigRolloutAnnotations(template, m, "hash", "2025-01-01T00:00:00Z")
+	if template.Annotations["config-hash"] != "hash" || template.Annotations["restartedAt"] != "2025-01-01T00:00:00Z" {
+		t.Fatalf("unexpected annotations %v", template.Annotations)
+	}
+
+	m.Spec.DisableRestartTimestamp = true
+	template = &corev1.PodTemplateSpec{}
+	setConfigRolloutAnnotations(template, m, "hash", "2025-01-01T00:00:00Z")
+	if _, ok := template.Annotations["restartedAt"]; ok || template.Annotations["config-hash"] != "hash" {
+		t.Fatalf("expected only the config-hash annotation, got %v", template.Annotations)
+	}
+}
+
+func TestReconcileConfigChangeWithoutRestartTimestamp(t *testing.T) {
+	requireEnvtest(t)
+	ctx := context.Background()
+
+	m := newTestNginxCluster("no-restart-timestamp")
+	m.Spec.DisableRestartTimestamp = true
+	createTestNginxCluster(t, m)
+	key := client.ObjectKeyFromObject(m)
+
+	eventually(t, func() error {
+		dep := &appsv1.Deployment{}
+		return k8sClient.Get(ctx, key, dep)
+	})
+
+	newConf := "events {}\nhttp { server { listen 8080; } }\n"
+	eventually(t, func() error {
+		if err := k8sClient.Get(ctx, key, m); err != nil {
+			return err
+		}
+		m.Spec.NginxConf = newConf
+		return k8sClient.Update(ctx, m)
+	})
+
+	eventually(t, func() error {
+		dep := &appsv1.Deployment{}
+		if err := k8sClient.Get(ctx, key, dep); err != nil {
+			return err
+		}
+		if got, want := dep.Spec.Template.Annotations["config-hash"], calculateConfigHash(newConf); got != want {
+			return fmt.Errorf("pod template config-hash is %q, want %q", got, want)
+		}
+		if ts, ok := dep.Spec.Template.Annotations["restartedAt"]; ok {
+			return fmt.Errorf("unexpected restartedAt annotation %q", ts)
+		}
+		return nil
+	})
+}
+
+func TestReconcileAdoptsExistingConfigMap(t *testing.T) {
+	requireEnvtest(t)
+	ctx := context.Background()
+
+	m := newTestNginxCluster("adopt-config")
+	// Pre-created during a migration, with the same content the spec produces
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: m.Name + configMapNameSuffix, Namespace: m.Namespace},
+		Data:       map[string]string{"nginx.conf": m.Spec.NginxConf},
+	}
+	if err := k8sClient.Create(ctx, existing); err != nil {
+		t.Fatalf("failed to create ConfigMap: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = k8sClient.Delete(context.Background(), existing)
+	})
+	createdVersion := existing.ResourceVersion
+
+	createTestNginxCluster(t, m)
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(m), m); err != nil {
+		t.Fatalf("failed to get NginxCluster: %v", err)
+	}
+
+	eventually(t, func() error {
+		cm := &corev1.ConfigMap{}
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(existing), cm); err != nil {
+			return err
+		}
+		if cm.ResourceVersion == createdVersion {
+			return fmt.Errorf("ConfigMap not adopted yet")
+		}
+		if cm.Data["nginx.conf"] != m.Spec.NginxConf {
+			return fmt.Errorf("unexpected nginx.conf %q", cm.Data["nginx.conf"])
+		}
+		if cm.Annotations["config-hash"] != calculateConfigHash(m.Spec.NginxConf) {
+			return fmt.Errorf("unexpected config-hash %q", cm.Annotations["config-hash"])
+		}
+		return checkControllerOwner(cm, m)
+	})
+}
+
+func TestDeploymentRevisionHistoryLimit(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+
+	m := newTestNginxCluster("revision-history")
+	dep := r.deploymentForNginxCluster(m, "hash")
+	if got := dep.Spec.RevisionHistoryLimit; got == nil || *got != defaultRevisionHistoryLimit {
+		t.Fatalf("default RevisionHistoryLimit = %v, want %d", got, defaultRevisionHistoryLimit)
+	}
+
+	limit := int32(5)
+	m.Spec.RevisionHistoryLimit = &limit
+	desired := r.deploymentForNginxCluster(m, "hash")
+	if got := desired.Spec.RevisionHistoryLimit; got == nil || *got != limit {
+		t.Fatalf("RevisionHistoryLimit = %v, want %d", got, limit)
+	}
+
+	if !syncDeploymentSpec(dep, desired) {
+		t.Fatalf("expected syncDeploymentSpec to report a change")
+	}
+	if *dep.Spec.RevisionHistoryLimit != limit {
+		t.Fatalf("synced RevisionHistoryLimit = %d, want %d", *dep.Spec.RevisionHistoryLimit, limit)
+	}
+	if syncDeploymentSpec(dep, desired) {
+		t.Fatalf("expected no change after sync")
+	}
+}
+
+func TestDeploymentProgressDeadlineSeconds(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+
+	m := newTestNginxCluster("progress-deadline")
+	dep := r.deploymentForNginxCluster(m, "hash")
+	if got := dep.Spec.ProgressDeadlineSeconds; got == nil || *got != defaultProgressDeadlineSeconds {
+		t.Fatalf("default ProgressDeadlineSeconds = %v, want %d", got, defaultProgressDeadlineSeconds)
+	}
+
+	deadline := int32(300)
+	m.Spec.ProgressDeadlineSeconds = &deadline
+	if !syncDeploymentSpec(dep, r.deploymentForNginxCluster(m, "hash")) {
+		t.Fatalf("expected syncDeploymentSpec to report a change")
+	}
+	if *dep.Spec.ProgressDeadlineSeconds != deadline {
+		t.Fatalf("synced ProgressDeadlineSeconds = %d, want %d", *dep.Spec.ProgressDeadlineSeconds, deadline)
+	}
+}
+
+func TestDeploymentConfigMountReadOnly(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+
+	m := newTestNginxCluster("config-readonly")
+	dep := r.deploymentForNginxCluster(m, "hash")
+	mount := dep.Spec.Template.Spec.Containers[0].VolumeMounts[0]
+	if mount.Name != "nginx-config" || !mount.ReadOnly {
+		t.Fatalf("expected read-only nginx-config mount, got %+v", mount)
+	}
+
+	m.Spec.ConfigWritable = true
+	desired := r.deploymentForNginxCluster(m, "hash")
+	if desired.Spec.Template.Spec.Containers[0].VolumeMounts[0].ReadOnly {
+		t.Fatalf("expected writable nginx-config mount when ConfigWritable is set")
+	}
+
+	if !syncDeploymentSpec(dep, desired) {
+		t.Fatalf("expected syncDeploymentSpec to report a pod spec change")
+	}
+	if dep.Spec.Template.Spec.Containers[0].VolumeMounts[0].ReadOnly {
+		t.Fatalf("expected synced mount to be writable")
+	}
+}
+
+func TestDeploymentPodMetadataEnv(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+
+	m := newTestNginxCluster("pod-metadata-env")
+	dep := r.deploymentForNginxCluster(m, "hash")
+	if env := dep.Spec.Template.Spec.Containers[0].Env; len(env) != 0 {
+		t.Fatalf("expected no env by default, got %v", env)
+	}
+
+	m.Spec.InjectPodMetadataEnv = true
+	desired := r.deploymentForNginxCluster(m, "hash")
+	want := map[string]string{
+		"POD_NAME":      "metadata.name",
+		"POD_NAMESPACE": "metadata.namespace",
+		"NODE_NAME":     "spec.nodeName",
+		"POD_IP":        "status.podIP",
+	}
+	env := desired.Spec.Template.Spec.Containers[0].Env
+	if len(env) != len(want) {
+		t.Fatalf("expected %d env vars, got %v", len(want), env)
+	}
+	for _, e := range env {
+		if e.ValueFrom == nil || e.ValueFrom.FieldRef == nil || e.ValueFrom.FieldRef.FieldPath != want[e.Name] {
+			t.Errorf("env %s has unexpected source %+v", e.Name, e.ValueFrom)
+		}
+	}
+
+	if !syncDeploymentSpec(dep, desired) {
+		t.Fatalf("expected enabling pod metadata env to roll the Deployment")
+	}
+}
+
+func TestDeploymentDefaultPodAntiAffinity(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+
+	m := newTestNginxCluster("anti-affinity")
+	affinity := r.deploymentForNginxCluster(m, "hash").Spec.Template.Spec.Affinity
+	if affinity == nil || affinity.PodAntiAffinity == nil {
+		t.Fatalf("expected default pod anti-affinity for %d replicas", m.Spec.Replicas)
+	}
+	terms := affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+	if len(terms) != 1 {
+		t.Fatalf("expected one preferred anti-affinity term, got %d", len(terms))
+	}
+	term := terms[0].PodAffinityTerm
+	if term.TopologyKey != corev1.LabelHostname {
+		t.Errorf("topology key = %q, want %q", term.TopologyKey, corev1.LabelHostname)
+	}
+	if term.LabelSelector == nil || term.LabelSelector.MatchLabels["cluster"] != m.Name {
+		t.Errorf("anti-affinity does not select the cluster pods: %+v", term.LabelSelector)
+	}
+
+	// Scaling must not change the pod template
+	m.Spec.Replicas = 1
+	if got := r.deploymentForNginxCluster(m, "hash").Spec.Template.Spec.Affinity; !reflect.DeepEqual(got, affinity) {
+		t.Errorf("anti-affinity changed with the replicas: %+v", got)
+	}
+
+	m.Spec.Replicas = 3
+	disabled := false
+	m.Spec.DefaultPodAntiAffinity = &disabled
+	if r.deploymentForNginxCluster(m, "hash").Spec.Template.Spec.Affinity != nil {
+		t.Errorf("expected no anti-affinity when disabled")
+	}
+
+	m.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{}}
+	if got := r.deploymentForNginxCluster(m, "hash").Spec.Template.Spec.Affinity; got == nil || got.NodeAffinity == nil || got.PodAntiAffinity != nil {
+		t.Errorf("expected explicit affinity to be used as is, got %+v", got)
+	}
+}
+
+func TestDeploymentPodLabels(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("pod-labels")
+	existing := r.deploymentForNginxCluster(m, "hash")
+	selector := existing.Spec.Selector.DeepCopy()
+
+	m.Spec.PodLabels = map[string]string{"team": "web", "app": "other"}
+	desired := r.deploymentForNginxCluster(m, "hash")
+	if labels := desired.Spec.Template.Labels; labels["team"] != "web" || labels["app"] != "nginx" || labels["cluster"] != m.Name {
+		t.Fatalf("unexpected pod labels %v", labels)
+	}
+	if !syncDeploymentSpec(existing, desired) {
+		t.Fatalf("expected adding a pod label to update the Deployment")
+	}
+	if existing.Spec.Template.Labels["team"] != "web" {
+		t.Fatalf("pod label not synced: %v", existing.Spec.Template.Labels)
+	}
+	if !reflect.DeepEqual(existing.Spec.Selector, selector) || !reflect.DeepEqual(desired.Spec.Selector, selector) {
+		t.Fatalf("pod labels changed the selector: %v", existing.Spec.Selector)
+	}
+}
+
+func TestDeploymentSchedulingGates(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+
+	m := newTestNginxCluster("scheduling-gates")
+	dep := r.deploymentForNginxCluster(m, "hash")
+	if gates := dep.Spec.Template.Spec.SchedulingGates; len(gates) != 0 {
+		t.Fatalf("expected no scheduling gates by default, got %v", gates)
+	}
+
+	m.Spec.SchedulingGates = []corev1.PodSchedulingGate{{Name: "example.com/cache-warm"}}
+	desired := r.deploymentForNginxCluster(m, "hash")
+	gates := desired.Spec.Template.Spec.SchedulingGates
+	if len(gates) != 1 || gates[0].Name != "example.com/cache-warm" {
+		t.Fatalf("expected the cache-warm gate, got %v", gates)
+	}
+	if !syncDeploymentSpec(dep, desired) {
+		t.Fatalf("expected adding a scheduling gate to roll the Deployment")
+	}
+}
+
+func TestDeploymentReadinessGates(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+
+	m := newTestNginxCluster("readiness-gates")
+	dep := r.deploymentForNginxCluster(m, "hash")
+	if gates := dep.Spec.Template.Spec.ReadinessGates; len(gates) != 0 {
+		t.Fatalf("expected no readiness gates by default, got %v", gates)
+	}
+
+	m.Spec.ReadinessGates = []corev1.PodReadinessGate{{ConditionType: "target-health.elbv2.k8s.aws/nginx"}}
+	desired := r.deploymentForNginxCluster(m, "hash")
+	gates := desired.Spec.Template.Spec.ReadinessGates
+	if len(gates) != 1 || gates[0].ConditionType != "target-health.elbv2.k8s.aws/nginx" {
+		t.Fatalf("expected the target health gate, got %v", gates)
+	}
+	if !syncDeploymentSpec(dep, desired) {
+		t.Fatalf("expected adding a readiness gate to roll the Deployment")
+	}
+}
+
+func TestDeploymentTerminationMessagePolicy(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+
+	m := newTestNginxCluster("termination-message")
+	dep := r.deploymentForNginxCluster(m, "hash")
+	if policy := dep.Spec.Template.Spec.Containers[0].TerminationMessagePolicy; policy != "" {
+		t.Fatalf("expected the API server default policy, got %q", policy)
+	}
+
+	m.Spec.TerminationMessagePolicy = corev1.TerminationMessageFallbackToLogsOnError
+	m.Spec.TerminationMessagePath = "/var/log/nginx/termination.log"
+	desired := r.deploymentForNginxCluster(m, "hash")
+	container := desired.Spec.Template.Spec.Containers[0]
+	if container.TerminationMessagePolicy != corev1.TerminationMessageFallbackToLogsOnError {
+		t.Errorf("expected FallbackToLogsOnError, got %q", container.TerminationMessagePolicy)
+	}
+	if container.TerminationMessagePath != "/var/log/nginx/termination.log" {
+		t.Errorf("unexpected termination message path %q", container.TerminationMessagePath)
+	}
+	if !syncDeploymentSpec(dep, desired) {
+		t.Fatalf("expected changing the termination message policy to roll the Deployment")
+	}
+}
+
+func TestDeploymentWorkingDir(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+
+	m := newTestNginxCluster("working-dir")
+	dep := r.deploymentForNginxCluster(m, "hash")
+	if dir := dep.Spec.Template.Spec.Containers[0].WorkingDir; dir != "" {
+		t.Fatalf("expected the image working directory, got %q", dir)
+	}
+
+	m.Spec.WorkingDir = "/opt/nginx"
+	desired := r.deploymentForNginxCluster(m, "hash")
+	if dir := desired.Spec.Template.Spec.Containers[0].WorkingDir; dir != "/opt/nginx" {
+		t.Fatalf("unexpected working directory %q", dir)
+	}
+	if !syncDeploymentSpec(dep, desired) {
+		t.Fatalf("expected changing the working directory to roll the Deployment")
+	}
+}
+
+func TestDeploymentFSGroup(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+
+	m := newTestNginxCluster("fs-group")
+	dep := r.deploymentForNginxCluster(m, "hash")
+	if sc := dep.Spec.Template.Spec.SecurityContext; sc != nil {
+		t.Fatalf("expected no pod security context by default, got %+v", sc)
+	}
+
+	fsGroup := int64(101)
+	m.Spec.FSGroup = &fsGroup
+	desired := r.deploymentForNginxCluster(m, "hash")
+	if sc := desired.Spec.Template.Spec.SecurityContext; sc == nil || sc.FSGroup == nil || *sc.FSGroup != 101 {
+		t.Fatalf("unexpected pod security context %+v", sc)
+	}
+	if !syncDeploymentSpec(dep, desired) {
+		t.Fatalf("expected changing fsGroup to roll the Deployment")
+	}
+}
+
+func TestDeploymentSysctls(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+
+	m := newTestNginxCluster("sysctls")
+	dep := r.deploymentForNginxCluster(m, "hash")
+	m.Spec.Sysctls = []corev1.Sysctl{{Name: "net.core.somaxconn", Value: "65535"}}
+	desired := r.deploymentForNginxCluster(m, "hash")
+	sc := desired.Spec.Template.Spec.SecurityContext
+	if sc == nil || len(sc.Sysctls) != 1 || sc.Sysctls[0] != m.Spec.Sysctls[0] || sc.FSGroup != nil {
+		t.Fatalf("unexpected pod security context %+v", sc)
+	}
+	if !syncDeploymentSpec(dep, desired) {
+		t.Fatalf("expected changing sysctls to roll the Deployment")
+	}
+}
+
+func TestDeploymentRunAsUser(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+
+	m := newTestNginxCluster("run-as-user")
+	dep := r.deploymentForNginxCluster(m, "hash")
+	if sc := dep.Spec.Template.Spec.Containers[0].SecurityContext; sc != nil {
+		t.Fatalf("expected no container security context by default, got %+v", sc)
+	}
+
+	uid, gid := int64(101), int64(101)
+	m.Spec.RunAsUser = &uid
+	m.Spec.RunAsGroup = &gid
+	desired := r.deploymentForNginxCluster(m, "hash")
+	sc := desired.Spec.Template.Spec.Containers[0].SecurityContext
+	if sc == nil || sc.RunAsUser == nil || *sc.RunAsUser != 101 || sc.RunAsGroup == nil || *sc.RunAsGroup != 101 {
+		t.Fatalf("unexpected container security context %+v", sc)
+	}
+	if !syncDeploymentSpec(dep, desired) {
+		t.Fatalf("expected changing runAsUser to roll the Deployment")
+	}
+}
+
+func TestDeploymentContainerName(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+
+	m := newTestNginxCluster("container-name")
+	dep := r.deploymentForNginxCluster(m, "hash")
+	if name := dep.Spec.Template.Spec.Containers[0].Name; name != "nginx" {
+		t.Fatalf("default container name = %q, want nginx", name)
+	}
+
+	m.Spec.ContainerName = "proxy"
+	m.Spec.CacheVolume = &nginxv1.CacheVolumeSpec{}
+	desired := r.deploymentForNginxCluster(m, "hash")
+	container := desired.Spec.Template.Spec.Containers[0]
+	if container.Name != "proxy" {
+		t.Fatalf("unexpected container name %q", container.Name)
+	}
+	if len(container.VolumeMounts) != 2 {
+		t.Fatalf("expected the config and cache mounts on the renamed container, got %+v", container.VolumeMounts)
+	}
+	if !syncDeploymentSpec(dep, desired) {
+		t.Fatalf("expected renaming the container to roll the Deployment")
+	}
+}
+
+func TestDeploymentShutdownDrain(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+
+	m := newTestNginxCluster("shutdown-drain")
+	dep := r.deploymentForNginxCluster(m, "hash")
+	if dep.Spec.Template.Spec.Containers[0].Lifecycle != nil || dep.Spec.Template.Spec.TerminationGracePeriodSeconds != nil {
+		t.Fatalf("expected no preStop hook or grace period by default")
+	}
+
+	m.Spec.ShutdownDrainSeconds = 30
+	desired := r.deploymentForNginxCluster(m, "hash")
+	lifecycle := desired.Spec.Template.Spec.Containers[0].Lifecycle
+	if lifecycle == nil || lifecycle.PreStop == nil || lifecycle.PreStop.Exec == nil {
+		t.Fatalf("expected a preStop exec hook, got %+v", lifecycle)
+	}
+	if cmd := lifecycle.PreStop.Exec.Command; len(cmd) != 3 || cmd[2] != "nginx -s quit; sleep 30" {
+		t.Errorf("unexpected preStop command %v", cmd)
+	}
+	if got := desired.Spec.Template.Spec.TerminationGracePeriodSeconds; got == nil || *got != 30+nginxv1.ShutdownDrainBufferSeconds {
+		t.Errorf("unexpected terminationGracePeriodSeconds %v", got)
+	}
+	if !syncDeploymentSpec(dep, desired) {
+		t.Fatalf("expected the drain settings to roll the Deployment")
+	}
+}
+
+func TestDeploymentCacheVolume(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+
+	m := newTestNginxCluster("cache-volume")
+	dep := r.deploymentForNginxCluster(m, "hash")
+	for _, v := range dep.Spec.Template.Spec.Volumes {
+		if v.Name == cacheVolumeName {
+			t.Fatalf("expected no cache volume by default")
+		}
+	}
+
+	sizeLimit := resource.MustParse("2Gi")
+	m.Spec.CacheVolume = &nginxv1.CacheVolumeSpec{SizeLimit: &sizeLimit}
+	desired := r.deploymentForNginxCluster(m, "hash")
+	var emptyDir *corev1.EmptyDirVolumeSource
+	for _, v := range desired.Spec.Template.Spec.Volumes {
+		if v.Name == cacheVolumeName {
+			emptyDir = v.EmptyDir
+		}
+	}
+	if emptyDir == nil || emptyDir.SizeLimit == nil || emptyDir.SizeLimit.Cmp(sizeLimit) != 0 {
+		t.Fatalf("expected a 2Gi emptyDir cache volume, got %+v", emptyDir)
+	}
+	mounted := false
+	for _, vm := range desired.Spec.Template.Spec.Containers[0].VolumeMounts {
+		if vm.Name == cacheVolumeName && vm.MountPath == "/var/cache/nginx" {
+			mounted = true
+		}
+	}
+	if !mounted {
+		t.Errorf("expected the cache volume to be mounted at /var/cache/nginx")
+	}
+	if !syncDeploymentSpec(dep, desired) {
+		t.Fatalf("expected adding a cache volume to roll the Deployment")
+	}
+
+	m.Spec.CacheVolume.Medium = corev1.StorageMediumMemory
+	if !syncDeploymentSpec(desired, r.deploymentForNginxCluster(m, "hash")) {
+		t.Fatalf("expected changing the cache medium to roll the Deployment")
+	}
+}
+
+func TestPodAges(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	pod := func(age time.Duration, deleting bool) corev1.Pod {
+		p := corev1.Pod{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-age))}}
+		if deleting {
+			deletedAt := metav1.NewTime(now)
+			p.DeletionTimestamp = &deletedAt
+		}
+		return p
+	}
+
+	if oldest, newest := podAges(nil, now); oldest != nil || newest != nil {
+		t.Fatalf("expected no ages without pods, got %v, %v", oldest, newest)
+	}
+
+	oldest, newest := podAges([]corev1.Pod{
+		pod(2*time.Hour, false),
+		pod(5*time.Minute, false),
+		pod(72*time.Hour, true),
+		pod(30*time.Minute, false),
+	}, now)
+	if oldest == nil || oldest.Duration != 2*time.Hour {
+		t.Errorf("expected the oldest pod to be 2h old, got %v", oldest)
+	}
+	if newest == nil || newest.Duration != 5*time.Minute {
+		t.Errorf("expected the newest pod to be 5m old, got %v", newest)
+	}
+}
+
+func TestProgressingCondition(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("progressing")
+	m.Spec.Replicas = 5
+	dep := r.deploymentForNginxCluster(m, "hash")
+
+	dep.Status = appsv1.DeploymentStatus{Replicas: 6, UpdatedReplicas: 3, AvailableReplicas: 4}
+	cond := progressingCondition(m, dep)
+	if cond.Status != metav1.ConditionTrue || cond.Reason != "RollingOut" {
+		t.Errorf("expected Progressing=True during the rollout, got %+v", cond)
+	}
+	if cond.Message != "3 of 5 updated, 4 available" {
+		t.Errorf("unexpected message %q", cond.Message)
+	}
+
+	dep.Status = appsv1.DeploymentStatus{Replicas: 5, UpdatedReplicas: 5, AvailableReplicas: 5}
+	if cond := progressingCondition(m, dep); cond.Status != metav1.ConditionFalse || cond.Reason != "RolloutComplete" {
+		t.Errorf("expected Progressing=False once rolled out, got %+v", cond)
+	}
+
+	dep.Status = appsv1.DeploymentStatus{Replicas: 6, UpdatedReplicas: 2, AvailableReplicas: 4, Conditions: []appsv1.DeploymentCondition{{
+		Type:   appsv1.DeploymentProgressing,
+		Status: corev1.ConditionFalse,
+		Reason: "ProgressDeadlineExceeded",
+	}}}
+	if cond := progressingCondition(m, dep); cond.Status != metav1.ConditionFalse || cond.Reason != "ProgressDeadlineExceeded" {
+		t.Errorf("expected a stalled rollout to report ProgressDeadlineExceeded, got %+v", cond)
+	}
+}
+
+func TestDeploymentHoldRollout(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+
+	m := newTestNginxCluster("hold-rollout")
+	dep := r.deploymentForNginxCluster(m, "hash")
+	if dep.Spec.Paused {
+		t.Fatalf("expected the Deployment not to be paused by default")
+	}
+	if cond := rolloutPausedCondition(m, dep); cond.Status != metav1.ConditionFalse {
+		t.Errorf("expected RolloutPaused=False, got %+v", cond)
+	}
+
+	m.Spec.HoldRollout = true
+	desired := r.deploymentForNginxCluster(m, "hash")
+	if !syncDeploymentSpec(dep, desired) || !dep.Spec.Paused {
+		t.Fatalf("expected holding the rollout to pause the Deployment")
+	}
+	if cond := rolloutPausedCondition(m, dep); cond.Status != metav1.ConditionTrue || cond.Reason != "HoldRollout" {
+		t.Errorf("expected RolloutPaused=True, got %+v", cond)
+	}
+
+	m.Spec.HoldRollout = false
+	if !syncDeploymentSpec(dep, r.deploymentForNginxCluster(m, "hash")) || dep.Spec.Paused {
+		t.Fatalf("expected clearing the hold to unpause the Deployment")
+	}
+}
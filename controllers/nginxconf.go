@@ -0,0 +1,345 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"math"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// http3Directives are merged into the first server block setting
+// ssl_certificate when HTTP/3 is enabled
+var http3Directives = []string{
+	"listen 443 quic reuseport;",
+	`add_header Alt-Svc 'h3=":443"; ma=86400';`,
+}
+
+// serverBlockPattern matches the opening of a server block
+var serverBlockPattern = regexp.MustCompile(`(?m)^([ \t]*)server\s*\{[ \t]*\n`)
+
+// sslCertificatePattern matches an ssl_certificate directive
+var sslCertificatePattern = regexp.MustCompile(`(?m)^[ \t]*ssl_certificate\s`)
+
+// workerProcessesPattern matches a worker_processes directive
+var workerProcessesPattern = regexp.MustCompile(`(?m)^[ \t]*worker_processes\s`)
+
+// clientMaxBodySizePattern matches a client_max_body_size directive
+var clientMaxBodySizePattern = regexp.MustCompile(`(?m)^[ \t]*client_max_body_size\s`)
+
+// workerRlimitNofilePattern matches a worker_rlimit_nofile directive
+var workerRlimitNofilePattern = regexp.MustCompile(`(?m)^[ \t]*worker_rlimit_nofile\s`)
+
+// workerConnectionsPattern matches the worker_connections directive of the
+// default configs, capturing everything up to its value
+var workerConnectionsPattern = regexp.MustCompile(`(?m)^([ \t]*worker_connections\s+)[0-9]+;`)
+
+// defaultListenPattern matches the listen directive of the default configs,
+// capturing its indentation
+var defaultListenPattern = regexp.MustCompile(`(?m)^([ \t]*)listen\s+80;\n`)
+
+// defaultProxyPassPattern matches the proxy_pass directive of the
+// ReverseProxy default config, capturing its indentation
+var defaultProxyPassPattern = regexp.MustCompile(`(?m)^([ \t]*)proxy_pass\s+http://backend;\n`)
+
+// httpBlockPattern matches the opening of the http block
+var httpBlockPattern = regexp.MustCompile(`(?m)^([ \t]*)http\s*\{[ \t]*\n`)
+
+// accessLogPattern matches an access_log directive, capturing its parameters
+var accessLogPattern = regexp.MustCompile(`(?m)^([ \t]*access_log\s+)([^;]*);`)
+
+// logSampledVariable is set by the split_clients block of log sampling to 1
+// for the requests that are logged
+const logSampledVariable = "$nginx_operator_log_sampled"
+
+// effectiveNginxConf returns the nginx configuration the operator writes to the
+// generated ConfigMap: the spec's config or active profile, or the default
+// one, with the directives required by spec features merged in.
+func effectiveNginxConf(m *nginxv1.NginxCluster) string {
+	conf := m.Spec.NginxConf
+	if m.Spec.ActiveProfile != "" {
+		conf = m.Spec.ConfigProfiles[m.Spec.ActiveProfile]
+	}
+	if conf == "" && m.Spec.DefaultConfigMode == "ReverseProxy" {
+		conf = withDefaultConfSettings(m, getReverseProxyNginxConf(m.Spec.Upstreams))
+	} else if conf == "" {
+		conf = withDefaultConfSettings(m, getDefaultNginxConf(m.Spec.DocumentRoot, m.Spec.IndexFiles))
+	}
+	return withFeatureDirectives(m, conf)
+}
+
+// withDefaultConfSettings applies the spec fields only the default configs
+// honor to conf
+func withDefaultConfSettings(m *nginxv1.NginxCluster, conf string) string {
+	conf = withGzip(m, withListenAddresses(m, withWorkerConnections(m, conf)))
+	return withLogFormat(m, withRateLimit(m, withProxyBuffers(m, conf)))
+}
+
+// withListenAddresses replaces the listen directive of a default config with
+// one per address of spec.listenAddresses
+func withListenAddresses(m *nginxv1.NginxCluster, conf string) string {
+	if len(m.Spec.ListenAddresses) == 0 {
+		return conf
+	}
+	return defaultListenPattern.ReplaceAllStringFunc(conf, func(directive string) string {
+		indent := defaultListenPattern.FindStringSubmatch(directive)[1]
+		var b strings.Builder
+		for _, addr := range m.Spec.ListenAddresses {
+			b.WriteString(indent + "listen " + net.JoinHostPort(addr, "80") + ";\n")
+		}
+		return b.String()
+	})
+}
+
+// withProxyBuffers adds the proxy buffer directives of the spec to the
+// location of the ReverseProxy default config
+func withProxyBuffers(m *nginxv1.NginxCluster, conf string) string {
+	var directives []string
+	for _, d := range []struct{ name, value string }{
+		{"proxy_buffer_size", m.Spec.ProxyBufferSize},
+		{"proxy_buffers", m.Spec.ProxyBuffers},
+		{"proxy_busy_buffers_size", m.Spec.ProxyBusyBuffersSize},
+	} {
+		if d.value != "" {
+			directives = append(directives, d.name+" "+d.value+";")
+		}
+	}
+	if len(directives) == 0 {
+		return conf
+	}
+	return defaultProxyPassPattern.ReplaceAllStringFunc(conf, func(directive string) string {
+		indent := defaultProxyPassPattern.FindStringSubmatch(directive)[1]
+		var b strings.Builder
+		b.WriteString(directive)
+		for _, d := range directives {
+			b.WriteString(indent + d + "\n")
+		}
+		return b.String()
+	})
+}
+
+// workerConnections returns the share of spec.totalConnections of each
+// replica, 0 when it is not set
+func workerConnections(m *nginxv1.NginxCluster) int32 {
+	if m.Spec.TotalConnections <= 0 {
+		return 0
+	}
+	return m.Spec.TotalConnections / max(m.Spec.Replicas, 1)
+}
+
+// withWorkerConnections sets the worker_connections of a default config to
+// the share of spec.totalConnections
+func withWorkerConnections(m *nginxv1.NginxCluster, conf string) string {
+	n := workerConnections(m)
+	if n == 0 {
+		return conf
+	}
+	return workerConnectionsPattern.ReplaceAllString(conf, "${1}"+strconv.Itoa(int(n))+";")
+}
+
+// withFeatureDirectives merges the directives required by spec features into
+// conf
+func withFeatureDirectives(m *nginxv1.NginxCluster, conf string) string {
+	if m.Spec.EnableHTTP3 && !strings.Contains(conf, " quic") {
+		// QUIC needs TLS, so a server without a certificate would stop
+		// nginx from starting
+		conf = injectTLSServerDirectives(conf, http3Directives)
+	}
+	if m.Spec.WorkerProcesses != "" && !workerProcessesPattern.MatchString(conf) {
+		// worker_processes is only valid in the main context
+		conf = "worker_processes " + m.Spec.WorkerProcesses + ";\n" + conf
+	}
+	if m.Spec.WorkerRlimitNofile > 0 && !workerRlimitNofilePattern.MatchString(conf) {
+		conf = "worker_rlimit_nofile " + strconv.Itoa(int(m.Spec.WorkerRlimitNofile)) + ";\n" + conf
+	}
+	if m.Spec.ClientMaxBodySize != "" && !clientMaxBodySizePattern.MatchString(conf) {
+		conf = injectHTTPDirectives(conf, []string{"client_max_body_size " + m.Spec.ClientMaxBodySize + ";"})
+	}
+	if len(m.Spec.ErrorPages) > 0 {
+		var missing []string
+		for _, d := range errorPageDirectives(m) {
+			if !strings.Contains(conf, d) {
+				missing = append(missing, d)
+			}
+		}
+		conf = injectServerDirectives(conf, missing)
+	}
+	if m.Spec.LogSampling != nil && !strings.Contains(conf, logSampledVariable) {
+		conf = withLogSampling(conf, m.Spec.LogSampling.Rate)
+	}
+	if m.Spec.EnableHealthEndpoint && !hasHealthEndpoint(conf) {
+		conf = withHealthEndpoint(conf)
+	}
+	if m.Spec.ReadOnlyRootFilesystem {
+		conf = withWritablePaths(conf)
+	}
+	if len(m.Spec.NjsScripts) > 0 {
+		conf = withNjsImports(m, conf)
+	}
+	if m.Spec.BasicAuth != nil {
+		conf = withBasicAuth(m, conf)
+	}
+	return conf
+}
+
+// withLogSampling adds a split_clients block selecting rate of the requests
+// to the http block, and makes every access_log directive log only those.
+// An access_log directive is added when the config has none. The config is
+// returned unchanged if it has no http block.
+func withLogSampling(conf, rate string) string {
+	loc := httpBlockPattern.FindStringSubmatchIndex(conf)
+	if loc == nil {
+		return conf
+	}
+	hasAccessLog := accessLogPattern.MatchString(conf)
+	indent := conf[loc[2]:loc[3]] + "    "
+	var b strings.Builder
+	b.WriteString(conf[:loc[1]])
+	b.WriteString(indent + `split_clients "${request_id}" ` + logSampledVariable + " {\n")
+	// split_clients rejects zero percentages, so the extremes only have the
+	// catch-all entry
+	switch hundredths := logSamplingHundredths(rate); hundredths {
+	case 0:
+		b.WriteString(indent + "    * 0;\n")
+	case 10000:
+		b.WriteString(indent + "    * 1;\n")
+	default:
+		b.WriteString(indent + "    " + strconv.FormatFloat(float64(hundredths)/100, 'f', -1, 64) + "% 1;\n")
+		b.WriteString(indent + "    * 0;\n")
+	}
+	b.WriteString(indent + "}\n")
+	if !hasAccessLog {
+		b.WriteString(indent + "access_log /var/log/nginx/access.log combined if=" + logSampledVariable + ";\n")
+	}
+	b.WriteString(conf[loc[1]:])
+
+	return accessLogPattern.ReplaceAllStringFunc(b.String(), func(directive string) string {
+		params := accessLogPattern.FindStringSubmatch(directive)[2]
+		if strings.TrimSpace(params) == "off" || strings.Contains(params, "if=") {
+			return directive
+		}
+		return strings.TrimSuffix(directive, ";") + " if=" + logSampledVariable + ";"
+	})
+}
+
+// logSamplingHundredths converts a sampling rate to hundredths of a percent,
+// the precision of split_clients
+func logSamplingHundredths(rate string) int {
+	f, err := strconv.ParseFloat(rate, 64)
+	if err != nil {
+		return 0
+	}
+	return int(math.Round(min(max(f, 0), 1) * 10000))
+}
+
+// injectServerDirectives inserts directives at the top of the first server
+// block. The config is returned unchanged if it has no server block.
+func injectServerDirectives(conf string, directives []string) string {
+	loc := serverBlockPattern.FindStringSubmatchIndex(conf)
+	if loc == nil {
+		return conf
+	}
+	indent := conf[loc[2]:loc[3]] + "    "
+	var b strings.Builder
+	b.WriteString(conf[:loc[1]])
+	for _, d := range directives {
+		b.WriteString(indent + d + "\n")
+	}
+	b.WriteString(conf[loc[1]:])
+	return b.String()
+}
+
+// injectTLSServerDirectives inserts directives at the top of the first server
+// block setting ssl_certificate. The config is returned unchanged if no server
+// block does.
+func injectTLSServerDirectives(conf string, directives []string) string {
+	for _, loc := range serverBlockPattern.FindAllStringSubmatchIndex(conf, -1) {
+		if !sslCertificatePattern.MatchString(conf[loc[1]:blockEnd(conf, loc[1])]) {
+			continue
+		}
+		indent := conf[loc[2]:loc[3]] + "    "
+		var b strings.Builder
+		b.WriteString(conf[:loc[1]])
+		for _, d := range directives {
+			b.WriteString(indent + d + "\n")
+		}
+		b.WriteString(conf[loc[1]:])
+		return b.String()
+	}
+	return conf
+}
+
+// blockEnd returns the offset of the brace closing the block whose body starts
+// at start, or len(conf) if it isn't closed
+func blockEnd(conf string, start int) int {
+	depth := 0
+	for i := start; i < len(conf); i++ {
+		switch conf[i] {
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return len(conf)
+}
+
+// injectHTTPDirectives inserts directives at the top of the http block. The
+// config is returned unchanged if it has no http block.
+func injectHTTPDirectives(conf string, directives []string) string {
+	loc := httpBlockPattern.FindStringSubmatchIndex(conf)
+	if loc == nil || len(directives) == 0 {
+		return conf
+	}
+	indent := conf[loc[2]:loc[3]] + "    "
+	var b strings.Builder
+	b.WriteString(conf[:loc[1]])
+	for _, d := range directives {
+		b.WriteString(indent + d + "\n")
+	}
+	b.WriteString(conf[loc[1]:])
+	return b.String()
+}
+
+// quicSupportWarning returns a warning when the image tag is an nginx release
+// older than 1.25, the first one shipping HTTP/3 support.
+func quicSupportWarning(image string) string {
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return ""
+	}
+	parts := strings.SplitN(strings.SplitN(image[i+1:], "-", 2)[0], ".", 3)
+	if len(parts) < 2 {
+		return ""
+	}
+	major, err1 := strconv.Atoi(parts[0])
+	minor, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil {
+		return ""
+	}
+	if major < 1 || (major == 1 && minor < 25) {
+		return "HTTP/3 is enabled but the nginx image likely predates QUIC support (1.25+)"
+	}
+	return ""
+}
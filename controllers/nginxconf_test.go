@@ -0,0 +1,337 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+	"testing"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+func TestEffectiveNginxConfHTTP3(t *testing.T) {
+	m := newTestNginxCluster("http3")
+	m.Spec.NginxConf = "http {\n    server {\n        listen 80;\n    }\n    server {\n        listen 443 ssl;\n" +
+		"        ssl_certificate /etc/nginx/tls/tls.crt;\n    }\n}\n"
+	if got := effectiveNginxConf(m); got != m.Spec.NginxConf {
+		t.Fatalf("config changed without HTTP/3:\n%s", got)
+	}
+
+	// The directives go into the server with a certificate, not the first one
+	m.Spec.EnableHTTP3 = true
+	want := "http {\n    server {\n        listen 80;\n    }\n    server {\n        listen 443 quic reuseport;\n" +
+		"        add_header Alt-Svc 'h3=\":443\"; ma=86400';\n        listen 443 ssl;\n" +
+		"        ssl_certificate /etc/nginx/tls/tls.crt;\n    }\n}\n"
+	if got := effectiveNginxConf(m); got != want {
+		t.Fatalf("unexpected effective config:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Configs that already listen for QUIC are left alone
+	m.Spec.NginxConf = want
+	if got := effectiveNginxConf(m); got != want {
+		t.Fatalf("QUIC directives merged twice:\n%s", got)
+	}
+
+	// Without a certificate nginx would refuse to start with a QUIC listener
+	m.Spec.NginxConf = ""
+	if got := effectiveNginxConf(m); strings.Contains(got, " quic") && !strings.Contains(got, "ssl_certificate") {
+		t.Fatalf("QUIC directives merged into a config without a certificate:\n%s", got)
+	}
+	m.Spec.NginxConf = "http {\n    server {\n        listen 80;\n    }\n}\n"
+	if got := effectiveNginxConf(m); got != m.Spec.NginxConf {
+		t.Fatalf("QUIC directives merged into a server without a certificate:\n%s", got)
+	}
+}
+
+func TestEffectiveNginxConfWorkerProcesses(t *testing.T) {
+	m := newTestNginxCluster("worker-processes")
+	m.Spec.NginxConf = ""
+	m.Spec.WorkerProcesses = "4"
+	if got := effectiveNginxConf(m); !strings.HasPrefix(got, "worker_processes 4;\n") {
+		t.Fatalf("worker_processes missing from default config:\n%s", got)
+	}
+
+	m.Spec.NginxConf = "events {}\nhttp {}\n"
+	if got, want := effectiveNginxConf(m), "worker_processes 4;\nevents {}\nhttp {}\n"; got != want {
+		t.Fatalf("unexpected effective config:\n%s\nwant:\n%s", got, want)
+	}
+	hash := calculateConfigHash(effectiveNginxConf(m))
+	m.Spec.WorkerProcesses = "auto"
+	if calculateConfigHash(effectiveNginxConf(m)) == hash {
+		t.Errorf("expected changing worker_processes to change the config hash")
+	}
+
+	// A directive already in the config wins
+	m.Spec.NginxConf = "worker_processes 2;\nevents {}\n"
+	if got := effectiveNginxConf(m); got != m.Spec.NginxConf {
+		t.Fatalf("worker_processes injected twice:\n%s", got)
+	}
+}
+
+func TestEffectiveNginxConfWorkerRlimitNofile(t *testing.T) {
+	m := newTestNginxCluster("worker-rlimit-nofile")
+	m.Spec.NginxConf = "events {}\n"
+	m.Spec.WorkerRlimitNofile = 65535
+	if got, want := effectiveNginxConf(m), "worker_rlimit_nofile 65535;\nevents {}\n"; got != want {
+		t.Fatalf("unexpected effective config:\n%s\nwant:\n%s", got, want)
+	}
+
+	// A directive already in the config wins
+	m.Spec.NginxConf = "worker_rlimit_nofile 1024;\nevents {}\n"
+	if got := effectiveNginxConf(m); got != m.Spec.NginxConf {
+		t.Fatalf("worker_rlimit_nofile injected twice:\n%s", got)
+	}
+}
+
+func TestEffectiveNginxConfClientMaxBodySize(t *testing.T) {
+	m := newTestNginxCluster("client-max-body-size")
+	m.Spec.NginxConf = ""
+	m.Spec.ClientMaxBodySize = "50m"
+	conf := effectiveNginxConf(m)
+	if !strings.Contains(conf, "http {\n    client_max_body_size 50m;\n") {
+		t.Fatalf("client_max_body_size missing from the default config:\n%s", conf)
+	}
+	m.Spec.ClientMaxBodySize = "100m"
+	if calculateConfigHash(effectiveNginxConf(m)) == calculateConfigHash(conf) {
+		t.Fatalf("client_max_body_size not part of the config hash")
+	}
+
+	m.Spec.NginxConf = "events {}\nhttp {\n}\n"
+	if got, want := effectiveNginxConf(m), "events {}\nhttp {\n    client_max_body_size 100m;\n}\n"; got != want {
+		t.Fatalf("unexpected effective config:\n%s\nwant:\n%s", got, want)
+	}
+	// A directive already in the config wins, even in a server block
+	m.Spec.NginxConf = "events {}\nhttp {\n    server {\n        client_max_body_size 10m;\n    }\n}\n"
+	if got := effectiveNginxConf(m); got != m.Spec.NginxConf {
+		t.Fatalf("client_max_body_size injected twice:\n%s", got)
+	}
+}
+
+func TestEffectiveNginxConfProxyBuffers(t *testing.T) {
+	m := newTestNginxCluster("proxy-buffers")
+	m.Spec.NginxConf = ""
+	m.Spec.DefaultConfigMode = "ReverseProxy"
+	m.Spec.Upstreams = []string{"backend:8080"}
+	m.Spec.ProxyBufferSize = "16k"
+	m.Spec.ProxyBuffers = "8 16k"
+	conf := effectiveNginxConf(m)
+	want := "            proxy_pass http://backend;\n" +
+		"            proxy_buffer_size 16k;\n" +
+		"            proxy_buffers 8 16k;\n" +
+		"            proxy_set_header Host $host;\n"
+	if !strings.Contains(conf, want) {
+		t.Fatalf("proxy buffers missing from the location:\n%s", conf)
+	}
+	m.Spec.ProxyBusyBuffersSize = "32k"
+	if calculateConfigHash(effectiveNginxConf(m)) == calculateConfigHash(conf) {
+		t.Fatalf("proxy_busy_buffers_size not part of the config hash")
+	}
+
+	// Only the ReverseProxy default config is changed
+	m.Spec.NginxConf = "events {}\nhttp {\n    server {\n        location / {\n            proxy_pass http://backend;\n        }\n    }\n}\n"
+	if got := effectiveNginxConf(m); got != m.Spec.NginxConf {
+		t.Fatalf("proxy buffers added to the spec config:\n%s", got)
+	}
+}
+
+func TestEffectiveNginxConfTotalConnections(t *testing.T) {
+	m := newTestNginxCluster("total-connections")
+	m.Spec.NginxConf = ""
+	m.Spec.TotalConnections = 4096
+	conf := effectiveNginxConf(m)
+	if !strings.Contains(conf, "    worker_connections 2048;\n") {
+		t.Fatalf("worker_connections not derived from 2 replicas:\n%s", conf)
+	}
+
+	// Scaling changes the share, and so rolls the pods
+	m.Spec.Replicas = 4
+	if got := effectiveNginxConf(m); !strings.Contains(got, "worker_connections 1024;") || calculateConfigHash(got) == calculateConfigHash(conf) {
+		t.Fatalf("expected scaling to change worker_connections:\n%s", got)
+	}
+	m.Spec.DefaultConfigMode = "ReverseProxy"
+	m.Spec.Upstreams = []string{"app:8080"}
+	if got := effectiveNginxConf(m); !strings.Contains(got, "worker_connections 1024;") {
+		t.Fatalf("worker_connections not set in the reverse proxy config:\n%s", got)
+	}
+
+	// Inline configs are left alone
+	m.Spec.NginxConf = "events { worker_connections 512; }\n"
+	if got := effectiveNginxConf(m); got != m.Spec.NginxConf {
+		t.Fatalf("inline config changed:\n%s", got)
+	}
+
+	if err := validateSpec(m); err != nil {
+		t.Fatalf("validateSpec() error = %v", err)
+	}
+	m.Spec.Replicas = 100
+	if err := validateSpec(m); err == nil {
+		t.Fatalf("expected a share below %d to be rejected", nginxv1.MinWorkerConnections)
+	}
+}
+
+func TestEffectiveNginxConfListenAddresses(t *testing.T) {
+	m := newTestNginxCluster("listen-addresses")
+	m.Spec.NginxConf = ""
+	conf := effectiveNginxConf(m)
+	m.Spec.ListenAddresses = []string{"10.0.0.5", "fd00::5"}
+	got := effectiveNginxConf(m)
+	if !strings.Contains(got, "        listen 10.0.0.5:80;\n        listen [fd00::5]:80;\n") || strings.Contains(got, "listen       80;") {
+		t.Fatalf("listen addresses not rendered:\n%s", got)
+	}
+	if calculateConfigHash(got) == calculateConfigHash(conf) {
+		t.Fatalf("expected the listen addresses to change the config hash")
+	}
+	m.Spec.DefaultConfigMode = "ReverseProxy"
+	m.Spec.Upstreams = []string{"app:8080"}
+	if got := effectiveNginxConf(m); !strings.Contains(got, "listen 10.0.0.5:80;") {
+		t.Fatalf("listen addresses not rendered in the reverse proxy config:\n%s", got)
+	}
+
+	if err := validateSpec(m); err != nil {
+		t.Fatalf("validateSpec() error = %v", err)
+	}
+	m.Spec.ListenAddresses = []string{"eth0"}
+	if err := validateSpec(m); err == nil {
+		t.Fatalf("expected an interface name to be rejected")
+	}
+}
+
+func TestEffectiveNginxConfDocumentRoot(t *testing.T) {
+	m := newTestNginxCluster("document-root")
+	m.Spec.NginxConf = ""
+	conf := effectiveNginxConf(m)
+	if !strings.Contains(conf, "root   /usr/share/nginx/html;\n            index  index.html index.htm;") {
+		t.Fatalf("unexpected default config:\n%s", conf)
+	}
+
+	m.Spec.DocumentRoot = "/srv/www"
+	m.Spec.IndexFiles = []string{"index.php", "index.html"}
+	changed := effectiveNginxConf(m)
+	if !strings.Contains(changed, "root   /srv/www;\n            index  index.php index.html;") {
+		t.Fatalf("document root not rendered:\n%s", changed)
+	}
+	if calculateConfigHash(changed) == calculateConfigHash(conf) {
+		t.Fatalf("changing the document root keeps the config hash")
+	}
+}
+
+func TestEffectiveNginxConfActiveProfile(t *testing.T) {
+	m := newTestNginxCluster("profiles")
+	m.Spec.NginxConf = ""
+	m.Spec.ConfigProfiles = map[string]string{
+		"staging":    "events {}\nhttp { server { listen 8080; } }\n",
+		"production": "events {}\nhttp { server { listen 80; } }\n",
+	}
+	m.Spec.ActiveProfile = "staging"
+	staging := effectiveNginxConf(m)
+	if staging != m.Spec.ConfigProfiles["staging"] {
+		t.Fatalf("active profile not used:\n%s", staging)
+	}
+
+	m.Spec.ActiveProfile = "production"
+	if calculateConfigHash(effectiveNginxConf(m)) == calculateConfigHash(staging) {
+		t.Fatalf("switching profiles keeps the config hash")
+	}
+}
+
+func TestEffectiveNginxConfReverseProxy(t *testing.T) {
+	m := newTestNginxCluster("reverse-proxy")
+	m.Spec.NginxConf = ""
+	m.Spec.DefaultConfigMode = "ReverseProxy"
+	m.Spec.Upstreams = []string{"app-1.default.svc:8080", "app-2.default.svc:8080"}
+	conf := effectiveNginxConf(m)
+	for _, want := range []string{
+		"upstream backend {\n        server app-1.default.svc:8080;\n        server app-2.default.svc:8080;\n    }",
+		"proxy_pass http://backend;",
+	} {
+		if !strings.Contains(conf, want) {
+			t.Errorf("expected %q in the config:\n%s", want, conf)
+		}
+	}
+
+	// An explicit config wins over the default
+	m.Spec.NginxConf = "events {}\n"
+	if got := effectiveNginxConf(m); got != m.Spec.NginxConf {
+		t.Fatalf("default config used despite nginxConf:\n%s", got)
+	}
+}
+
+func TestDeploymentHTTP3Ports(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("http3-ports")
+	m.Spec.EnableHTTP3 = true
+
+	ports := r.deploymentForNginxCluster(m, "hash").Spec.Template.Spec.Containers[0].Ports
+	if len(ports) != 2 || ports[1].ContainerPort != 443 || ports[1].Protocol != "UDP" {
+		t.Fatalf("expected UDP 443 container port, got %+v", ports)
+	}
+	svcPorts := r.serviceForNginxCluster(m).Spec.Ports
+	if len(svcPorts) != 2 || svcPorts[1].Port != 443 || svcPorts[1].Protocol != "UDP" {
+		t.Fatalf("expected UDP 443 service port, got %+v", svcPorts)
+	}
+}
+
+func TestQUICSupportWarning(t *testing.T) {
+	for image, warn := range map[string]bool{
+		"nginx:1.24":                    true,
+		"nginx:1.23.4-alpine":           true,
+		"nginx:1.25":                    false,
+		"nginx:1.27.0":                  false,
+		"nginx:latest":                  false,
+		"registry.local:5000/nginx":     false,
+		"registry.local:5000/nginx:1.2": true,
+	} {
+		if got := quicSupportWarning(image) != ""; got != warn {
+			t.Errorf("quicSupportWarning(%q) warns = %v, want %v", image, got, warn)
+		}
+	}
+}
+
+func TestEffectiveNginxConfLogSampling(t *testing.T) {
+	m := newTestNginxCluster("log-sampling")
+	m.Spec.NginxConf = "http {\n    access_log /var/log/nginx/access.log main;\n    server {\n        access_log off;\n    }\n}\n"
+	m.Spec.LogSampling = &nginxv1.LogSamplingSpec{Rate: "0.125"}
+	want := "http {\n" +
+		"    split_clients \"${request_id}\" $nginx_operator_log_sampled {\n        12.5% 1;\n        * 0;\n    }\n" +
+		"    access_log /var/log/nginx/access.log main if=$nginx_operator_log_sampled;\n" +
+		"    server {\n        access_log off;\n    }\n}\n"
+	got := effectiveNginxConf(m)
+	if got != want {
+		t.Fatalf("unexpected effective config:\n%s\nwant:\n%s", got, want)
+	}
+	m.Spec.NginxConf = got
+	if got := effectiveNginxConf(m); got != want {
+		t.Fatalf("sampling merged twice:\n%s", got)
+	}
+
+	// The default config gets an access_log directive
+	m.Spec.NginxConf = ""
+	if got := effectiveNginxConf(m); !strings.Contains(got, "access_log /var/log/nginx/access.log combined if=$nginx_operator_log_sampled;") {
+		t.Fatalf("sampled access log missing from default config:\n%s", got)
+	}
+
+	for rate, entries := range map[string]string{
+		"0":      "        * 0;\n    }",
+		"1":      "        * 1;\n    }",
+		"0.0001": "        0.01% 1;\n        * 0;\n    }",
+	} {
+		m.Spec.LogSampling.Rate = rate
+		if got := effectiveNginxConf(m); !strings.Contains(got, "$nginx_operator_log_sampled {\n"+entries) {
+			t.Errorf("rate %s: unexpected split_clients block:\n%s", rate, got)
+		}
+	}
+}
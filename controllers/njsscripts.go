@@ -0,0 +1,170 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+const (
+	njsScriptsConfigMapSuffix = "-njs-scripts"
+	njsScriptsVolumeName      = "njs-scripts"
+	njsScriptsDir             = "/etc/nginx/njs"
+	// njsModule is the dynamic module of the official images providing the
+	// js_* directives of the http context, relative to the nginx prefix
+	njsModule = "modules/ngx_http_js_module.so"
+)
+
+// njsModulePattern matches a load_module directive of the njs http module
+var njsModulePattern = regexp.MustCompile(`(?m)^[ \t]*load_module\s+\S*ngx_http_js_module\.so`)
+
+// njsScriptNames returns the file names of spec.njsScripts in order
+func njsScriptNames(m *nginxv1.NginxCluster) []string {
+	names := make([]string, 0, len(m.Spec.NjsScripts))
+	for name := range m.Spec.NjsScripts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// withNjsImports loads the njs module in the main context of conf and
+// imports the scripts of spec.njsScripts in its http block, each under its
+// file name without .js. Directives conf has itself are not repeated.
+func withNjsImports(m *nginxv1.NginxCluster, conf string) string {
+	if !njsModulePattern.MatchString(conf) {
+		// load_module is only valid in the main context
+		conf = "load_module " + njsModule + ";\n" + conf
+	}
+	var missing []string
+	if !strings.Contains(conf, "js_path ") {
+		missing = append(missing, `js_path "`+njsScriptsDir+`/";`)
+	}
+	for _, name := range njsScriptNames(m) {
+		d := fmt.Sprintf("js_import %s from %s;", strings.TrimSuffix(name, ".js"), name)
+		if !strings.Contains(conf, d) {
+			missing = append(missing, d)
+		}
+	}
+	return injectHTTPDirectives(conf, missing)
+}
+
+// withNjsScripts folds the content of the njs scripts into configHash, so
+// changing a script rolls the pods: nginx only reads them when it loads its
+// config.
+func withNjsScripts(m *nginxv1.NginxCluster, configHash string) string {
+	if len(m.Spec.NjsScripts) == 0 {
+		return configHash
+	}
+	var b strings.Builder
+	b.WriteString(configHash)
+	for _, name := range njsScriptNames(m) {
+		fmt.Fprintf(&b, "\nnjs-script/%s:%s", name, calculateConfigHash(m.Spec.NjsScripts[name]))
+	}
+	return calculateConfigHash(b.String())
+}
+
+// addNjsScripts mounts the scripts of spec.njsScripts into njsScriptsDir of
+// the nginx container
+func addNjsScripts(podSpec *corev1.PodSpec, m *nginxv1.NginxCluster) {
+	if len(m.Spec.NjsScripts) == 0 {
+		return
+	}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: njsScriptsVolumeName,
+		VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: m.Name + njsScriptsConfigMapSuffix},
+		}},
+	})
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      njsScriptsVolumeName,
+		MountPath: njsScriptsDir,
+		ReadOnly:  true,
+	})
+}
+
+// reconcileNjsScripts creates or updates the ConfigMap holding the njs
+// scripts when the spec has some, and removes a previously created one
+// otherwise.
+func (r *NginxClusterReconciler) reconcileNjsScripts(ctx context.Context, m *nginxv1.NginxCluster) error {
+	logger := log.FromContext(ctx)
+
+	cm := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Name: m.Name + njsScriptsConfigMapSuffix, Namespace: workloadNamespace(m)}, cm)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+
+	if len(m.Spec.NjsScripts) == 0 {
+		if exists && r.isOwnedBy(cm, m) {
+			logger.Info("Deleting njs scripts ConfigMap", "ConfigMap.Namespace", cm.Namespace, "ConfigMap.Name", cm.Name)
+			if err := r.Delete(ctx, cm); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+		return nil
+	}
+
+	desired := r.njsScriptsConfigMapForNginxCluster(m)
+	if !exists {
+		logger.Info("Creating a new njs scripts ConfigMap", "ConfigMap.Namespace", desired.Namespace, "ConfigMap.Name", desired.Name)
+		return r.createObject(ctx, desired)
+	}
+	if !r.isOwnedBy(cm, m) {
+		return fmt.Errorf("ConfigMap %s/%s exists and is not managed by the NginxCluster", cm.Namespace, cm.Name)
+	}
+
+	if !reflect.DeepEqual(cm.Data, desired.Data) {
+		logger.Info("Updating njs scripts ConfigMap", "ConfigMap.Namespace", cm.Namespace, "ConfigMap.Name", cm.Name)
+		return r.updateObject(ctx, cm, desired, func() {
+			cm.Data = desired.Data
+		})
+	}
+	return nil
+}
+
+// njsScriptsConfigMapForNginxCluster returns the ConfigMap holding the njs
+// scripts, keyed by file name
+func (r *NginxClusterReconciler) njsScriptsConfigMapForNginxCluster(m *nginxv1.NginxCluster) *corev1.ConfigMap {
+	data := make(map[string]string, len(m.Spec.NjsScripts))
+	for name, script := range m.Spec.NjsScripts {
+		data[name] = script
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      m.Name + njsScriptsConfigMapSuffix,
+			Namespace: workloadNamespace(m),
+		},
+		Data: data,
+	}
+	r.setOwner(m, cm)
+	return cm
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEffectiveNginxConfNjsScripts(t *testing.T) {
+	m := newTestNginxCluster("njs")
+	m.Spec.NginxConf = "events {}\nhttp {\n    server {\n        location / { js_content main.handler; }\n    }\n}\n"
+	m.Spec.NjsScripts = map[string]string{"main.js": "export default {handler};", "auth.js": ""}
+	conf := effectiveNginxConf(m)
+	if !strings.HasPrefix(conf, "load_module modules/ngx_http_js_module.so;\n") {
+		t.Fatalf("njs module not loaded:\n%s", conf)
+	}
+	want := "http {\n    js_path \"/etc/nginx/njs/\";\n    js_import auth from auth.js;\n    js_import main from main.js;\n    server {"
+	if !strings.Contains(conf, want) {
+		t.Fatalf("js_import directives missing from the http block:\n%s", conf)
+	}
+
+	// Configs that already import the scripts are left alone
+	m.Spec.NginxConf = conf
+	if got := effectiveNginxConf(m); got != conf {
+		t.Fatalf("njs directives merged twice:\n%s", got)
+	}
+}
+
+func TestDeploymentMountsNjsScripts(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("njs")
+	m.Spec.NjsScripts = map[string]string{"main.js": "export default {};"}
+	dep := r.deploymentForNginxCluster(m, "hash")
+	volumes := dep.Spec.Template.Spec.Volumes
+	if v := volumes[len(volumes)-1]; v.ConfigMap == nil || v.ConfigMap.Name != "njs-njs-scripts" {
+		t.Fatalf("unexpected njs scripts volume %+v", v)
+	}
+	mounts := dep.Spec.Template.Spec.Containers[0].VolumeMounts
+	if mount := mounts[len(mounts)-1]; mount.MountPath != "/etc/nginx/njs" || !mount.ReadOnly {
+		t.Fatalf("unexpected njs scripts mount %+v", mount)
+	}
+
+	cm := r.njsScriptsConfigMapForNginxCluster(m)
+	if cm.Data["main.js"] != "export default {};" {
+		t.Fatalf("unexpected njs scripts ConfigMap data %v", cm.Data)
+	}
+}
+
+func TestWithNjsScripts(t *testing.T) {
+	m := newTestNginxCluster("njs")
+	if got := withNjsScripts(m, "hash"); got != "hash" {
+		t.Fatalf("hash changed without njs scripts: %s", got)
+	}
+	m.Spec.NjsScripts = map[string]string{"main.js": "export default {};"}
+	first := withNjsScripts(m, "hash")
+	m.Spec.NjsScripts["main.js"] = "export default {handler};"
+	if first == "hash" || withNjsScripts(m, "hash") == first {
+		t.Fatalf("script content not part of the config hash")
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// DefaultLockConfigMap is the name of the ConfigMap that pauses the
+	// operator, looked up in the operator's namespace
+	DefaultLockConfigMap = "nginx-operator-lock"
+	// lockPausedKey is the key of the lock ConfigMap that pauses the operator
+	// when set to "true"
+	lockPausedKey = "paused"
+	// lockRecheckInterval is how often paused clusters check the lock again
+	lockRecheckInterval = 30 * time.Second
+	// serviceAccountNamespaceFile holds the namespace of the operator's pod
+	serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+// ParseLockConfigMap returns the lock ConfigMap of the --lock-configmap flag,
+// either namespace/name or a name in the operator's namespace. An empty
+// value disables the lock.
+func ParseLockConfigMap(value string) (types.NamespacedName, error) {
+	if value == "" {
+		return types.NamespacedName{}, nil
+	}
+	if namespace, name, ok := strings.Cut(value, "/"); ok {
+		if namespace == "" || name == "" {
+			return types.NamespacedName{}, fmt.Errorf("lock ConfigMap %q is not namespace/name", value)
+		}
+		return types.NamespacedName{Namespace: namespace, Name: name}, nil
+	}
+	namespace, err := os.ReadFile(serviceAccountNamespaceFile)
+	if err != nil {
+		return types.NamespacedName{}, fmt.Errorf("namespace of lock ConfigMap %q unknown outside the cluster, give it as namespace/name: %w", value, err)
+	}
+	return types.NamespacedName{Namespace: strings.TrimSpace(string(namespace)), Name: value}, nil
+}
+
+// pausedByLock reports whether the lock ConfigMap pauses the operator. A
+// missing ConfigMap doesn't.
+func (r *NginxClusterReconciler) pausedByLock(ctx context.Context) (bool, error) {
+	if r.LockConfigMap.Name == "" {
+		return false, nil
+	}
+	lock := &corev1.ConfigMap{}
+	if err := r.Get(ctx, r.LockConfigMap, lock); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return lock.Data[lockPausedKey] == "true", nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestParseLockConfigMap(t *testing.T) {
+	if lock, err := ParseLockConfigMap(""); err != nil || lock.Name != "" {
+		t.Fatalf("ParseLockConfigMap(\"\") = %v, %v, want the lock disabled", lock, err)
+	}
+	lock, err := ParseLockConfigMap("maintenance/nginx-operator-lock")
+	if err != nil || lock != (types.NamespacedName{Namespace: "maintenance", Name: "nginx-operator-lock"}) {
+		t.Fatalf("ParseLockConfigMap() = %v, %v", lock, err)
+	}
+	if _, err := ParseLockConfigMap("/nginx-operator-lock"); err == nil {
+		t.Fatalf("expected an empty namespace to be rejected")
+	}
+}
+
+func TestReconcilePausedByLock(t *testing.T) {
+	requireEnvtest(t)
+	ctx := context.Background()
+
+	lock := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx-operator-lock-test", Namespace: "default"},
+		Data:       map[string]string{lockPausedKey: "true"},
+	}
+	if err := k8sClient.Create(ctx, lock); err != nil {
+		t.Fatalf("failed to create lock ConfigMap: %v", err)
+	}
+	t.Cleanup(func() { _ = k8sClient.Delete(context.Background(), lock) })
+
+	r := &NginxClusterReconciler{Client: k8sClient, Scheme: testScheme, LockConfigMap: types.NamespacedName{Name: lock.Name, Namespace: lock.Namespace}}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "paused-by-lock", Namespace: "default"}}
+	result, err := r.Reconcile(ctx, req)
+	if err != nil || result.RequeueAfter != lockRecheckInterval {
+		t.Fatalf("Reconcile() = %+v, %v while paused", result, err)
+	}
+
+	lock.Data[lockPausedKey] = "false"
+	if err := k8sClient.Update(ctx, lock); err != nil {
+		t.Fatalf("failed to update lock ConfigMap: %v", err)
+	}
+	// The NginxCluster doesn't exist, so reconciling it returns right away
+	if result, err := r.Reconcile(ctx, req); err != nil || result != (ctrl.Result{}) {
+		t.Fatalf("Reconcile() = %+v, %v after unpausing", result, err)
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Version of the operator build, set with
+// -ldflags "-X github.com/example/nginx-operator/controllers.Version=v1.2.3"
+var Version = "devel"
+
+// managedByVersionAnnotation records the operator version that last wrote a
+// ConfigMap, Deployment or Service, to find the ones a stale operator manages
+// during an upgrade
+const managedByVersionAnnotation = "nginx.example.com/managed-by-version"
+
+// stampOperatorVersion sets the managed-by-version annotation of obj to the
+// running operator's version
+func stampOperatorVersion(obj metav1.Object) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[managedByVersionAnnotation] = Version
+	obj.SetAnnotations(annotations)
+}
+
+// carryOperatorVersion stamps obj with the version when desired, the object
+// it is updated to, carries one. The version alone doesn't count as drift, so
+// it is only written along with other changes.
+func carryOperatorVersion(obj, desired metav1.Object) {
+	if _, ok := desired.GetAnnotations()[managedByVersionAnnotation]; ok {
+		stampOperatorVersion(obj)
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+func TestManagedResourcesCarryOperatorVersion(t *testing.T) {
+	defer func(v string) { Version = v }(Version)
+	Version = "v1.2.3"
+
+	r := &NginxClusterReconciler{Client: deploymentUpdateClient{}, Scheme: testScheme}
+	m := newTestNginxCluster("operator-version")
+	for _, annotations := range []map[string]string{
+		r.configMapForNginxCluster(m, "events {}\n", "hash").Annotations,
+		r.deploymentForNginxCluster(m, "hash").Annotations,
+		r.serviceForNginxCluster(m).Annotations,
+	} {
+		if annotations[managedByVersionAnnotation] != "v1.2.3" {
+			t.Fatalf("managed-by-version annotation = %q, want v1.2.3", annotations[managedByVersionAnnotation])
+		}
+	}
+
+	// A Deployment written by an older operator picks up the version with the
+	// next update
+	Version = "v1.3.0"
+	dep := r.deploymentForNginxCluster(m, "hash")
+	dep.Annotations[managedByVersionAnnotation] = "v1.2.3"
+	replicas := int32(3)
+	if err := r.updateDeployment(context.Background(), dep, r.deploymentForNginxCluster(m, "hash"), func(d *appsv1.Deployment) {
+		d.Spec.Replicas = &replicas
+	}); err != nil {
+		t.Fatalf("updateDeployment() error = %v", err)
+	}
+	if got := dep.Annotations[managedByVersionAnnotation]; got != "v1.3.0" {
+		t.Fatalf("managed-by-version annotation = %q after the update, want v1.3.0", got)
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// Management labels identify the NginxCluster a resource belongs to when owner
+// references are disabled or the resource lives in another namespace.
+const (
+	managedByNameLabel      = "nginx.example.com/cluster-name"
+	managedByNamespaceLabel = "nginx.example.com/cluster-namespace"
+)
+
+// workloadNamespace returns the namespace the resources of m are created in
+func workloadNamespace(m *nginxv1.NginxCluster) string {
+	if m.Spec.TargetNamespace != "" {
+		return m.Spec.TargetNamespace
+	}
+	return m.Namespace
+}
+
+// usesOwnerReferences reports whether the resources of m are tied to it by
+// owner references. Owner references cannot cross namespaces, so resources in
+// a target namespace fall back to the management labels.
+func (r *NginxClusterReconciler) usesOwnerReferences(m *nginxv1.NginxCluster) bool {
+	return !r.DisableOwnerReferences && workloadNamespace(m) == m.Namespace
+}
+
+// setOwner marks obj as managed by m, either through a controller reference or,
+// when owner references can't be used, through the management labels.
+func (r *NginxClusterReconciler) setOwner(m *nginxv1.NginxCluster, obj metav1.Object) {
+	if !r.usesOwnerReferences(m) {
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		for k, v := range managementLabelsForNginxCluster(m) {
+			labels[k] = v
+		}
+		obj.SetLabels(labels)
+		return
+	}
+	// Set NginxCluster instance as the owner and controller
+	ctrl.SetControllerReference(m, obj, r.Scheme)
+	if r.DisableBlockOwnerDeletion {
+		refs := obj.GetOwnerReferences()
+		for i := range refs {
+			if refs[i].UID == m.UID {
+				block := false
+				refs[i].BlockOwnerDeletion = &block
+			}
+		}
+		obj.SetOwnerReferences(refs)
+	}
+}
+
+// isOwnedBy reports whether obj is managed by m
+func (r *NginxClusterReconciler) isOwnedBy(obj metav1.Object, m *nginxv1.NginxCluster) bool {
+	if !r.usesOwnerReferences(m) {
+		labels := obj.GetLabels()
+		return labels[managedByNameLabel] == m.Name && labels[managedByNamespaceLabel] == m.Namespace
+	}
+	return metav1.IsControlledBy(obj, m)
+}
+
+// managementLabelsForNginxCluster returns the labels tagging resources of m
+func managementLabelsForNginxCluster(m *nginxv1.NginxCluster) map[string]string {
+	return map[string]string{
+		managedByNameLabel:      m.Name,
+		managedByNamespaceLabel: m.Namespace,
+	}
+}
+
+// deleteManagedResources removes the resources labelled as managed by m. It
+// replaces garbage collection when owner references are not used.
+func (r *NginxClusterReconciler) deleteManagedResources(ctx context.Context, m *nginxv1.NginxCluster) error {
+	logger := log.FromContext(ctx)
+
+	lists := []client.ObjectList{
+		&appsv1.DeploymentList{},
+		&corev1.ServiceList{},
+		&corev1.ConfigMapList{},
+		&networkingv1.NetworkPolicyList{},
+	}
+	for _, list := range lists {
+		if err := r.List(ctx, list, client.InNamespace(workloadNamespace(m)), client.MatchingLabels(managementLabelsForNginxCluster(m))); err != nil {
+			return err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			obj := item.(client.Object)
+			logger.Info("Deleting managed resource", "Namespace", obj.GetNamespace(), "Name", obj.GetName())
+			if err := r.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+	for _, gvk := range []schema.GroupVersionKind{routeGVK, httpRouteGVK, vpaGVK} {
+		if err := r.deleteManagedUnstructured(ctx, m, gvk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requestForManagedObject maps a labelled resource back to its NginxCluster
+func requestForManagedObject(ctx context.Context, obj client.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+	name, namespace := labels[managedByNameLabel], labels[managedByNamespaceLabel]
+	if name == "" || namespace == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestDisableOwnerReferencesUsesManagementLabels(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme, DisableOwnerReferences: true}
+	m := newTestNginxCluster("external")
+
+	for _, obj := range []client.Object{
+		r.configMapForNginxCluster(m, effectiveNginxConf(m), "hash"),
+		r.deploymentForNginxCluster(m, "hash"),
+		r.serviceForNginxCluster(m),
+	} {
+		if refs := obj.GetOwnerReferences(); len(refs) != 0 {
+			t.Errorf("%T has owner references %v", obj, refs)
+		}
+		if !r.isOwnedBy(obj, m) {
+			t.Errorf("%T is not tagged as managed by %s: %v", obj, m.Name, obj.GetLabels())
+		}
+		reqs := requestForManagedObject(context.Background(), obj)
+		want := types.NamespacedName{Name: m.Name, Namespace: m.Namespace}
+		if len(reqs) != 1 || reqs[0].NamespacedName != want {
+			t.Errorf("%T maps to %v, want %v", obj, reqs, want)
+		}
+	}
+
+	other := newTestNginxCluster("other")
+	if r.isOwnedBy(r.serviceForNginxCluster(m), other) {
+		t.Errorf("Service of %s reported as managed by %s", m.Name, other.Name)
+	}
+}
+
+func TestDisableBlockOwnerDeletion(t *testing.T) {
+	m := newTestNginxCluster("owner-deletion")
+	m.UID = "owner-deletion-uid"
+
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	refs := r.serviceForNginxCluster(m).GetOwnerReferences()
+	if len(refs) != 1 || refs[0].BlockOwnerDeletion == nil || !*refs[0].BlockOwnerDeletion {
+		t.Fatalf("expected the owner reference to block owner deletion, got %+v", refs)
+	}
+
+	r.DisableBlockOwnerDeletion = true
+	for _, obj := range []client.Object{
+		r.configMapForNginxCluster(m, effectiveNginxConf(m), "hash"),
+		r.deploymentForNginxCluster(m, "hash"),
+		r.serviceForNginxCluster(m),
+	} {
+		refs := obj.GetOwnerReferences()
+		if len(refs) != 1 || refs[0].Controller == nil || !*refs[0].Controller || refs[0].BlockOwnerDeletion == nil || *refs[0].BlockOwnerDeletion {
+			t.Errorf("%T has owner references %+v", obj, refs)
+		}
+		if !r.isOwnedBy(obj, m) {
+			t.Errorf("%T is not owned by %s", obj, m.Name)
+		}
+	}
+}
+
+func TestTargetNamespaceFallsBackToManagementLabels(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("cross-namespace")
+	m.UID = "cross-namespace-uid"
+	m.Spec.TargetNamespace = "team-a"
+
+	for _, obj := range []client.Object{
+		r.configMapForNginxCluster(m, effectiveNginxConf(m), "hash"),
+		r.deploymentForNginxCluster(m, "hash"),
+		r.serviceForNginxCluster(m),
+	} {
+		if obj.GetNamespace() != "team-a" {
+			t.Errorf("%T created in %q, want team-a", obj, obj.GetNamespace())
+		}
+		if refs := obj.GetOwnerReferences(); len(refs) != 0 {
+			t.Errorf("%T has cross-namespace owner references %v", obj, refs)
+		}
+		reqs := requestForManagedObject(context.Background(), obj)
+		want := types.NamespacedName{Name: m.Name, Namespace: m.Namespace}
+		if len(reqs) != 1 || reqs[0].NamespacedName != want {
+			t.Errorf("%T maps to %v, want %v", obj, reqs, want)
+		}
+	}
+
+	// Pointing the target at the cluster's own namespace keeps owner references
+	m.Spec.TargetNamespace = m.Namespace
+	if refs := r.deploymentForNginxCluster(m, "hash").GetOwnerReferences(); len(refs) != 1 {
+		t.Errorf("expected a controller reference in the cluster's namespace, got %v", refs)
+	}
+}
+
+func TestTargetNamespaceIsImmutable(t *testing.T) {
+	requireEnvtest(t)
+	ctx := context.Background()
+
+	// Setting the field after creation would strand the resources it made
+	m := newTestNginxCluster("target-namespace-set")
+	createTestNginxCluster(t, m)
+	patched := m.DeepCopy()
+	patched.Spec.TargetNamespace = "kube-public"
+	if err := k8sClient.Patch(ctx, patched, client.MergeFrom(m)); !apierrors.IsInvalid(err) {
+		t.Fatalf("expected setting targetNamespace to be rejected, got %v", err)
+	}
+
+	m = newTestNginxCluster("target-namespace-unset")
+	m.Spec.TargetNamespace = "kube-public"
+	createTestNginxCluster(t, m)
+	patched = m.DeepCopy()
+	patched.Spec.TargetNamespace = ""
+	if err := k8sClient.Patch(ctx, patched, client.MergeFrom(m)); !apierrors.IsInvalid(err) {
+		t.Fatalf("expected removing targetNamespace to be rejected, got %v", err)
+	}
+	patched.Spec.TargetNamespace = "default"
+	if err := k8sClient.Patch(ctx, patched, client.MergeFrom(m)); !apierrors.IsInvalid(err) {
+		t.Fatalf("expected changing targetNamespace to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// podsForNginxCluster lists the cluster's pods
+func (r *NginxClusterReconciler) podsForNginxCluster(ctx context.Context, m *nginxv1.NginxCluster) ([]corev1.Pod, error) {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(workloadNamespace(m)), client.MatchingLabels(labelsForNginxCluster(m))); err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+// podAges returns the age of the oldest and newest pod that is not being
+// deleted at now. Both are nil when there are no pods.
+func podAges(pods []corev1.Pod, now time.Time) (oldest, newest *metav1.Duration) {
+	for i := range pods {
+		if pods[i].DeletionTimestamp != nil {
+			continue
+		}
+		age := now.Sub(pods[i].CreationTimestamp.Time).Truncate(time.Second)
+		if oldest == nil || age > oldest.Duration {
+			oldest = &metav1.Duration{Duration: age}
+		}
+		if newest == nil || age < newest.Duration {
+			newest = &metav1.Duration{Duration: age}
+		}
+	}
+	return oldest, newest
+}
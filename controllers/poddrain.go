@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+const (
+	// podDrainTimeout bounds how long deletion of a NginxCluster waits for its
+	// pods to terminate, unless their termination grace period is longer
+	podDrainTimeout      = 5 * time.Minute
+	podDrainPollInterval = 5 * time.Second
+)
+
+// waitForPodsDrained scales the cluster's Deployment to zero and returns how
+// long to wait before checking again whether its pods are gone. Scaling down
+// lets every pod shut down within its termination grace period and finish
+// in-flight requests before the Deployment is garbage collected. A zero
+// duration means finalization can proceed.
+func (r *NginxClusterReconciler) waitForPodsDrained(ctx context.Context, m *nginxv1.NginxCluster) (time.Duration, error) {
+	logger := log.FromContext(ctx)
+
+	dep := &appsv1.Deployment{}
+	err := r.Get(ctx, types.NamespacedName{Name: m.Name, Namespace: workloadNamespace(m)}, dep)
+	if errors.IsNotFound(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	if !r.isOwnedBy(dep, m) {
+		return 0, nil
+	}
+
+	if dep.Spec.Replicas == nil || *dep.Spec.Replicas != 0 {
+		logger.Info("Scaling down Deployment before deletion", "Deployment.Namespace", dep.Namespace, "Deployment.Name", dep.Name)
+		zero := int32(0)
+		if err := r.updateWithRetry(ctx, dep, func() {
+			dep.Spec.Replicas = &zero
+		}); err != nil {
+			return 0, err
+		}
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(dep.Namespace), client.MatchingLabels(labelsForNginxCluster(m))); err != nil {
+		return 0, err
+	}
+	if len(pods.Items) == 0 {
+		return 0, nil
+	}
+
+	if m.DeletionTimestamp != nil && time.Since(m.DeletionTimestamp.Time) > podDrainTimeoutFor(dep) {
+		logger.Info("Timed out waiting for the pods to terminate, finalizing anyway",
+			"Deployment.Namespace", dep.Namespace, "Deployment.Name", dep.Name, "Pods", len(pods.Items))
+		return 0, nil
+	}
+	logger.Info("Waiting for the pods to terminate", "Deployment.Namespace", dep.Namespace, "Deployment.Name", dep.Name, "Pods", len(pods.Items))
+	return podDrainPollInterval, nil
+}
+
+// podDrainTimeoutFor returns how long to wait for the pods of dep to
+// terminate: podDrainTimeout, or their grace period when it is longer
+func podDrainTimeoutFor(dep *appsv1.Deployment) time.Duration {
+	timeout := podDrainTimeout
+	if grace := dep.Spec.Template.Spec.TerminationGracePeriodSeconds; grace != nil {
+		timeout = max(timeout, time.Duration(*grace)*time.Second)
+	}
+	return timeout
+}
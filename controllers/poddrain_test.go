@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+func TestPodDrainTimeoutFor(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	dep := r.deploymentForNginxCluster(newTestNginxCluster("drain-timeout"), "hash")
+	if got := podDrainTimeoutFor(dep); got != podDrainTimeout {
+		t.Fatalf("podDrainTimeoutFor() = %v, want %v", got, podDrainTimeout)
+	}
+	grace := int64(600)
+	dep.Spec.Template.Spec.TerminationGracePeriodSeconds = &grace
+	if got := podDrainTimeoutFor(dep); got != 10*time.Minute {
+		t.Fatalf("podDrainTimeoutFor() = %v, want the grace period", got)
+	}
+}
+
+func TestFinalizerWaitsForPodsToDrain(t *testing.T) {
+	requireEnvtest(t)
+	ctx := context.Background()
+
+	m := newTestNginxCluster("pod-drain")
+	createTestNginxCluster(t, m)
+	key := client.ObjectKeyFromObject(m)
+	eventually(t, func() error {
+		return k8sClient.Get(ctx, key, &appsv1.Deployment{})
+	})
+
+	// No kubelet runs in envtest, so a finalizer stands in for a pod that is
+	// still shutting down
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "pod-drain-0",
+			Namespace:  m.Namespace,
+			Labels:     labelsForNginxCluster(m),
+			Finalizers: []string{"example.com/terminating"},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "nginx", Image: "nginx"}}},
+	}
+	if err := k8sClient.Create(ctx, pod); err != nil {
+		t.Fatalf("failed to create Pod: %v", err)
+	}
+
+	if err := k8sClient.Delete(ctx, m); err != nil {
+		t.Fatalf("failed to delete NginxCluster: %v", err)
+	}
+	eventually(t, func() error {
+		dep := &appsv1.Deployment{}
+		if err := k8sClient.Get(ctx, key, dep); err != nil {
+			return err
+		}
+		if *dep.Spec.Replicas != 0 {
+			return fmt.Errorf("Deployment has %d replicas, want 0", *dep.Spec.Replicas)
+		}
+		return nil
+	})
+	if err := k8sClient.Get(ctx, key, &nginxv1.NginxCluster{}); err != nil {
+		t.Fatalf("expected the NginxCluster to wait for its pods, got %v", err)
+	}
+
+	// The pod terminates
+	eventually(t, func() error {
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(pod), pod); err != nil {
+			return err
+		}
+		controllerutil.RemoveFinalizer(pod, "example.com/terminating")
+		return k8sClient.Update(ctx, pod)
+	})
+	if err := k8sClient.Delete(ctx, pod); err != nil && !errors.IsNotFound(err) {
+		t.Fatalf("failed to delete Pod: %v", err)
+	}
+	eventually(t, func() error {
+		err := k8sClient.Get(ctx, key, &nginxv1.NginxCluster{})
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("NginxCluster still present: %v", err)
+	})
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// applyPodTemplatePatch applies spec.podTemplatePatch over template as a
+// strategic merge patch
+func applyPodTemplatePatch(template *corev1.PodTemplateSpec, m *nginxv1.NginxCluster) error {
+	if m.Spec.PodTemplatePatch == nil || len(m.Spec.PodTemplatePatch.Raw) == 0 {
+		return nil
+	}
+	original, err := json.Marshal(template)
+	if err != nil {
+		return err
+	}
+	patched, err := strategicpatch.StrategicMergePatch(original, m.Spec.PodTemplatePatch.Raw, corev1.PodTemplateSpec{})
+	if err != nil {
+		return fmt.Errorf("invalid podTemplatePatch: %w", err)
+	}
+	result := corev1.PodTemplateSpec{}
+	if err := json.Unmarshal(patched, &result); err != nil {
+		return fmt.Errorf("invalid podTemplatePatch: %w", err)
+	}
+	*template = result
+	return nil
+}
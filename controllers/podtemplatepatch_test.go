@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestDeploymentPodTemplatePatch(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+
+	m := newTestNginxCluster("pod-template-patch")
+	dep := r.deploymentForNginxCluster(m, "hash")
+
+	m.Spec.PodTemplatePatch = &runtime.RawExtension{Raw: []byte(`{
+		"metadata": {"labels": {"app": "other", "team": "edge"}},
+		"spec": {
+			"priorityClassName": "edge-critical",
+			"containers": [{"name": "nginx", "stdin": true}]
+		}
+	}`)}
+	if err := validateSpec(m); err != nil {
+		t.Fatalf("valid patch rejected: %v", err)
+	}
+	desired := r.deploymentForNginxCluster(m, "hash")
+	spec := desired.Spec.Template.Spec
+	if spec.PriorityClassName != "edge-critical" {
+		t.Errorf("expected the patched priority class, got %q", spec.PriorityClassName)
+	}
+	if len(spec.Containers) != 1 || !spec.Containers[0].Stdin || spec.Containers[0].Image != m.Spec.Image {
+		t.Errorf("expected the nginx container to be merged, got %+v", spec.Containers)
+	}
+	if labels := desired.Spec.Template.Labels; labels["team"] != "edge" || labels["app"] != "nginx" {
+		t.Errorf("expected extra labels to be added and selector labels kept, got %v", labels)
+	}
+	if !syncDeploymentSpec(dep, desired) {
+		t.Fatalf("expected a pod template patch to roll the Deployment")
+	}
+}
+
+func TestValidatePodTemplatePatchRejectsMalformedPatch(t *testing.T) {
+	m := newTestNginxCluster("bad-pod-template-patch")
+	for _, raw := range []string{
+		`{"spec": {"containers": "nginx"}}`,
+		`{"spec": {"terminationGracePeriodSeconds": "soon"}}`,
+	} {
+		m.Spec.PodTemplatePatch = &runtime.RawExtension{Raw: []byte(raw)}
+		if err := validateSpec(m); err == nil {
+			t.Errorf("expected patch %s to be rejected", raw)
+		}
+	}
+}
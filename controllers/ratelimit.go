@@ -0,0 +1,48 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strconv"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// rateLimitZone is the limit_req_zone spec.rateLimit adds to the default
+// config
+const rateLimitZone = "nginx_operator_rate_limit"
+
+// withRateLimit adds the limit_req_zone of spec.rateLimit to the http block
+// of a default config, and limits its server to the zone
+func withRateLimit(m *nginxv1.NginxCluster, conf string) string {
+	rl := m.Spec.RateLimit
+	if rl == nil {
+		return conf
+	}
+	zoneSize := rl.ZoneSize
+	if zoneSize == "" {
+		zoneSize = "10m"
+	}
+	limit := "limit_req zone=" + rateLimitZone
+	if rl.Burst > 0 {
+		limit += " burst=" + strconv.Itoa(int(rl.Burst))
+	}
+	conf = injectHTTPDirectives(conf, []string{
+		"limit_req_zone $binary_remote_addr zone=" + rateLimitZone + ":" + zoneSize + " rate=" + rl.Rate + ";",
+	})
+	return injectServerDirectives(conf, []string{limit + ";", "limit_req_status 429;"})
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+	"testing"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+func TestEffectiveNginxConfRateLimit(t *testing.T) {
+	m := newTestNginxCluster("rate-limit")
+	m.Spec.NginxConf = ""
+	m.Spec.RateLimit = &nginxv1.RateLimitSpec{Rate: "10r/s", Burst: 20}
+	conf := effectiveNginxConf(m)
+	if !strings.Contains(conf, "http {\n    limit_req_zone $binary_remote_addr zone=nginx_operator_rate_limit:10m rate=10r/s;\n") {
+		t.Fatalf("limit_req_zone missing from the http block:\n%s", conf)
+	}
+	if !strings.Contains(conf, "        limit_req zone=nginx_operator_rate_limit burst=20;\n        limit_req_status 429;\n") {
+		t.Fatalf("limit_req missing from the server block:\n%s", conf)
+	}
+	m.Spec.RateLimit.Rate = "5r/s"
+	if calculateConfigHash(effectiveNginxConf(m)) == calculateConfigHash(conf) {
+		t.Fatalf("rate limit not part of the config hash")
+	}
+
+	// The ReverseProxy default config is limited too
+	m.Spec.DefaultConfigMode = "ReverseProxy"
+	m.Spec.Upstreams = []string{"backend:8080"}
+	if conf := effectiveNginxConf(m); !strings.Contains(conf, "        limit_req zone=nginx_operator_rate_limit burst=20;\n") {
+		t.Fatalf("limit_req missing from the ReverseProxy config:\n%s", conf)
+	}
+
+	// Only the default configs are changed
+	m.Spec.NginxConf = "events {}\nhttp {\n    server {\n    }\n}\n"
+	if conf := effectiveNginxConf(m); strings.Contains(conf, "limit_req") {
+		t.Fatalf("rate limit added to the spec config:\n%s", conf)
+	}
+}
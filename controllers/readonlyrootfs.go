@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"regexp"
+
+	corev1 "k8s.io/api/core/v1"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// The emptyDirs nginx writes to with spec.readOnlyRootFilesystem
+var writableDirs = []struct{ name, path string }{
+	{"nginx-tmp", "/tmp"},
+	{"nginx-run", "/var/run"},
+}
+
+// pidPattern matches a pid directive
+var pidPattern = regexp.MustCompile(`(?m)^[ \t]*pid\s`)
+
+// tempPathModules are the modules whose <module>_temp_path directive is
+// pointed into /tmp
+var tempPathModules = []string{"client_body", "proxy", "fastcgi", "uwsgi", "scgi"}
+
+// tempPathPattern matches a temp path directive, capturing its module
+var tempPathPattern = regexp.MustCompile(`(?m)^[ \t]*(client_body|proxy|fastcgi|uwsgi|scgi)_temp_path\s`)
+
+// withWritablePaths points the pid file and the temp paths of conf, unless it
+// sets them itself, at the writable emptyDirs of a read-only root filesystem.
+// The temp paths are only added when conf has an http block.
+func withWritablePaths(conf string) string {
+	if !pidPattern.MatchString(conf) {
+		// pid is only valid in the main context
+		conf = "pid " + defaultPIDFile + ";\n" + conf
+	}
+	set := map[string]bool{}
+	for _, match := range tempPathPattern.FindAllStringSubmatch(conf, -1) {
+		set[match[1]] = true
+	}
+	var missing []string
+	for _, module := range tempPathModules {
+		if !set[module] {
+			missing = append(missing, module+"_temp_path /tmp/"+module+"_temp;")
+		}
+	}
+	return injectHTTPDirectives(conf, missing)
+}
+
+// addWritableDirs mounts the emptyDirs nginx writes to when the root
+// filesystem of its container is read-only
+func addWritableDirs(podSpec *corev1.PodSpec, m *nginxv1.NginxCluster) {
+	if !m.Spec.ReadOnlyRootFilesystem {
+		return
+	}
+	for _, dir := range writableDirs {
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name:         dir.name,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		})
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      dir.name,
+			MountPath: dir.path,
+		})
+	}
+}
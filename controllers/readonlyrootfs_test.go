@@ -0,0 +1,75 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDeploymentReadOnlyRootFilesystem(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("read-only-rootfs")
+	if sc := r.deploymentForNginxCluster(m, "hash").Spec.Template.Spec.Containers[0].SecurityContext; sc != nil {
+		t.Fatalf("expected no security context by default, got %+v", sc)
+	}
+
+	m.Spec.ReadOnlyRootFilesystem = true
+	spec := r.deploymentForNginxCluster(m, "hash").Spec.Template.Spec
+	nginx := spec.Containers[0]
+	if sc := nginx.SecurityContext; sc == nil || sc.ReadOnlyRootFilesystem == nil || !*sc.ReadOnlyRootFilesystem {
+		t.Fatalf("expected a read-only root filesystem, got %+v", sc)
+	}
+	mounts := map[string]string{}
+	for _, mount := range nginx.VolumeMounts {
+		mounts[mount.MountPath] = mount.Name
+	}
+	for _, path := range []string{"/tmp", "/var/run"} {
+		if mounts[path] == "" {
+			t.Fatalf("%s not mounted: %+v", path, nginx.VolumeMounts)
+		}
+	}
+	if len(spec.Volumes) != 3 || spec.Volumes[1].EmptyDir == nil || spec.Volumes[2].EmptyDir == nil {
+		t.Fatalf("expected two emptyDirs next to the config, got %+v", spec.Volumes)
+	}
+}
+
+func TestEffectiveNginxConfReadOnlyRootFilesystem(t *testing.T) {
+	m := newTestNginxCluster("read-only-rootfs-conf")
+	m.Spec.ReadOnlyRootFilesystem = true
+	m.Spec.NginxConf = "events {}\nhttp {\n    proxy_temp_path /var/cache/nginx/proxy;\n}\n"
+	want := "pid /var/run/nginx.pid;\nevents {}\nhttp {\n" +
+		"    client_body_temp_path /tmp/client_body_temp;\n" +
+		"    fastcgi_temp_path /tmp/fastcgi_temp;\n" +
+		"    uwsgi_temp_path /tmp/uwsgi_temp;\n" +
+		"    scgi_temp_path /tmp/scgi_temp;\n" +
+		"    proxy_temp_path /var/cache/nginx/proxy;\n}\n"
+	if got := effectiveNginxConf(m); got != want {
+		t.Fatalf("unexpected effective config:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Directives already in the config win
+	m.Spec.NginxConf = want
+	if got := effectiveNginxConf(m); got != want {
+		t.Fatalf("writable paths added twice:\n%s", got)
+	}
+
+	m.Spec.NginxConf = ""
+	if got := effectiveNginxConf(m); !strings.HasPrefix(got, "pid /var/run/nginx.pid;\n") || !strings.Contains(got, "client_body_temp_path /tmp/client_body_temp;") {
+		t.Fatalf("writable paths missing from the default config:\n%s", got)
+	}
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// ReconcileSummary describes one reconcile of a NginxCluster
+type ReconcileSummary struct {
+	Time time.Time `json:"time"`
+	// Result is Success, Requeue or Error
+	Result       string `json:"result"`
+	RequeueAfter string `json:"requeueAfter,omitempty"`
+	Error        string `json:"error,omitempty"`
+	// ConfigHash is the hash the pod template was reconciled with, empty when
+	// the reconcile didn't get that far
+	ConfigHash string `json:"configHash,omitempty"`
+	// Actions are the events recorded on the NginxCluster during the reconcile
+	Actions []ReconcileAction `json:"actions,omitempty"`
+}
+
+// ReconcileAction is an event recorded during a reconcile
+type ReconcileAction struct {
+	Type    string `json:"type"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// reconcileHistory keeps the last summaries of every NginxCluster in memory,
+// in a ring buffer per cluster. It is lost when the operator restarts.
+type reconcileHistory struct {
+	mu    sync.Mutex
+	rings map[types.NamespacedName]*summaryRing
+	// actions collects the events of the reconciles in flight, of which there
+	// is at most one per cluster
+	actions map[types.NamespacedName][]ReconcileAction
+	// gone holds the clusters found deleted by the reconcile in flight
+	gone map[types.NamespacedName]struct{}
+}
+
+type summaryRing struct {
+	items []ReconcileSummary
+	next  int
+}
+
+// add stores s, overwriting the oldest summary once size are kept
+func (b *summaryRing) add(s ReconcileSummary, size int) {
+	if len(b.items) < size {
+		b.items = append(b.items, s)
+		return
+	}
+	b.items[b.next] = s
+	b.next = (b.next + 1) % len(b.items)
+}
+
+// list returns the summaries, oldest first
+func (b *summaryRing) list() []ReconcileSummary {
+	return append(append([]ReconcileSummary{}, b.items[b.next:]...), b.items[:b.next]...)
+}
+
+// recordAction adds an event to the reconcile of key in flight
+func (h *reconcileHistory) recordAction(key types.NamespacedName, action ReconcileAction) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.actions == nil {
+		h.actions = map[types.NamespacedName][]ReconcileAction{}
+	}
+	h.actions[key] = append(h.actions[key], action)
+}
+
+// forget drops the history of key once its reconcile in flight ends, as the
+// NginxCluster is gone
+func (h *reconcileHistory) forget(key types.NamespacedName) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.gone == nil {
+		h.gone = map[types.NamespacedName]struct{}{}
+	}
+	h.gone[key] = struct{}{}
+}
+
+// record ends the reconcile of key in flight, keeping its summary among the
+// last size ones, and returns the summary with its actions
+func (h *reconcileHistory) record(key types.NamespacedName, size int, s ReconcileSummary) ReconcileSummary {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s.Actions = h.actions[key]
+	delete(h.actions, key)
+	if _, ok := h.gone[key]; ok {
+		delete(h.gone, key)
+		delete(h.rings, key)
+		return s
+	}
+	if size <= 0 {
+		return s
+	}
+	if h.rings == nil {
+		h.rings = map[types.NamespacedName]*summaryRing{}
+	}
+	ring := h.rings[key]
+	if ring == nil {
+		ring = &summaryRing{}
+		h.rings[key] = ring
+	}
+	ring.add(s, size)
+	return s
+}
+
+// summaries returns the history of key, oldest first
+func (h *reconcileHistory) summaries(key types.NamespacedName) ([]ReconcileSummary, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ring, ok := h.rings[key]
+	if !ok {
+		return nil, false
+	}
+	return ring.list(), true
+}
+
+// all returns the history of every cluster keyed by namespace/name
+func (h *reconcileHistory) all() map[string][]ReconcileSummary {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	out := make(map[string][]ReconcileSummary, len(h.rings))
+	for key, ring := range h.rings {
+		out[key.String()] = ring.list()
+	}
+	return out
+}
+
+// reconcileRun collects what a reconcile in flight reports in its summary
+type reconcileRun struct {
+	// configHash is set once the config hash is calculated
+	configHash string
+}
+
+// reconcileSummary summarizes the outcome of a reconcile
+func reconcileSummary(now time.Time, configHash string, result ctrl.Result, err error) ReconcileSummary {
+	s := ReconcileSummary{Time: now.UTC(), Result: "Success", ConfigHash: configHash}
+	switch {
+	case err != nil:
+		s.Result = "Error"
+		s.Error = err.Error()
+	case result.RequeueAfter > 0:
+		s.Result = "Requeue"
+		s.RequeueAfter = result.RequeueAfter.String()
+	case result.Requeue:
+		s.Result = "Requeue"
+	}
+	return s
+}
+
+// recordsReconciles reports whether reconcile summaries are kept or sent
+func (r *NginxClusterReconciler) recordsReconciles() bool {
+	return r.ReconcileHistorySize > 0 || r.ReconcileSink != nil
+}
+
+// recordReconcile adds the summary of the reconcile of key that just ended
+// to the history, when it is kept, and sends it to the ReconcileSink
+func (r *NginxClusterReconciler) recordReconcile(key types.NamespacedName, run *reconcileRun, result ctrl.Result, err error) {
+	if !r.recordsReconciles() {
+		return
+	}
+	s := r.history.record(key, r.ReconcileHistorySize, reconcileSummary(time.Now(), run.configHash, result, err))
+	if r.ReconcileSink != nil {
+		r.ReconcileSink.Send(key, s)
+	}
+}
+
+// ReconcileHistoryHandler serves the recent reconciles as JSON: those of
+// every cluster keyed by namespace/name, or those of the cluster given by
+// the namespace and name query parameters, oldest first
+func (r *NginxClusterReconciler) ReconcileHistoryHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var body any
+		query := req.URL.Query()
+		if name := query.Get("name"); name != "" {
+			summaries, ok := r.history.summaries(types.NamespacedName{Namespace: query.Get("namespace"), Name: name})
+			if !ok {
+				http.Error(w, "no reconciles recorded for this NginxCluster", http.StatusNotFound)
+				return
+			}
+			body = summaries
+		} else {
+			body = r.history.all()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(body)
+	})
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestReconcileHistoryKeepsLastSummaries(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme, ReconcileHistorySize: 2}
+	m := newTestNginxCluster("history")
+	key := types.NamespacedName{Namespace: m.Namespace, Name: m.Name}
+	run := &reconcileRun{configHash: "hash"}
+
+	r.recordReconcile(key, run, ctrl.Result{}, nil)
+	r.recordEvent(m, corev1.EventTypeNormal, "ConfigChanged", "nginx.conf changed")
+	r.recordReconcile(key, run, ctrl.Result{RequeueAfter: time.Minute}, nil)
+	r.recordReconcile(key, run, ctrl.Result{}, errors.New("boom"))
+
+	summaries, ok := r.history.summaries(key)
+	if !ok || len(summaries) != 2 {
+		t.Fatalf("expected the last 2 summaries, got %+v", summaries)
+	}
+	if s := summaries[0]; s.Result != "Requeue" || s.RequeueAfter != "1m0s" || s.ConfigHash != "hash" || len(s.Actions) != 1 || s.Actions[0].Reason != "ConfigChanged" {
+		t.Fatalf("unexpected first summary %+v", s)
+	}
+	if s := summaries[1]; s.Result != "Error" || s.Error != "boom" || len(s.Actions) != 0 {
+		t.Fatalf("unexpected last summary %+v", s)
+	}
+
+	// The history of a deleted cluster is dropped
+	r.history.forget(key)
+	r.recordReconcile(key, run, ctrl.Result{}, nil)
+	if _, ok := r.history.summaries(key); ok {
+		t.Fatalf("history kept for a deleted cluster")
+	}
+}
+
+func TestReconcileHistoryDisabled(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("no-history")
+	key := types.NamespacedName{Namespace: m.Namespace, Name: m.Name}
+	r.recordEvent(m, corev1.EventTypeNormal, "ConfigChanged", "nginx.conf changed")
+	r.recordReconcile(key, &reconcileRun{}, ctrl.Result{}, nil)
+	if _, ok := r.history.summaries(key); ok || len(r.history.actions) != 0 {
+		t.Fatalf("history kept with ReconcileHistorySize 0")
+	}
+}
+
+func TestReconcileHistoryHandler(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme, ReconcileHistorySize: 5}
+	m := newTestNginxCluster("history-api")
+	key := types.NamespacedName{Namespace: m.Namespace, Name: m.Name}
+	r.recordReconcile(key, &reconcileRun{configHash: "hash"}, ctrl.Result{}, nil)
+	handler := r.ReconcileHistoryHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/reconciles", nil))
+	all := map[string][]ReconcileSummary{}
+	if err := json.NewDecoder(rec.Body).Decode(&all); err != nil {
+		t.Fatalf("failed to decode the response: %v", err)
+	}
+	if got := all[key.String()]; len(got) != 1 || got[0].ConfigHash != "hash" {
+		t.Fatalf("unexpected history %+v", all)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/reconciles?namespace="+m.Namespace+"&name="+m.Name, nil))
+	var one []ReconcileSummary
+	if err := json.NewDecoder(rec.Body).Decode(&one); err != nil || len(one) != 1 || one[0].Result != "Success" {
+		t.Fatalf("unexpected history of %s: %+v, %v", key, one, err)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/reconciles?namespace="+m.Namespace+"&name=other", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown cluster, got %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/reconciles", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 for POST, got %d", rec.Code)
+	}
+}
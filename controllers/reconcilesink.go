@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	// reconcileSinkQueueSize is the number of summaries the HTTP sink buffers
+	// while a post is in flight. Further summaries are dropped.
+	reconcileSinkQueueSize = 256
+	// reconcileSinkTimeout bounds each post of the HTTP sink
+	reconcileSinkTimeout = 5 * time.Second
+)
+
+// ReconcileSink receives the summary of every reconcile. Send is called from
+// the reconcile itself, so it must return right away.
+type ReconcileSink interface {
+	Send(cluster types.NamespacedName, summary ReconcileSummary)
+}
+
+// ReconcileSinkEvent is the JSON body the HTTP sink posts
+type ReconcileSinkEvent struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	ReconcileSummary
+}
+
+// HTTPReconcileSink posts each summary as a ReconcileSinkEvent to a URL. The
+// posts are sent one at a time by Start; summaries sent while the queue is
+// full are dropped, so a slow endpoint never stalls reconciles.
+type HTTPReconcileSink struct {
+	url    string
+	doer   httpDoer
+	events chan ReconcileSinkEvent
+}
+
+// NewHTTPReconcileSink returns a sink posting to url. It must be added to the
+// manager, which runs Start.
+func NewHTTPReconcileSink(url string) *HTTPReconcileSink {
+	return &HTTPReconcileSink{url: url, doer: http.DefaultClient, events: make(chan ReconcileSinkEvent, reconcileSinkQueueSize)}
+}
+
+// Send queues the summary of the reconcile of cluster
+func (s *HTTPReconcileSink) Send(cluster types.NamespacedName, summary ReconcileSummary) {
+	select {
+	case s.events <- ReconcileSinkEvent{Namespace: cluster.Namespace, Name: cluster.Name, ReconcileSummary: summary}:
+	default:
+		ctrl.Log.WithName("reconcile-sink").Info("Queue full, dropping reconcile summary", "nginxCluster", cluster)
+	}
+}
+
+// Start posts the queued summaries until ctx is done
+func (s *HTTPReconcileSink) Start(ctx context.Context) error {
+	logger := ctrl.Log.WithName("reconcile-sink")
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-s.events:
+			if err := s.post(ctx, event); err != nil {
+				logger.Error(err, "Failed to post reconcile summary", "nginxCluster", types.NamespacedName{Namespace: event.Namespace, Name: event.Name})
+			}
+		}
+	}
+}
+
+// post sends event to the URL of the sink
+func (s *HTTPReconcileSink) post(ctx context.Context, event ReconcileSinkEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, reconcileSinkTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.doer.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("POST %s returned %d", s.url, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// recordingSink keeps the summaries it is sent
+type recordingSink []ReconcileSummary
+
+func (s *recordingSink) Send(_ types.NamespacedName, summary ReconcileSummary) {
+	*s = append(*s, summary)
+}
+
+func TestReconcileSinkReceivesSummaries(t *testing.T) {
+	sink := &recordingSink{}
+	r := &NginxClusterReconciler{Scheme: testScheme, ReconcileSink: sink}
+	m := newTestNginxCluster("sink")
+	key := types.NamespacedName{Namespace: m.Namespace, Name: m.Name}
+
+	r.recordEvent(m, corev1.EventTypeNormal, "ConfigChanged", "nginx.conf changed")
+	r.recordReconcile(key, &reconcileRun{configHash: "hash"}, ctrl.Result{}, nil)
+	if len(*sink) != 1 || (*sink)[0].ConfigHash != "hash" || len((*sink)[0].Actions) != 1 {
+		t.Fatalf("unexpected summaries %+v", *sink)
+	}
+	// Without ReconcileHistorySize, nothing is kept in memory
+	if _, ok := r.history.summaries(key); ok || len(r.history.actions) != 0 {
+		t.Fatalf("history kept with ReconcileHistorySize 0")
+	}
+}
+
+func TestHTTPReconcileSink(t *testing.T) {
+	received := make(chan ReconcileSinkEvent, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var event ReconcileSinkEvent
+		if req.Method != http.MethodPost || json.NewDecoder(req.Body).Decode(&event) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- event
+	}))
+	defer server.Close()
+
+	sink := NewHTTPReconcileSink(server.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = sink.Start(ctx) }()
+
+	key := types.NamespacedName{Namespace: "default", Name: "sink"}
+	sink.Send(key, ReconcileSummary{Result: "Error", Error: "boom", Actions: []ReconcileAction{{Type: corev1.EventTypeWarning, Reason: "QuotaExceeded"}}})
+	select {
+	case event := <-received:
+		if event.Namespace != "default" || event.Name != "sink" || event.Result != "Error" || event.Error != "boom" || len(event.Actions) != 1 {
+			t.Fatalf("unexpected event %+v", event)
+		}
+	case <-time.After(testTimeout):
+		t.Fatalf("no summary posted")
+	}
+}
+
+func TestHTTPReconcileSinkDropsWhenFull(t *testing.T) {
+	// Nothing drains the queue without Start
+	sink := NewHTTPReconcileSink("http://127.0.0.1:0")
+	key := types.NamespacedName{Namespace: "default", Name: "sink"}
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i <= reconcileSinkQueueSize; i++ {
+			sink.Send(key, ReconcileSummary{Result: "Success"})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(testTimeout):
+		t.Fatalf("Send blocked on a full queue")
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// resourceProfiles are the requests and limits of spec.resourceProfile, as
+// CPU, memory, CPU limit and memory limit
+var resourceProfiles = map[string][4]string{
+	"small":  {"100m", "128Mi", "500m", "256Mi"},
+	"medium": {"250m", "256Mi", "1", "512Mi"},
+	"large":  {"1", "1Gi", "2", "2Gi"},
+}
+
+// resourcesForNginxCluster returns the resources of the nginx container:
+// spec.resources when set, or those of spec.resourceProfile
+func resourcesForNginxCluster(m *nginxv1.NginxCluster) corev1.ResourceRequirements {
+	if m.Spec.Resources != nil {
+		return *m.Spec.Resources.DeepCopy()
+	}
+	profile, ok := resourceProfiles[m.Spec.ResourceProfile]
+	if !ok {
+		return corev1.ResourceRequirements{}
+	}
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(profile[0]),
+			corev1.ResourceMemory: resource.MustParse(profile[1]),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(profile[2]),
+			corev1.ResourceMemory: resource.MustParse(profile[3]),
+		},
+	}
+}
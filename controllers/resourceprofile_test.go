@@ -0,0 +1,50 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestDeploymentResourceProfile(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("resource-profile")
+	if res := r.deploymentForNginxCluster(m, "hash").Spec.Template.Spec.Containers[0].Resources; len(res.Requests) != 0 || len(res.Limits) != 0 {
+		t.Fatalf("expected no resources without a profile, got %+v", res)
+	}
+
+	m.Spec.ResourceProfile = "medium"
+	res := r.deploymentForNginxCluster(m, "hash").Spec.Template.Spec.Containers[0].Resources
+	if cpu := res.Requests[corev1.ResourceCPU]; cpu.String() != "250m" {
+		t.Fatalf("CPU request = %s, want 250m", cpu.String())
+	}
+	if mem := res.Limits[corev1.ResourceMemory]; mem.String() != "512Mi" {
+		t.Fatalf("memory limit = %s, want 512Mi", mem.String())
+	}
+
+	// Explicit resources replace the profile
+	m.Spec.Resources = &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50m")},
+	}
+	res = r.deploymentForNginxCluster(m, "hash").Spec.Template.Spec.Containers[0].Resources
+	if cpu := res.Requests[corev1.ResourceCPU]; cpu.String() != "50m" || len(res.Limits) != 0 {
+		t.Fatalf("expected spec.resources, got %+v", res)
+	}
+}
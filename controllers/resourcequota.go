@@ -0,0 +1,142 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// resourceQuotaViolation checks whether the replicas still to be created for
+// m fit into the ResourceQuotas of the workload namespace, and returns a
+// message describing the first quota they would exceed. The check is best
+// effort: scoped quotas are skipped, and pods being replaced by a rollout are
+// not accounted for.
+func (r *NginxClusterReconciler) resourceQuotaViolation(ctx context.Context, m *nginxv1.NginxCluster, dep *appsv1.Deployment, template *corev1.PodTemplateSpec) (string, error) {
+	missing := int64(workloadReplicas(m)) - int64(dep.Status.Replicas)
+	if missing <= 0 {
+		return "", nil
+	}
+	quotas := &corev1.ResourceQuotaList{}
+	if err := r.List(ctx, quotas, client.InNamespace(workloadNamespace(m))); err != nil {
+		return "", err
+	}
+	perPod := podQuotaUsage(&template.Spec)
+	for i := range quotas.Items {
+		if msg := quotaViolation(&quotas.Items[i], perPod, missing); msg != "" {
+			return msg, nil
+		}
+	}
+	return "", nil
+}
+
+// quotaViolation describes how adding pods with the given usage would exceed
+// quota, or returns "" if they fit
+func quotaViolation(quota *corev1.ResourceQuota, perPod corev1.ResourceList, pods int64) string {
+	if len(quota.Spec.Scopes) > 0 || quota.Spec.ScopeSelector != nil {
+		return ""
+	}
+	names := make([]string, 0, len(quota.Status.Hard))
+	for name := range quota.Status.Hard {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		usage, ok := perPod[corev1.ResourceName(name)]
+		if !ok {
+			continue
+		}
+		needed := resource.NewMilliQuantity(usage.MilliValue()*pods, usage.Format)
+		left := quota.Status.Hard[corev1.ResourceName(name)].DeepCopy()
+		left.Sub(quota.Status.Used[corev1.ResourceName(name)])
+		if needed.Cmp(left) > 0 {
+			return fmt.Sprintf("%d more replicas need %s %s but ResourceQuota %s has %s left", pods, needed.String(), name, quota.Name, left.String())
+		}
+	}
+	return ""
+}
+
+// podQuotaUsage returns what a single pod counts against the quota resources
+// the check supports. As for scheduling, a pod needs the larger of the sum of
+// its containers and its largest init container.
+func podQuotaUsage(spec *corev1.PodSpec) corev1.ResourceList {
+	usage := corev1.ResourceList{
+		corev1.ResourcePods:               resource.MustParse("1"),
+		corev1.ResourceName("count/pods"): resource.MustParse("1"),
+		corev1.ResourceRequestsCPU:        resource.Quantity{},
+		corev1.ResourceRequestsMemory:     resource.Quantity{},
+		corev1.ResourceLimitsCPU:          resource.Quantity{},
+		corev1.ResourceLimitsMemory:       resource.Quantity{},
+	}
+	add := func(name corev1.ResourceName, q resource.Quantity) {
+		sum := usage[name]
+		sum.Add(q)
+		usage[name] = sum
+	}
+	for _, c := range spec.Containers {
+		add(corev1.ResourceRequestsCPU, c.Resources.Requests[corev1.ResourceCPU])
+		add(corev1.ResourceRequestsMemory, c.Resources.Requests[corev1.ResourceMemory])
+		add(corev1.ResourceLimitsCPU, c.Resources.Limits[corev1.ResourceCPU])
+		add(corev1.ResourceLimitsMemory, c.Resources.Limits[corev1.ResourceMemory])
+	}
+	for _, c := range spec.InitContainers {
+		for name, q := range map[corev1.ResourceName]resource.Quantity{
+			corev1.ResourceRequestsCPU:    c.Resources.Requests[corev1.ResourceCPU],
+			corev1.ResourceRequestsMemory: c.Resources.Requests[corev1.ResourceMemory],
+			corev1.ResourceLimitsCPU:      c.Resources.Limits[corev1.ResourceCPU],
+			corev1.ResourceLimitsMemory:   c.Resources.Limits[corev1.ResourceMemory],
+		} {
+			if q.Cmp(usage[name]) > 0 {
+				usage[name] = q
+			}
+		}
+	}
+	// The plain names are aliases for the requests
+	usage[corev1.ResourceCPU] = usage[corev1.ResourceRequestsCPU]
+	usage[corev1.ResourceMemory] = usage[corev1.ResourceRequestsMemory]
+	return usage
+}
+
+// degradedCondition reports whether the cluster is blocked from reaching its
+// desired state, given the quota violation found, if any
+func degradedCondition(m *nginxv1.NginxCluster, quotaMessage string) metav1.Condition {
+	if quotaMessage != "" {
+		return metav1.Condition{
+			Type:               nginxv1.ConditionDegraded,
+			Status:             metav1.ConditionTrue,
+			Reason:             "QuotaExceeded",
+			Message:            quotaMessage,
+			ObservedGeneration: m.Generation,
+		}
+	}
+	return metav1.Condition{
+		Type:               nginxv1.ConditionDegraded,
+		Status:             metav1.ConditionFalse,
+		Reason:             "WithinQuota",
+		Message:            "The requested replicas fit into the namespace quotas",
+		ObservedGeneration: m.Generation,
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testResourceQuota(hard, used corev1.ResourceList) *corev1.ResourceQuota {
+	return &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "compute"},
+		Status:     corev1.ResourceQuotaStatus{Hard: hard, Used: used},
+	}
+}
+
+func TestPodQuotaUsage(t *testing.T) {
+	spec := &corev1.PodSpec{
+		Containers: []corev1.Container{{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m"), corev1.ResourceMemory: resource.MustParse("128Mi")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+			},
+		}, {
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+			},
+		}},
+		InitContainers: []corev1.Container{{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			},
+		}},
+	}
+	usage := podQuotaUsage(spec)
+	for name, want := range map[corev1.ResourceName]string{
+		corev1.ResourcePods:           "1",
+		corev1.ResourceRequestsCPU:    "1",
+		corev1.ResourceCPU:            "1",
+		corev1.ResourceRequestsMemory: "128Mi",
+		corev1.ResourceLimitsMemory:   "256Mi",
+	} {
+		if got := usage[name]; got.Cmp(resource.MustParse(want)) != 0 {
+			t.Errorf("%s = %s, want %s", name, got.String(), want)
+		}
+	}
+}
+
+func TestQuotaViolation(t *testing.T) {
+	perPod := podQuotaUsage(&corev1.PodSpec{Containers: []corev1.Container{{
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+		},
+	}}})
+	quota := testResourceQuota(
+		corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("2"), corev1.ResourcePods: resource.MustParse("10")},
+		corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("1"), corev1.ResourcePods: resource.MustParse("2")},
+	)
+	if msg := quotaViolation(quota, perPod, 2); msg != "" {
+		t.Fatalf("unexpected violation for replicas that fit: %s", msg)
+	}
+	want := "3 more replicas need 1500m requests.cpu but ResourceQuota compute has 1 left"
+	if msg := quotaViolation(quota, perPod, 3); msg != want {
+		t.Fatalf("quotaViolation() = %q, want %q", msg, want)
+	}
+
+	// Scoped quotas only apply to some pods and are skipped
+	quota.Spec.Scopes = []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeBestEffort}
+	if msg := quotaViolation(quota, perPod, 3); msg != "" {
+		t.Fatalf("unexpected violation for a scoped quota: %s", msg)
+	}
+}
+
+func TestDegradedCondition(t *testing.T) {
+	m := newTestNginxCluster("degraded")
+	if cond := degradedCondition(m, ""); cond.Status != metav1.ConditionFalse {
+		t.Errorf("expected Degraded=False, got %+v", cond)
+	}
+	if cond := degradedCondition(m, "no room"); cond.Status != metav1.ConditionTrue || cond.Reason != "QuotaExceeded" || cond.Message != "no room" {
+		t.Errorf("expected Degraded=True, got %+v", cond)
+	}
+}
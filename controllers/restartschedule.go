@@ -0,0 +1,56 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+	"github.com/example/nginx-operator/internal/cron"
+)
+
+// nextScheduledRestart returns when the next scheduled restart of m is due,
+// counted from the last one. The zero time means no restart is scheduled.
+func nextScheduledRestart(m *nginxv1.NginxCluster) time.Time {
+	if m.Spec.RestartSchedule == "" || m.Status.LastScheduledRestartTime == nil {
+		return time.Time{}
+	}
+	schedule, err := cron.Parse(m.Spec.RestartSchedule)
+	if err != nil {
+		return time.Time{}
+	}
+	return schedule.Next(m.Status.LastScheduledRestartTime.Time)
+}
+
+// scheduledRestartDue reports whether a scheduled restart of m is due at now
+func scheduledRestartDue(m *nginxv1.NginxCluster, now time.Time) bool {
+	next := nextScheduledRestart(m)
+	return !next.IsZero() && !now.Before(next)
+}
+
+// requeueForRestartSchedule returns how long to wait for the next scheduled
+// restart after now, or zero when none is scheduled.
+func requeueForRestartSchedule(m *nginxv1.NginxCluster, now time.Time) time.Duration {
+	next := nextScheduledRestart(m)
+	if next.IsZero() {
+		return 0
+	}
+	if wait := next.Sub(now); wait > 0 {
+		return wait
+	}
+	return time.Second
+}
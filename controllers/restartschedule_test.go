@@ -0,0 +1,73 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateRestartSchedule(t *testing.T) {
+	m := newTestNginxCluster("restart-validate")
+	if err := validateSpec(m); err != nil {
+		t.Fatalf("empty schedule rejected: %v", err)
+	}
+	m.Spec.RestartSchedule = "0 3 * * *"
+	if err := validateSpec(m); err != nil {
+		t.Fatalf("valid schedule rejected: %v", err)
+	}
+	m.Spec.RestartSchedule = "nightly"
+	if err := validateSpec(m); err == nil {
+		t.Fatalf("expected an invalid schedule to be rejected")
+	}
+}
+
+func TestScheduledRestart(t *testing.T) {
+	m := newTestNginxCluster("restart-schedule")
+	m.Spec.RestartSchedule = "0 3 * * *"
+	now := time.Date(2025, time.March, 14, 2, 30, 0, 0, time.UTC)
+
+	// Nothing is due until the schedule has been observed
+	if scheduledRestartDue(m, now) || requeueForRestartSchedule(m, now) != 0 {
+		t.Fatalf("expected no restart before the schedule is observed")
+	}
+
+	last := metav1.NewTime(time.Date(2025, time.March, 13, 3, 0, 0, 0, time.UTC))
+	m.Status.LastScheduledRestartTime = &last
+	if scheduledRestartDue(m, now) {
+		t.Fatalf("restart due before 03:00")
+	}
+	if got := requeueForRestartSchedule(m, now); got != 30*time.Minute {
+		t.Fatalf("requeue after %v, want 30m", got)
+	}
+
+	// Missed runs are caught up once
+	later := now.Add(48 * time.Hour)
+	if !scheduledRestartDue(m, later) {
+		t.Fatalf("expected the missed restart to be due")
+	}
+	if got := requeueForRestartSchedule(m, later); got != time.Second {
+		t.Fatalf("requeue after %v for an overdue restart, want 1s", got)
+	}
+
+	m.Spec.RestartSchedule = ""
+	if scheduledRestartDue(m, later) || requeueForRestartSchedule(m, later) != 0 {
+		t.Fatalf("expected no restart without a schedule")
+	}
+}
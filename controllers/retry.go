@@ -0,0 +1,53 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// updateWithRetry applies mutate to obj and updates it. On a conflict, obj is
+// re-fetched and mutate applied again, so mutate must be idempotent.
+func (r *NginxClusterReconciler) updateWithRetry(ctx context.Context, obj client.Object, mutate func()) error {
+	return r.retryOnConflict(ctx, obj, mutate, func() error {
+		return r.Update(ctx, obj)
+	})
+}
+
+// updateStatusWithRetry is like updateWithRetry for the status subresource
+func (r *NginxClusterReconciler) updateStatusWithRetry(ctx context.Context, obj client.Object, mutate func()) error {
+	return r.retryOnConflict(ctx, obj, mutate, func() error {
+		return r.Status().Update(ctx, obj)
+	})
+}
+
+func (r *NginxClusterReconciler) retryOnConflict(ctx context.Context, obj client.Object, mutate func(), update func() error) error {
+	refetch := false
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if refetch {
+			if err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+				return err
+			}
+		}
+		refetch = true
+		mutate()
+		return update()
+	})
+}
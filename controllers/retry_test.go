@@ -0,0 +1,131 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// conflictingClient serves a single NginxCluster and rejects the first
+// conflicts writes with a Conflict error, as the API server does when the
+// object was modified since it was read.
+type conflictingClient struct {
+	client.Client
+	stored    *nginxv1.NginxCluster
+	conflicts int
+	gets      int
+}
+
+func (c *conflictingClient) Get(_ context.Context, _ client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	c.gets++
+	c.stored.DeepCopyInto(obj.(*nginxv1.NginxCluster))
+	return nil
+}
+
+func (c *conflictingClient) Update(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	return c.write(obj)
+}
+
+func (c *conflictingClient) Status() client.SubResourceWriter {
+	return conflictingStatusWriter{c: c}
+}
+
+func (c *conflictingClient) write(obj client.Object) error {
+	if c.conflicts > 0 {
+		c.conflicts--
+		// Simulate another writer bumping the object underneath us
+		c.stored.ResourceVersion = "2"
+		c.stored.Labels = map[string]string{"touched-by": "someone-else"}
+		return apierrors.NewConflict(schema.GroupResource{Group: "nginx.example.com", Resource: "nginxclusters"},
+			obj.GetName(), nil)
+	}
+	obj.(*nginxv1.NginxCluster).DeepCopyInto(c.stored)
+	return nil
+}
+
+type conflictingStatusWriter struct {
+	client.SubResourceWriter
+	c *conflictingClient
+}
+
+func (w conflictingStatusWriter) Update(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+	return w.c.write(obj)
+}
+
+func TestUpdateWithRetryReappliesMutationAfterConflict(t *testing.T) {
+	stored := newTestNginxCluster("retry")
+	stored.ResourceVersion = "1"
+	c := &conflictingClient{stored: stored, conflicts: 1}
+	r := &NginxClusterReconciler{Client: c, Scheme: testScheme}
+
+	m := stored.DeepCopy()
+	err := r.updateWithRetry(context.Background(), m, func() {
+		m.Spec.Image = "nginx:1.27"
+	})
+	if err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if c.gets != 1 {
+		t.Errorf("expected the object to be re-fetched once, got %d gets", c.gets)
+	}
+	if c.stored.Spec.Image != "nginx:1.27" {
+		t.Errorf("mutation was not persisted, image is %q", c.stored.Spec.Image)
+	}
+	if c.stored.Labels["touched-by"] != "someone-else" {
+		t.Error("retry overwrote the concurrent change instead of re-reading it")
+	}
+}
+
+func TestUpdateStatusWithRetryReappliesMutationAfterConflict(t *testing.T) {
+	stored := newTestNginxCluster("retry-status")
+	stored.ResourceVersion = "1"
+	c := &conflictingClient{stored: stored, conflicts: 1}
+	r := &NginxClusterReconciler{Client: c, Scheme: testScheme}
+
+	m := stored.DeepCopy()
+	err := r.updateStatusWithRetry(context.Background(), m, func() {
+		m.Status.ConfigHash = "abc"
+	})
+	if err != nil {
+		t.Fatalf("status update failed: %v", err)
+	}
+	if c.gets != 1 {
+		t.Errorf("expected the object to be re-fetched once, got %d gets", c.gets)
+	}
+	if c.stored.Status.ConfigHash != "abc" {
+		t.Errorf("status mutation was not persisted, hash is %q", c.stored.Status.ConfigHash)
+	}
+}
+
+func TestUpdateWithRetryGivesUpOnPersistentConflict(t *testing.T) {
+	stored := newTestNginxCluster("retry-exhausted")
+	c := &conflictingClient{stored: stored, conflicts: 100}
+	r := &NginxClusterReconciler{Client: c, Scheme: testScheme}
+
+	m := stored.DeepCopy()
+	err := r.updateWithRetry(context.Background(), m, func() {})
+	if !apierrors.IsConflict(err) {
+		t.Fatalf("expected a conflict error once retries are exhausted, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// revisionAnnotation is set by the Deployment controller on its ReplicaSets
+const revisionAnnotation = "deployment.kubernetes.io/revision"
+
+// replicaSetsForDeployment lists the ReplicaSets controlled by dep, newest
+// revision first
+func (r *NginxClusterReconciler) replicaSetsForDeployment(ctx context.Context, dep *appsv1.Deployment) ([]appsv1.ReplicaSet, error) {
+	list := &appsv1.ReplicaSetList{}
+	if err := r.List(ctx, list, client.InNamespace(dep.Namespace), client.MatchingLabels(dep.Spec.Selector.MatchLabels)); err != nil {
+		return nil, err
+	}
+	var sets []appsv1.ReplicaSet
+	for _, rs := range list.Items {
+		if metav1.IsControlledBy(&rs, dep) {
+			sets = append(sets, rs)
+		}
+	}
+	sort.Slice(sets, func(i, j int) bool {
+		return replicaSetRevision(&sets[i]) > replicaSetRevision(&sets[j])
+	})
+	return sets, nil
+}
+
+// replicaSetRevision returns the Deployment revision a ReplicaSet belongs to
+func replicaSetRevision(rs *appsv1.ReplicaSet) int64 {
+	rev, _ := strconv.ParseInt(rs.Annotations[revisionAnnotation], 10, 64)
+	return rev
+}
+
+// revisionsForReplicaSets returns the rollback points reported in status
+func revisionsForReplicaSets(sets []appsv1.ReplicaSet) []nginxv1.RevisionStatus {
+	var revisions []nginxv1.RevisionStatus
+	for i := range sets {
+		revisions = append(revisions, nginxv1.RevisionStatus{
+			Revision:   replicaSetRevision(&sets[i]),
+			ReplicaSet: sets[i].Name,
+			ConfigHash: sets[i].Spec.Template.Annotations["config-hash"],
+		})
+	}
+	return revisions
+}
+
+// reconcileRollback pins the pod template of dep to the ReplicaSet of
+// spec.rollbackToRevision. Rolling back makes the Deployment controller move
+// that ReplicaSet to a new revision, so the ReplicaSet is remembered in status
+// and looked up by name afterwards. desired is the spec-derived Deployment. It
+// returns the rollback to report, or nil when none is requested, and whether
+// dep was updated.
+func (r *NginxClusterReconciler) reconcileRollback(ctx context.Context, m *nginxv1.NginxCluster, dep, desired *appsv1.Deployment, sets []appsv1.ReplicaSet) (*nginxv1.RollbackStatus, bool, error) {
+	rev := m.Spec.RollbackToRevision
+	if rev == nil {
+		return nil, false, nil
+	}
+	var target *appsv1.ReplicaSet
+	for i := range sets {
+		if prev := m.Status.Rollback; prev != nil && prev.Revision == *rev {
+			if sets[i].Name == prev.ReplicaSet {
+				target = &sets[i]
+			}
+		} else if replicaSetRevision(&sets[i]) == *rev {
+			target = &sets[i]
+		}
+	}
+	if target == nil {
+		return nil, false, fmt.Errorf("revision %d of Deployment %s/%s not found", *rev, dep.Namespace, dep.Name)
+	}
+	rollback := &nginxv1.RollbackStatus{Revision: *rev, ReplicaSet: target.Name}
+
+	template := rollbackTemplate(target)
+	if equality.Semantic.DeepEqual(dep.Spec.Template, template) {
+		return rollback, false, nil
+	}
+	log.FromContext(ctx).Info("Rolling back Deployment", "Deployment.Namespace", dep.Namespace, "Deployment.Name", dep.Name, "Revision", *rev, "ReplicaSet", target.Name)
+	err := r.updateDeployment(ctx, dep, desired, func(d *appsv1.Deployment) {
+		d.Spec.Template = *template.DeepCopy()
+		// Force the spec-derived template back once the rollback is cleared
+		delete(d.Annotations, "pod-spec-hash")
+	})
+	return rollback, err == nil, err
+}
+
+// rollbackTemplate returns the pod template of rs as it was set on the
+// Deployment, without the label the Deployment controller adds
+func rollbackTemplate(rs *appsv1.ReplicaSet) corev1.PodTemplateSpec {
+	template := *rs.Spec.Template.DeepCopy()
+	delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+	return template
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// testReplicaSet returns a ReplicaSet of dep at the given revision, created
+// from dep's template with the given config hash
+func testReplicaSet(dep *appsv1.Deployment, name, revision, configHash string) appsv1.ReplicaSet {
+	rs := appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{revisionAnnotation: revision},
+		},
+	}
+	rs.Spec.Template = *dep.Spec.Template.DeepCopy()
+	rs.Spec.Template.Labels[appsv1.DefaultDeploymentUniqueLabelKey] = name
+	rs.Spec.Template.Annotations["config-hash"] = configHash
+	return rs
+}
+
+func TestRevisionsForReplicaSets(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	dep := r.deploymentForNginxCluster(newTestNginxCluster("revisions"), "new")
+	sets := []appsv1.ReplicaSet{
+		testReplicaSet(dep, "revisions-b", "3", "new"),
+		testReplicaSet(dep, "revisions-a", "2", "old"),
+	}
+	revisions := revisionsForReplicaSets(sets)
+	want := []nginxv1.RevisionStatus{
+		{Revision: 3, ReplicaSet: "revisions-b", ConfigHash: "new"},
+		{Revision: 2, ReplicaSet: "revisions-a", ConfigHash: "old"},
+	}
+	if len(revisions) != len(want) || revisions[0] != want[0] || revisions[1] != want[1] {
+		t.Fatalf("revisions = %+v, want %+v", revisions, want)
+	}
+}
+
+func TestReconcileRollback(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("rollback")
+	dep := r.deploymentForNginxCluster(m, "old")
+	old := testReplicaSet(dep, "rollback-a", "2", "old")
+	sets := []appsv1.ReplicaSet{testReplicaSet(dep, "rollback-b", "3", "new"), old}
+
+	if rollback, _, err := r.reconcileRollback(context.Background(), m, dep, dep, sets); rollback != nil || err != nil {
+		t.Fatalf("unexpected rollback %+v, %v without rollbackToRevision", rollback, err)
+	}
+
+	rev := int64(7)
+	m.Spec.RollbackToRevision = &rev
+	if _, _, err := r.reconcileRollback(context.Background(), m, dep, dep, sets); err == nil {
+		t.Fatalf("expected an unknown revision to be reported")
+	}
+
+	// The Deployment already runs revision 2's template
+	rev = 2
+	dep.Spec.Template = rollbackTemplate(&old)
+	rollback, updated, err := r.reconcileRollback(context.Background(), m, dep, dep, sets)
+	if err != nil || updated {
+		t.Fatalf("reconcileRollback() updated = %v, err = %v", updated, err)
+	}
+	if rollback == nil || *rollback != (nginxv1.RollbackStatus{Revision: 2, ReplicaSet: "rollback-a"}) {
+		t.Fatalf("unexpected rollback %+v", rollback)
+	}
+
+	// Once rolled back, the ReplicaSet moves to a new revision and is found by
+	// name
+	m.Status.Rollback = rollback
+	old.Annotations[revisionAnnotation] = "4"
+	sets = []appsv1.ReplicaSet{old, sets[0]}
+	if rollback, _, err := r.reconcileRollback(context.Background(), m, dep, dep, sets); err != nil || rollback.ReplicaSet != "rollback-a" {
+		t.Fatalf("unexpected rollback %+v, %v after the revision moved", rollback, err)
+	}
+}
+
+func TestRollbackTemplate(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	dep := r.deploymentForNginxCluster(newTestNginxCluster("rollback-template"), "hash")
+	rs := testReplicaSet(dep, "rollback-template-a", "1", "hash")
+	template := rollbackTemplate(&rs)
+	if _, ok := template.Labels[appsv1.DefaultDeploymentUniqueLabelKey]; ok {
+		t.Fatalf("expected the pod-template-hash label to be removed")
+	}
+	if _, ok := rs.Spec.Template.Labels[appsv1.DefaultDeploymentUniqueLabelKey]; !ok {
+		t.Fatalf("ReplicaSet template modified")
+	}
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultRolloutPollInterval is the default RolloutPollInterval. The
+// Deployment's status changes with every pod that becomes available during a
+// rollout, but watch events can lag behind, so polling keeps the conditions
+// of the NginxCluster within a few seconds of the rollout.
+const DefaultRolloutPollInterval = 5 * time.Second
+
+// rolloutRequeueAfter shortens requeueAfter to the rollout poll interval
+// while progressing reports a rollout in progress. Pods are not watched, so
+// rollouts are checked every imagePullCheckInterval for failed image pulls
+// even without a poll interval.
+func (r *NginxClusterReconciler) rolloutRequeueAfter(progressing metav1.Condition, requeueAfter time.Duration) time.Duration {
+	if progressing.Status != metav1.ConditionTrue {
+		return requeueAfter
+	}
+	interval := imagePullCheckInterval
+	if r.RolloutPollInterval > 0 {
+		interval = min(interval, r.RolloutPollInterval)
+	}
+	if requeueAfter == 0 || requeueAfter > interval {
+		return interval
+	}
+	return requeueAfter
+}
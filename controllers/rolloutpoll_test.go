@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestRolloutRequeueAfter(t *testing.T) {
+	r := &NginxClusterReconciler{RolloutPollInterval: 2 * time.Second}
+	rollingOut := metav1.Condition{Status: metav1.ConditionTrue}
+	complete := metav1.Condition{Status: metav1.ConditionFalse}
+
+	if got := r.rolloutRequeueAfter(rollingOut, 0); got != 2*time.Second {
+		t.Fatalf("rolloutRequeueAfter() = %v during a rollout, want the poll interval", got)
+	}
+	if got := r.rolloutRequeueAfter(rollingOut, time.Second); got != time.Second {
+		t.Fatalf("rolloutRequeueAfter() = %v, want the earlier requeue", got)
+	}
+	if got := r.rolloutRequeueAfter(complete, 0); got != 0 {
+		t.Fatalf("rolloutRequeueAfter() = %v once converged, want no polling", got)
+	}
+	r.RolloutPollInterval = 0
+	if got := r.rolloutRequeueAfter(rollingOut, 0); got != imagePullCheckInterval {
+		t.Fatalf("rolloutRequeueAfter() = %v without a poll interval, want %v", got, imagePullCheckInterval)
+	}
+}
+
+func TestReconcilePollsDuringRollout(t *testing.T) {
+	requireEnvtest(t)
+	ctx := context.Background()
+
+	m := newTestNginxCluster("rollout-poll")
+	createTestNginxCluster(t, m)
+	key := client.ObjectKeyFromObject(m)
+	eventually(t, func() error {
+		return k8sClient.Get(ctx, key, &appsv1.Deployment{})
+	})
+
+	// envtest runs no Deployment controller, so the rollout never completes
+	r := &NginxClusterReconciler{Client: k8sClient, Scheme: testScheme, RolloutPollInterval: 2 * time.Second}
+	res, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if res.RequeueAfter != 2*time.Second {
+		t.Fatalf("RequeueAfter = %v during the rollout, want the poll interval", res.RequeueAfter)
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// updatedAvailablePercent returns the share of the desired replicas of dep
+// that run the new revision and are available. The Deployment only counts
+// available replicas across revisions, so the old replicas still running
+// are assumed to be available.
+func updatedAvailablePercent(dep *appsv1.Deployment) int32 {
+	desired := int32(1)
+	if dep.Spec.Replicas != nil {
+		desired = *dep.Spec.Replicas
+	}
+	if desired <= 0 {
+		return 100
+	}
+	status := dep.Status
+	old := max(status.Replicas-status.UpdatedReplicas, 0)
+	available := min(max(status.AvailableReplicas-old, 0), status.UpdatedReplicas)
+	return min(available*100/desired, 100)
+}
+
+// nextRampStep returns the ramp step a rollout at prev reached with the
+// replicas of dep. Steps are never taken back within a rollout.
+func nextRampStep(m *nginxv1.NginxCluster, dep *appsv1.Deployment, prev int32) int32 {
+	ramp := m.Spec.RolloutRamp
+	if ramp == nil {
+		return 0
+	}
+	percent := updatedAvailablePercent(dep)
+	step := prev
+	for i, s := range ramp.Steps {
+		if int32(i) > step && s.UpdatedPercent <= percent {
+			step = int32(i)
+		}
+	}
+	return min(step, int32(len(ramp.Steps)-1))
+}
+
+// rampMinReadySeconds returns the minReadySeconds of the Deployment live
+// while its spec-derived version is desired. A rollout about to start, by a
+// config change or a new pod template, begins with the first step; one in
+// progress uses the step recorded in the rollout state, the first until the
+// state caught up. Without a rollout the last step is kept, so the cluster
+// stays converged until the next one.
+func rampMinReadySeconds(m *nginxv1.NginxCluster, live, desired *appsv1.Deployment, configHash string) int32 {
+	ramp := m.Spec.RolloutRamp
+	if ramp == nil || len(ramp.Steps) == 0 {
+		return 0
+	}
+	if live.Spec.Template.Annotations["config-hash"] != configHash || live.Annotations["pod-spec-hash"] != desired.Annotations["pod-spec-hash"] {
+		return ramp.Steps[0].MinReadySeconds
+	}
+	last := int32(len(ramp.Steps) - 1)
+	if progressingCondition(m, live).Status != metav1.ConditionTrue {
+		return ramp.Steps[last].MinReadySeconds
+	}
+	step := int32(0)
+	if state := m.Status.RolloutState; state != nil && state.Phase != nginxv1.RolloutPhaseComplete {
+		step = min(state.RampStep, last)
+	}
+	return ramp.Steps[step].MinReadySeconds
+}
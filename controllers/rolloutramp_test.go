@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+func newTestRamp() *nginxv1.RampSpec {
+	return &nginxv1.RampSpec{Steps: []nginxv1.RampStep{
+		{UpdatedPercent: 0, MinReadySeconds: 60},
+		{UpdatedPercent: 25, MinReadySeconds: 20},
+		{UpdatedPercent: 75, MinReadySeconds: 0},
+	}}
+}
+
+func TestUpdatedAvailablePercent(t *testing.T) {
+	dep := &appsv1.Deployment{}
+	replicas := int32(4)
+	dep.Spec.Replicas = &replicas
+	for _, tc := range []struct {
+		status appsv1.DeploymentStatus
+		want   int32
+	}{
+		{appsv1.DeploymentStatus{Replicas: 4, AvailableReplicas: 4}, 0},
+		{appsv1.DeploymentStatus{Replicas: 5, UpdatedReplicas: 1, AvailableReplicas: 4}, 0},
+		{appsv1.DeploymentStatus{Replicas: 5, UpdatedReplicas: 2, AvailableReplicas: 5}, 50},
+		{appsv1.DeploymentStatus{Replicas: 4, UpdatedReplicas: 4, AvailableReplicas: 4}, 100},
+	} {
+		dep.Status = tc.status
+		if got := updatedAvailablePercent(dep); got != tc.want {
+			t.Errorf("updatedAvailablePercent(%+v) = %d, want %d", tc.status, got, tc.want)
+		}
+	}
+}
+
+func TestNextRampStep(t *testing.T) {
+	m := newTestNginxCluster("ramp-step")
+	m.Spec.RolloutRamp = newTestRamp()
+	replicas := int32(4)
+	dep := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: &replicas}}
+
+	dep.Status = appsv1.DeploymentStatus{Replicas: 5, UpdatedReplicas: 1, AvailableReplicas: 5}
+	if got := nextRampStep(m, dep, 0); got != 1 {
+		t.Fatalf("nextRampStep() at 25%% = %d, want 1", got)
+	}
+	dep.Status = appsv1.DeploymentStatus{Replicas: 4, UpdatedReplicas: 4, AvailableReplicas: 4}
+	if got := nextRampStep(m, dep, 1); got != 2 {
+		t.Fatalf("nextRampStep() at 100%% = %d, want 2", got)
+	}
+	// An updated pod becoming unavailable doesn't take a step back
+	dep.Status = appsv1.DeploymentStatus{Replicas: 4, UpdatedReplicas: 4, AvailableReplicas: 0}
+	if got := nextRampStep(m, dep, 2); got != 2 {
+		t.Fatalf("nextRampStep() = %d, want the step reached", got)
+	}
+}
+
+func TestRampMinReadySeconds(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("ramp-min-ready")
+	m.Spec.RolloutRamp = newTestRamp()
+	desired := r.deploymentForNginxCluster(m, "new")
+	if desired.Spec.MinReadySeconds != 60 {
+		t.Fatalf("created Deployment MinReadySeconds = %d, want the first step", desired.Spec.MinReadySeconds)
+	}
+
+	live := r.deploymentForNginxCluster(m, "old")
+	if got := rampMinReadySeconds(m, live, desired, "new"); got != 60 {
+		t.Fatalf("pending rollout MinReadySeconds = %d, want the first step", got)
+	}
+
+	live = desired.DeepCopy()
+	live.Status = appsv1.DeploymentStatus{ObservedGeneration: live.Generation, Replicas: 3, UpdatedReplicas: 1, AvailableReplicas: 3}
+	if got := rampMinReadySeconds(m, live, desired, "new"); got != 60 {
+		t.Fatalf("MinReadySeconds before the state caught up = %d, want the first step", got)
+	}
+	m.Status.RolloutState = &nginxv1.RolloutState{TargetHash: "new", Phase: nginxv1.RolloutPhaseRollingOut, RampStep: 1}
+	if got := rampMinReadySeconds(m, live, desired, "new"); got != 20 {
+		t.Fatalf("rolling out MinReadySeconds = %d, want the second step", got)
+	}
+
+	live.Status = appsv1.DeploymentStatus{ObservedGeneration: live.Generation, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2}
+	m.Status.RolloutState.Phase = nginxv1.RolloutPhaseComplete
+	if got := rampMinReadySeconds(m, live, desired, "new"); got != 0 {
+		t.Fatalf("rolled out MinReadySeconds = %d, want the last step", got)
+	}
+
+	m.Spec.RolloutRamp = nil
+	if got := rampMinReadySeconds(m, live, desired, "new"); got != 0 {
+		t.Fatalf("MinReadySeconds without a ramp = %d, want 0", got)
+	}
+}
+
+func TestSyncDeploymentSpecMinReadySeconds(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("ramp-sync")
+	dep := r.deploymentForNginxCluster(m, "hash")
+	desired := r.deploymentForNginxCluster(m, "hash")
+	desired.Spec.MinReadySeconds = 30
+	if !syncDeploymentSpec(dep, desired) || dep.Spec.MinReadySeconds != 30 {
+		t.Fatalf("MinReadySeconds not synced, got %d", dep.Spec.MinReadySeconds)
+	}
+}
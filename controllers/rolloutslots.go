@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// rolloutSlotRetryInterval is how often a config rollout waiting for a slot
+// checks again
+const rolloutSlotRetryInterval = 15 * time.Second
+
+// rolloutSlots tracks the clusters with a rollout in flight, so a bad config
+// change applied to the whole fleet rolls out to a few clusters at a time.
+// The state is in memory only: after a restart, clusters still rolling out
+// take their slot back on their first reconcile, which may briefly exceed
+// the limit.
+type rolloutSlots struct {
+	mu      sync.Mutex
+	holders map[types.NamespacedName]struct{}
+}
+
+// acquire takes a slot for key, which it keeps if it already holds one, and
+// reports whether it got it. limit 0 means unlimited.
+func (s *rolloutSlots) acquire(key types.NamespacedName, limit int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.holders[key]; ok {
+		return true
+	}
+	if limit > 0 && len(s.holders) >= limit {
+		return false
+	}
+	s.hold(key)
+	return true
+}
+
+// track records whether key has a rollout in flight, whatever started it
+func (s *rolloutSlots) track(key types.NamespacedName, rollingOut bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if rollingOut {
+		s.hold(key)
+	} else {
+		delete(s.holders, key)
+	}
+}
+
+// hold adds key to the holders, s.mu must be held
+func (s *rolloutSlots) hold(key types.NamespacedName) {
+	if s.holders == nil {
+		s.holders = map[types.NamespacedName]struct{}{}
+	}
+	s.holders[key] = struct{}{}
+}
+
+// inFlight returns the number of clusters holding a slot
+func (s *rolloutSlots) inFlight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.holders)
+}
+
+// waitingForRolloutSlotCondition reports a config rollout held back until
+// fewer than limit clusters roll out
+func waitingForRolloutSlotCondition(m *nginxv1.NginxCluster, inFlight, limit int) metav1.Condition {
+	return metav1.Condition{
+		Type:               nginxv1.ConditionWaitingForRolloutSlot,
+		Status:             metav1.ConditionTrue,
+		Reason:             "MaxConcurrentRollouts",
+		Message:            fmt.Sprintf("%d of at most %d clusters are rolling out, the config rollout starts when one completes", inFlight, limit),
+		ObservedGeneration: m.Generation,
+	}
+}
+
+// rolloutSlotAcquiredCondition reports that a cluster no longer waits for a
+// rollout slot
+func rolloutSlotAcquiredCondition(m *nginxv1.NginxCluster) metav1.Condition {
+	return metav1.Condition{
+		Type:               nginxv1.ConditionWaitingForRolloutSlot,
+		Status:             metav1.ConditionFalse,
+		Reason:             "RolloutSlotAcquired",
+		Message:            "The config rollout is not held back",
+		ObservedGeneration: m.Generation,
+	}
+}
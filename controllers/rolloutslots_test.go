@@ -0,0 +1,72 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestRolloutSlots(t *testing.T) {
+	var slots rolloutSlots
+	a := types.NamespacedName{Namespace: "default", Name: "a"}
+	b := types.NamespacedName{Namespace: "default", Name: "b"}
+	c := types.NamespacedName{Namespace: "default", Name: "c"}
+
+	if !slots.acquire(a, 2) || !slots.acquire(b, 2) {
+		t.Fatalf("expected two slots to be free")
+	}
+	if slots.acquire(c, 2) {
+		t.Fatalf("third rollout started beyond the limit")
+	}
+	// A holder keeps its slot
+	if !slots.acquire(a, 2) {
+		t.Fatalf("holder lost its slot")
+	}
+
+	slots.track(a, false)
+	if !slots.acquire(c, 2) {
+		t.Fatalf("expected the completed rollout to free its slot")
+	}
+
+	// Rollouts started otherwise, e.g. before a restart, count too
+	slots.track(a, true)
+	if n := slots.inFlight(); n != 3 {
+		t.Fatalf("inFlight() = %d, want 3", n)
+	}
+
+	var unlimited rolloutSlots
+	for i := 0; i < 100; i++ {
+		if !unlimited.acquire(types.NamespacedName{Name: strings.Repeat("x", i+1)}, 0) {
+			t.Fatalf("limit 0 must be unlimited")
+		}
+	}
+}
+
+func TestWaitingForRolloutSlotCondition(t *testing.T) {
+	m := newTestNginxCluster("rollout-slot")
+	cond := waitingForRolloutSlotCondition(m, 5, 5)
+	if cond.Status != metav1.ConditionTrue || cond.Reason != "MaxConcurrentRollouts" || !strings.Contains(cond.Message, "5 of at most 5") {
+		t.Fatalf("unexpected condition %+v", cond)
+	}
+	if cond := rolloutSlotAcquiredCondition(m); cond.Status != metav1.ConditionFalse {
+		t.Fatalf("unexpected condition %+v", cond)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// nextRolloutState advances the rollout state recorded in status for the
+// rollout of configHash to the pods of dep. The state is carried over while
+// the target stays the same, so a rollout interrupted by an operator restart
+// keeps its start time instead of starting over, and so does the step of
+// spec.rolloutRamp it reached. A rollback suspends the
+// rollout, leaving the state as it was.
+func nextRolloutState(m *nginxv1.NginxCluster, dep *appsv1.Deployment, configHash string, configDrift metav1.Condition, rollback *nginxv1.RollbackStatus, now metav1.Time) *nginxv1.RolloutState {
+	prev := m.Status.RolloutState
+	if rollback != nil {
+		return prev
+	}
+	state := &nginxv1.RolloutState{TargetHash: configHash, StartTime: now}
+	if prev != nil && prev.TargetHash == configHash {
+		state = prev.DeepCopy()
+	} else if prev != nil {
+		state.PreviousHash = prev.TargetHash
+	}
+	switch {
+	case dep.Spec.Template.Annotations["config-hash"] != configHash:
+		state.Phase = nginxv1.RolloutPhasePropagating
+		state.CompletionTime = nil
+		state.RampStep = 0
+	case configDrift.Status == metav1.ConditionTrue || dep.Status.UpdatedReplicas < dep.Status.Replicas:
+		if state.Phase == nginxv1.RolloutPhaseComplete {
+			// A new pod template rolls out under the same config
+			state.RampStep = 0
+		}
+		state.Phase = nginxv1.RolloutPhaseRollingOut
+		state.CompletionTime = nil
+		state.RampStep = nextRampStep(m, dep, state.RampStep)
+	default:
+		state.Phase = nginxv1.RolloutPhaseComplete
+		if state.CompletionTime == nil {
+			completed := now
+			state.CompletionTime = &completed
+		}
+	}
+	return state
+}
+
+// completedRolloutDuration returns how long the rollout of state took when it
+// completed since the previous state prev was recorded, nil otherwise. Only
+// rollouts seen in progress count, so clusters first reconciled with their
+// config already rolled out don't report a zero duration.
+func completedRolloutDuration(prev, state *nginxv1.RolloutState) *metav1.Duration {
+	if prev == nil || state == nil || state.CompletionTime == nil {
+		return nil
+	}
+	if prev.TargetHash != state.TargetHash || prev.CompletionTime != nil {
+		return nil
+	}
+	return &metav1.Duration{Duration: state.CompletionTime.Sub(state.StartTime.Time)}
+}
+
+// completeRollout reports a rollout that completedRolloutDuration found
+// completed, once its completion is recorded in status: the rollout duration
+// is observed and a RolloutComplete event summarizes the rollout
+func (r *NginxClusterReconciler) completeRollout(m *nginxv1.NginxCluster, state *nginxv1.RolloutState, duration *metav1.Duration, replicas int32) {
+	if duration == nil {
+		return
+	}
+	rolloutDurationSeconds.WithLabelValues(m.Namespace, m.Name).Observe(duration.Seconds())
+	from := state.PreviousHash
+	if from == "" {
+		from = "unknown"
+	}
+	r.recordEvent(m, corev1.EventTypeNormal, "RolloutComplete", fmt.Sprintf("Rolled out config %s (from %s) to %d replicas in %s",
+		state.TargetHash, from, replicas, duration.Duration.Round(time.Second)))
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+func TestNextRolloutState(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("rollout-state")
+	dep := r.deploymentForNginxCluster(m, "old")
+	inSync := metav1.Condition{Status: metav1.ConditionFalse}
+	drifted := metav1.Condition{Status: metav1.ConditionTrue}
+	start := metav1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	later := metav1.NewTime(start.Add(time.Minute))
+
+	// The ConfigMap was updated, the pod template not yet
+	state := nextRolloutState(m, dep, "new", drifted, nil, start)
+	if state.Phase != nginxv1.RolloutPhasePropagating || state.TargetHash != "new" || !state.StartTime.Equal(&start) {
+		t.Fatalf("unexpected state %+v", state)
+	}
+
+	// After an operator restart, the rollout continues where it was
+	m.Status.RolloutState = state
+	setConfigRolloutAnnotations(&dep.Spec.Template, m, "new", "now")
+	state = nextRolloutState(m, dep, "new", drifted, nil, later)
+	if state.Phase != nginxv1.RolloutPhaseRollingOut || !state.StartTime.Equal(&start) {
+		t.Fatalf("unexpected state %+v", state)
+	}
+
+	m.Status.RolloutState = state
+	state = nextRolloutState(m, dep, "new", inSync, nil, later)
+	if state.Phase != nginxv1.RolloutPhaseComplete || state.CompletionTime == nil || !state.CompletionTime.Equal(&later) {
+		t.Fatalf("unexpected state %+v", state)
+	}
+
+	// A ramp advances with the updated replicas and starts over with the
+	// next rollout of the same config
+	m.Spec.RolloutRamp = newTestRamp()
+	m.Status.RolloutState = state
+	dep.Status = appsv1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 1, AvailableReplicas: 2}
+	ramped := nextRolloutState(m, dep, "new", inSync, nil, later)
+	if ramped.Phase != nginxv1.RolloutPhaseRollingOut || ramped.RampStep != 0 {
+		t.Fatalf("unexpected state %+v", ramped)
+	}
+	m.Status.RolloutState = ramped
+	dep.Status = appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2}
+	if got := nextRolloutState(m, dep, "new", drifted, nil, later); got.RampStep != 2 {
+		t.Fatalf("ramp step = %d, want 2", got.RampStep)
+	}
+	m.Spec.RolloutRamp = nil
+	dep.Status = appsv1.DeploymentStatus{}
+
+	// A rollback leaves the state alone
+	m.Status.RolloutState = state
+	if got := nextRolloutState(m, dep, "newer", drifted, &nginxv1.RollbackStatus{Revision: 1}, later); got != state {
+		t.Fatalf("rollout state changed during a rollback: %+v", got)
+	}
+}
+
+func TestRolloutCompleteEventFiresOnce(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	r := &NginxClusterReconciler{Scheme: testScheme, Recorder: recorder}
+	m := newTestNginxCluster("rollout-complete")
+	dep := r.deploymentForNginxCluster(m, "old")
+	dep.Status = appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 2}
+	start := metav1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	done := metav1.NewTime(start.Add(90 * time.Second))
+	m.Status.RolloutState = &nginxv1.RolloutState{Phase: nginxv1.RolloutPhaseComplete, TargetHash: "old", StartTime: start, CompletionTime: &start}
+
+	// Propagating, rolling out, then completed and reconciled again
+	reconcile := func(drift metav1.ConditionStatus, now metav1.Time) {
+		state := nextRolloutState(m, dep, "new", metav1.Condition{Status: drift}, nil, now)
+		r.completeRollout(m, state, completedRolloutDuration(m.Status.RolloutState, state), dep.Status.UpdatedReplicas)
+		m.Status.RolloutState = state
+	}
+	reconcile(metav1.ConditionTrue, start)
+	setConfigRolloutAnnotations(&dep.Spec.Template, m, "new", "now")
+	reconcile(metav1.ConditionTrue, start)
+	reconcile(metav1.ConditionFalse, done)
+	reconcile(metav1.ConditionFalse, done)
+
+	if len(recorder.Events) != 1 {
+		t.Fatalf("expected a single event, got %d", len(recorder.Events))
+	}
+	event := <-recorder.Events
+	if want := "Normal RolloutComplete Rolled out config new (from old) to 2 replicas in 1m30s"; event != want {
+		t.Fatalf("event = %q, want %q", event, want)
+	}
+	if m.Status.RolloutState.PreviousHash != "old" {
+		t.Fatalf("previous hash not recorded: %+v", m.Status.RolloutState)
+	}
+}
+
+func TestCompletedRolloutDuration(t *testing.T) {
+	start := metav1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	done := metav1.NewTime(start.Add(90 * time.Second))
+	rolling := &nginxv1.RolloutState{Phase: nginxv1.RolloutPhaseRollingOut, TargetHash: "new", StartTime: start}
+	complete := &nginxv1.RolloutState{Phase: nginxv1.RolloutPhaseComplete, TargetHash: "new", StartTime: start, CompletionTime: &done}
+
+	if got := completedRolloutDuration(rolling, complete); got == nil || got.Duration != 90*time.Second {
+		t.Fatalf("completedRolloutDuration() = %v, want 90s", got)
+	}
+	if got := completedRolloutDuration(rolling, rolling); got != nil {
+		t.Fatalf("duration reported for a rollout in progress: %v", got)
+	}
+	// Completion was already recorded
+	if got := completedRolloutDuration(complete, complete); got != nil {
+		t.Fatalf("duration reported twice: %v", got)
+	}
+	// The rollout was never seen in progress
+	if got := completedRolloutDuration(nil, complete); got != nil {
+		t.Fatalf("duration reported without a previous state: %v", got)
+	}
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// routeGVK is the OpenShift Route kind. Routes are handled as unstructured
+// objects so the operator doesn't depend on the OpenShift API module.
+var routeGVK = schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "Route"}
+
+// routeAPIAvailable reports whether the cluster serves the Route API, i.e.
+// whether it is an OpenShift cluster
+func routeAPIAvailable(mapper meta.RESTMapper) (bool, error) {
+	return apiAvailable(mapper, routeGVK)
+}
+
+// apiAvailable reports whether the cluster serves the given kind, for APIs
+// defined by optional CRDs
+func apiAvailable(mapper meta.RESTMapper, gvk schema.GroupVersionKind) (bool, error) {
+	_, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// newRoute returns an empty unstructured Route
+func newRoute() *unstructured.Unstructured {
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(routeGVK)
+	return route
+}
+
+// reconcileRoute creates or updates the Route when it is requested in the
+// spec, and removes a previously created one otherwise. Clusters without the
+// Route API are skipped.
+func (r *NginxClusterReconciler) reconcileRoute(ctx context.Context, m *nginxv1.NginxCluster) error {
+	logger := log.FromContext(ctx)
+
+	available, err := routeAPIAvailable(r.RESTMapper())
+	if err != nil {
+		return err
+	}
+	if !available {
+		if m.Spec.Route != nil {
+			logger.Info("Route API route.openshift.io/v1 not available, skipping Route")
+		}
+		return nil
+	}
+
+	route := newRoute()
+	err = r.Get(ctx, types.NamespacedName{Name: m.Name, Namespace: workloadNamespace(m)}, route)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+
+	if m.Spec.Route == nil {
+		if exists && r.isOwnedBy(route, m) {
+			logger.Info("Deleting Route", "Route.Namespace", route.GetNamespace(), "Route.Name", route.GetName())
+			if err := r.Delete(ctx, route); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+		return nil
+	}
+
+	desired := r.routeForNginxCluster(m)
+	if !exists {
+		logger.Info("Creating a new Route", "Route.Namespace", desired.GetNamespace(), "Route.Name", desired.GetName())
+		return r.createObject(ctx, desired)
+	}
+
+	if syncRouteSpec(route.DeepCopy(), desired) {
+		logger.Info("Updating Route", "Route.Namespace", route.GetNamespace(), "Route.Name", route.GetName())
+		return r.updateObject(ctx, route, desired, func() {
+			syncRouteSpec(route, desired)
+		})
+	}
+	return nil
+}
+
+// routeForNginxCluster returns a Route exposing the cluster's Service
+func (r *NginxClusterReconciler) routeForNginxCluster(m *nginxv1.NginxCluster) *unstructured.Unstructured {
+	serviceName := m.Name
+	if m.Spec.SharedServiceName != "" {
+		serviceName = m.Spec.SharedServiceName
+	}
+	spec := map[string]interface{}{
+		"to": map[string]interface{}{
+			"kind":   "Service",
+			"name":   serviceName,
+			"weight": int64(100),
+		},
+		"port": map[string]interface{}{
+			"targetPort": "http",
+		},
+	}
+	if m.Spec.Route.Host != "" {
+		spec["host"] = m.Spec.Route.Host
+	}
+	if m.Spec.Route.TLSTermination != "" {
+		spec["tls"] = map[string]interface{}{
+			"termination": m.Spec.Route.TLSTermination,
+		}
+	}
+
+	route := newRoute()
+	route.SetName(m.Name)
+	route.SetNamespace(workloadNamespace(m))
+	route.Object["spec"] = spec
+	r.setOwner(m, route)
+	return route
+}
+
+// syncRouteSpec copies the fields the operator manages from desired onto
+// existing and reports whether anything changed. A host generated by the
+// router is kept when the spec leaves it empty.
+func syncRouteSpec(existing, desired *unstructured.Unstructured) bool {
+	changed := false
+	for _, path := range [][]string{
+		{"spec", "to", "name"},
+		{"spec", "port", "targetPort"},
+		{"spec", "host"},
+		{"spec", "tls", "termination"},
+	} {
+		want, wantFound, _ := unstructured.NestedString(desired.Object, path...)
+		got, _, _ := unstructured.NestedString(existing.Object, path...)
+		switch {
+		case path[1] == "host" && !wantFound:
+			continue
+		case path[1] == "tls" && !wantFound:
+			if _, found, _ := unstructured.NestedFieldNoCopy(existing.Object, "spec", "tls"); found {
+				unstructured.RemoveNestedField(existing.Object, "spec", "tls")
+				changed = true
+			}
+		case got != want:
+			_ = unstructured.SetNestedField(existing.Object, want, path...)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// deleteManagedUnstructured removes the objects of the given kind labelled
+// as managed by m, if the cluster serves the kind
+func (r *NginxClusterReconciler) deleteManagedUnstructured(ctx context.Context, m *nginxv1.NginxCluster, gvk schema.GroupVersionKind) error {
+	if available, err := apiAvailable(r.RESTMapper(), gvk); err != nil || !available {
+		return err
+	}
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := r.List(ctx, list, client.InNamespace(workloadNamespace(m)), client.MatchingLabels(managementLabelsForNginxCluster(m))); err != nil {
+		return err
+	}
+	for i := range list.Items {
+		obj := &list.Items[i]
+		log.FromContext(ctx).Info("Deleting managed resource", "Namespace", obj.GetNamespace(), "Name", obj.GetName())
+		if err := r.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
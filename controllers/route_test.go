@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+func TestRouteAPIAvailable(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	if ok, err := routeAPIAvailable(mapper); ok || err != nil {
+		t.Fatalf("routeAPIAvailable() = %v, %v without the Route API", ok, err)
+	}
+	mapper.Add(routeGVK, meta.RESTScopeNamespace)
+	if ok, err := routeAPIAvailable(mapper); !ok || err != nil {
+		t.Fatalf("routeAPIAvailable() = %v, %v with the Route API", ok, err)
+	}
+}
+
+func TestRouteForNginxCluster(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("route")
+	m.UID = "route-uid"
+	m.Spec.Route = &nginxv1.RouteSpec{Host: "www.example.com", TLSTermination: "edge"}
+
+	route := r.routeForNginxCluster(m)
+	if route.GetKind() != "Route" || route.GetAPIVersion() != "route.openshift.io/v1" {
+		t.Fatalf("unexpected kind %s %s", route.GetAPIVersion(), route.GetKind())
+	}
+	for path, want := range map[[3]string]string{
+		{"spec", "to", "name"}:         m.Name,
+		{"spec", "port", "targetPort"}: "http",
+		{"spec", "tls", "termination"}: "edge",
+	} {
+		if got, _, _ := unstructured.NestedString(route.Object, path[:]...); got != want {
+			t.Errorf("%v = %q, want %q", path, got, want)
+		}
+	}
+	if host, _, _ := unstructured.NestedString(route.Object, "spec", "host"); host != "www.example.com" {
+		t.Errorf("unexpected host %q", host)
+	}
+	if err := checkControllerOwner(route, m); err != nil {
+		t.Error(err)
+	}
+
+	m.Spec.SharedServiceName = "edge"
+	if name, _, _ := unstructured.NestedString(r.routeForNginxCluster(m).Object, "spec", "to", "name"); name != "edge" {
+		t.Errorf("expected the Route to target the shared Service, got %q", name)
+	}
+}
+
+func TestSyncRouteSpec(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("route-sync")
+	m.Spec.Route = &nginxv1.RouteSpec{TLSTermination: "edge"}
+
+	existing := r.routeForNginxCluster(m)
+	// Filled in by the router
+	_ = unstructured.SetNestedField(existing.Object, "route-sync-default.apps.example.com", "spec", "host")
+	if syncRouteSpec(existing, r.routeForNginxCluster(m)) {
+		t.Fatalf("generated host counted as drift")
+	}
+
+	m.Spec.Route = &nginxv1.RouteSpec{Host: "www.example.com"}
+	if !syncRouteSpec(existing, r.routeForNginxCluster(m)) {
+		t.Fatalf("expected the host and TLS changes to be synced")
+	}
+	if host, _, _ := unstructured.NestedString(existing.Object, "spec", "host"); host != "www.example.com" {
+		t.Errorf("unexpected host %q", host)
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(existing.Object, "spec", "tls"); found {
+		t.Errorf("expected TLS to be removed")
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strconv"
+	"strings"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// scrapeAnnotationPrefix is the prefix of the annotations annotation-based
+// Prometheus discovery reads
+const scrapeAnnotationPrefix = "prometheus.io/"
+
+const (
+	defaultMetricsPort = 9113
+	defaultMetricsPath = "/metrics"
+)
+
+// scrapeAnnotations returns the pod annotations announcing the metrics
+// endpoint to Prometheus, nil unless spec.enableScrapeAnnotations is set
+func scrapeAnnotations(m *nginxv1.NginxCluster) map[string]string {
+	if !m.Spec.EnableScrapeAnnotations {
+		return nil
+	}
+	port := metricsPortForNginxCluster(m)
+	path := m.Spec.MetricsPath
+	if path == "" {
+		path = defaultMetricsPath
+	}
+	return map[string]string{
+		scrapeAnnotationPrefix + "scrape": "true",
+		scrapeAnnotationPrefix + "port":   strconv.Itoa(int(port)),
+		scrapeAnnotationPrefix + "path":   path,
+	}
+}
+
+// metricsPortForNginxCluster returns the port the metrics are served on
+func metricsPortForNginxCluster(m *nginxv1.NginxCluster) int32 {
+	if m.Spec.MetricsPort == 0 {
+		return defaultMetricsPort
+	}
+	return m.Spec.MetricsPort
+}
+
+// templateScrapeAnnotations returns the scrape annotations among annotations
+func templateScrapeAnnotations(annotations map[string]string) map[string]string {
+	var scrape map[string]string
+	for k, v := range annotations {
+		if strings.HasPrefix(k, scrapeAnnotationPrefix) {
+			if scrape == nil {
+				scrape = map[string]string{}
+			}
+			scrape[k] = v
+		}
+	}
+	return scrape
+}
+
+// syncScrapeAnnotations replaces the scrape annotations of existing with the
+// ones of desired
+func syncScrapeAnnotations(existing, desired map[string]string) map[string]string {
+	for k := range templateScrapeAnnotations(existing) {
+		delete(existing, k)
+	}
+	for k, v := range templateScrapeAnnotations(desired) {
+		if existing == nil {
+			existing = map[string]string{}
+		}
+		existing[k] = v
+	}
+	return existing
+}
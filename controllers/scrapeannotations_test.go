@@ -0,0 +1,55 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+)
+
+func TestScrapeAnnotations(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("scrape")
+	before := r.deploymentForNginxCluster(m, "hash")
+	if a := templateScrapeAnnotations(before.Spec.Template.Annotations); a != nil {
+		t.Fatalf("unexpected scrape annotations %v", a)
+	}
+
+	m.Spec.EnableScrapeAnnotations = true
+	dep := r.deploymentForNginxCluster(m, "hash")
+	a := dep.Spec.Template.Annotations
+	if a["prometheus.io/scrape"] != "true" || a["prometheus.io/port"] != "9113" || a["prometheus.io/path"] != "/metrics" {
+		t.Fatalf("unexpected annotations %v", a)
+	}
+	if dep.Annotations["pod-spec-hash"] == before.Annotations["pod-spec-hash"] {
+		t.Fatalf("enabling the annotations doesn't roll the pods")
+	}
+
+	m.Spec.MetricsPort = 9145
+	m.Spec.MetricsPath = "/stats"
+	desired := r.deploymentForNginxCluster(m, "hash")
+	if a := desired.Spec.Template.Annotations; a["prometheus.io/port"] != "9145" || a["prometheus.io/path"] != "/stats" {
+		t.Fatalf("unexpected annotations %v", a)
+	}
+
+	// Disabling them removes them from the live template, other annotations stay
+	dep.Spec.Template.Annotations["restartedAt"] = "2025-01-01T00:00:00Z"
+	m.Spec.EnableScrapeAnnotations = false
+	syncDeploymentSpec(dep, r.deploymentForNginxCluster(m, "hash"))
+	if a := dep.Spec.Template.Annotations; templateScrapeAnnotations(a) != nil || a["restartedAt"] == "" {
+		t.Fatalf("unexpected annotations after sync %v", a)
+	}
+}
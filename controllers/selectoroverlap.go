@@ -0,0 +1,87 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// maxReportedForeignPods bounds the pod names in the SelectorOverlap message
+const maxReportedForeignPods = 3
+
+// foreignPods returns the names of the pods, not being deleted, that are not
+// run by a ReplicaSet of dep. pods are those matching the labels of the
+// cluster's Service selector. ReplicaSets created after sets was listed,
+// e.g. when a rollout just started, are looked up by name.
+func (r *NginxClusterReconciler) foreignPods(ctx context.Context, pods []corev1.Pod, dep *appsv1.Deployment, sets []appsv1.ReplicaSet) ([]string, error) {
+	owned := make(map[types.UID]bool, len(sets))
+	for i := range sets {
+		owned[sets[i].UID] = true
+	}
+	var foreign []string
+	for i := range pods {
+		if pods[i].DeletionTimestamp != nil {
+			continue
+		}
+		ref := metav1.GetControllerOf(&pods[i])
+		if ref != nil && ref.Kind == "ReplicaSet" && !owned[ref.UID] {
+			rs := &appsv1.ReplicaSet{}
+			err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: pods[i].Namespace}, rs)
+			if err != nil && !errors.IsNotFound(err) {
+				return nil, err
+			}
+			owned[ref.UID] = err == nil && rs.UID == ref.UID && metav1.IsControlledBy(rs, dep)
+		}
+		if ref == nil || ref.Kind != "ReplicaSet" || !owned[ref.UID] {
+			foreign = append(foreign, pods[i].Name)
+		}
+	}
+	return foreign, nil
+}
+
+// selectorOverlapCondition reports whether pods other than those of the
+// cluster's Deployment match its Service selector and receive its traffic
+func selectorOverlapCondition(m *nginxv1.NginxCluster, foreign []string) metav1.Condition {
+	cond := metav1.Condition{
+		Type:               nginxv1.ConditionSelectorOverlap,
+		Status:             metav1.ConditionFalse,
+		Reason:             "NoForeignPods",
+		Message:            "Only pods of the cluster's Deployment match the Service selector",
+		ObservedGeneration: m.Generation,
+	}
+	if len(foreign) == 0 {
+		return cond
+	}
+	names := strings.Join(foreign[:min(len(foreign), maxReportedForeignPods)], ", ")
+	if len(foreign) > maxReportedForeignPods {
+		names += ", ..."
+	}
+	cond.Status = metav1.ConditionTrue
+	cond.Reason = "ForeignPods"
+	cond.Message = fmt.Sprintf("%d pods not run by the cluster's Deployment match the Service selector: %s", len(foreign), names)
+	return cond
+}
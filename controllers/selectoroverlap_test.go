@@ -0,0 +1,87 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+func TestSelectorOverlapCondition(t *testing.T) {
+	m := newTestNginxCluster("selector-overlap")
+	if cond := selectorOverlapCondition(m, nil); cond.Status != metav1.ConditionFalse {
+		t.Fatalf("expected no overlap without foreign pods, got %+v", cond)
+	}
+	cond := selectorOverlapCondition(m, []string{"a", "b", "c", "d"})
+	if cond.Status != metav1.ConditionTrue || cond.Reason != "ForeignPods" ||
+		cond.Message != "4 pods not run by the cluster's Deployment match the Service selector: a, b, c, ..." {
+		t.Fatalf("unexpected condition %+v", cond)
+	}
+}
+
+func TestReconcileReportsSelectorOverlap(t *testing.T) {
+	requireEnvtest(t)
+	ctx := context.Background()
+
+	m := newTestNginxCluster("selector-overlap")
+	createTestNginxCluster(t, m)
+	key := client.ObjectKeyFromObject(m)
+	eventually(t, func() error {
+		return k8sClient.Get(ctx, key, &appsv1.Deployment{})
+	})
+
+	// A bare pod carrying the cluster's labels, as another workload's would
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "selector-overlap-other",
+			Namespace: m.Namespace,
+			Labels:    labelsForNginxCluster(m),
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "other", Image: "nginx"}}},
+	}
+	if err := k8sClient.Create(ctx, pod); err != nil {
+		t.Fatalf("failed to create Pod: %v", err)
+	}
+	defer k8sClient.Delete(ctx, pod)
+
+	// Pods are not watched, so a spec change triggers the next reconcile
+	eventually(t, func() error {
+		if err := k8sClient.Get(ctx, key, m); err != nil {
+			return err
+		}
+		m.Spec.Replicas = 3
+		return k8sClient.Update(ctx, m)
+	})
+	eventually(t, func() error {
+		if err := k8sClient.Get(ctx, key, m); err != nil {
+			return err
+		}
+		if cond := meta.FindStatusCondition(m.Status.Conditions, nginxv1.ConditionSelectorOverlap); cond == nil || cond.Status != metav1.ConditionTrue {
+			return fmt.Errorf("SelectorOverlap condition not set: %+v", cond)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// fieldManager is the field manager of the operator's server-side applies
+const fieldManager = "nginx-operator"
+
+// applyObject server-side applies obj, which must only hold the fields the
+// operator manages. Fields other managers set are left alone unless obj sets
+// them too, in which case the operator takes them over.
+func (r *NginxClusterReconciler) applyObject(ctx context.Context, obj client.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+	if err != nil {
+		return err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	obj.SetResourceVersion("")
+	obj.SetManagedFields(nil)
+	return r.Patch(ctx, obj, client.Apply, client.FieldOwner(fieldManager), client.ForceOwnership)
+}
+
+// createObject creates obj, by applying it when server-side apply is enabled
+func (r *NginxClusterReconciler) createObject(ctx context.Context, obj client.Object) error {
+	if r.UseServerSideApply {
+		return r.applyObject(ctx, obj)
+	}
+	return r.Create(ctx, obj)
+}
+
+// updateObject updates obj with mutate, as updateWithRetry does. When
+// server-side apply is enabled, applied is applied instead and obj is replaced
+// with the result; applied must then hold all fields the operator manages on
+// obj, since the ones it leaves out are removed. Either way, obj carries the
+// operator version applied is stamped with.
+func (r *NginxClusterReconciler) updateObject(ctx context.Context, obj, applied client.Object, mutate func()) error {
+	if !r.UseServerSideApply {
+		return r.updateWithRetry(ctx, obj, func() {
+			mutate()
+			carryOperatorVersion(obj, applied)
+		})
+	}
+	if reflect.TypeOf(obj) != reflect.TypeOf(applied) {
+		return fmt.Errorf("cannot apply %T to %T", applied, obj)
+	}
+	if err := r.applyObject(ctx, applied); err != nil {
+		return err
+	}
+	reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(applied).Elem())
+	return nil
+}
+
+// updateDeployment updates dep with mutate. When server-side apply is
+// enabled, mutate is applied to the Deployment derived from desired by
+// appliedDeployment instead, which is then applied.
+func (r *NginxClusterReconciler) updateDeployment(ctx context.Context, dep, desired *appsv1.Deployment, mutate func(*appsv1.Deployment)) error {
+	if !r.UseServerSideApply {
+		return r.updateWithRetry(ctx, dep, func() {
+			mutate(dep)
+			carryOperatorVersion(dep, desired)
+		})
+	}
+	applied := appliedDeployment(desired, dep)
+	mutate(applied)
+	return r.updateObject(ctx, dep, applied, nil)
+}
+
+// appliedDeployment returns the Deployment to apply for the spec-derived
+// desired one. The rollout state is carried over from live: the config hash
+// and restart annotations of the pod template, the pause of the circuit
+// breaker and a template pinned by a rollback, which has no pod-spec-hash.
+// The reconcile steps changing them do so explicitly.
+func appliedDeployment(desired, live *appsv1.Deployment) *appsv1.Deployment {
+	applied := desired.DeepCopy()
+	applied.Spec.Paused = live.Spec.Paused
+	if _, ok := live.Annotations["pod-spec-hash"]; !ok {
+		applied.Spec.Template = *live.Spec.Template.DeepCopy()
+		delete(applied.Annotations, "pod-spec-hash")
+		return applied
+	}
+	for _, key := range []string{"config-hash", "restartedAt"} {
+		if value, ok := live.Spec.Template.Annotations[key]; ok {
+			applied.Spec.Template.Annotations[key] = value
+		} else {
+			delete(applied.Spec.Template.Annotations, key)
+		}
+	}
+	return applied
+}
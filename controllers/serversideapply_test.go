@@ -0,0 +1,129 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// applyRecordingClient records the patches sent to it and serves them back
+// as the result
+type applyRecordingClient struct {
+	client.Client
+	patches []client.Patch
+	options []client.PatchOptions
+}
+
+func (c *applyRecordingClient) Patch(_ context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	c.patches = append(c.patches, patch)
+	c.options = append(c.options, *(&client.PatchOptions{}).ApplyOptions(opts))
+	obj.SetResourceVersion("applied")
+	return nil
+}
+
+func TestUpdateObjectServerSideApply(t *testing.T) {
+	c := &applyRecordingClient{}
+	r := &NginxClusterReconciler{Client: c, Scheme: testScheme, UseServerSideApply: true}
+	m := newTestNginxCluster("ssa")
+
+	live := r.configMapForNginxCluster(m, "events {}\n", "old")
+	live.ResourceVersion = "1"
+	live.Labels["other-controller"] = "true"
+	applied := r.configMapForNginxCluster(m, "http {}\n", "new")
+	if err := r.updateObject(context.Background(), live, applied, func() {
+		t.Fatalf("mutate called with server-side apply")
+	}); err != nil {
+		t.Fatalf("updateObject() error = %v", err)
+	}
+
+	if len(c.patches) != 1 || c.patches[0].Type() != client.Apply.Type() {
+		t.Fatalf("expected a single apply patch, got %v", c.patches)
+	}
+	if opts := c.options[0]; opts.FieldManager != fieldManager || opts.Force == nil || !*opts.Force {
+		t.Fatalf("unexpected patch options %+v", opts)
+	}
+	if gvk := applied.GroupVersionKind(); gvk.Kind != "ConfigMap" || gvk.Version != "v1" {
+		t.Fatalf("applied object has kind %v", gvk)
+	}
+	// The labels of other controllers are not part of the applied object
+	if _, ok := applied.Labels["other-controller"]; ok {
+		t.Fatalf("applied object carries labels the operator doesn't manage")
+	}
+	if live.ResourceVersion != "applied" || live.Data["nginx.conf"] != "http {}\n" {
+		t.Fatalf("live object not replaced by the apply result: %+v", live)
+	}
+}
+
+func TestAppliedDeployment(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("applied")
+	live := r.deploymentForNginxCluster(m, "old")
+	live.Spec.Paused = true
+	live.Spec.Template.Annotations["restartedAt"] = "2025-01-01T00:00:00Z"
+	live.Spec.Template.Spec.Containers = append(live.Spec.Template.Spec.Containers, live.Spec.Template.Spec.Containers[0])
+	live.Spec.Template.Spec.Containers[1].Name = "injected"
+
+	m.Spec.Replicas = 5
+	desired := r.deploymentForNginxCluster(m, "new")
+	applied := appliedDeployment(desired, live)
+	if *applied.Spec.Replicas != 5 || !applied.Spec.Paused {
+		t.Fatalf("expected the spec replicas and the live pause, got %d, %v", *applied.Spec.Replicas, applied.Spec.Paused)
+	}
+	if a := applied.Spec.Template.Annotations; a["config-hash"] != "old" || a["restartedAt"] != "2025-01-01T00:00:00Z" {
+		t.Fatalf("rollout annotations not carried over: %v", a)
+	}
+	if len(applied.Spec.Template.Spec.Containers) != 1 {
+		t.Fatalf("containers set by others are part of the applied template")
+	}
+
+	// A rollback pins the live template
+	delete(live.Annotations, "pod-spec-hash")
+	applied = appliedDeployment(desired, live)
+	if len(applied.Spec.Template.Spec.Containers) != 2 {
+		t.Fatalf("rolled back template not carried over")
+	}
+	if _, ok := applied.Annotations["pod-spec-hash"]; ok {
+		t.Fatalf("pod-spec-hash applied while rolled back")
+	}
+}
+
+func TestUpdateDeploymentWithoutServerSideApply(t *testing.T) {
+	r := &NginxClusterReconciler{Client: deploymentUpdateClient{}, Scheme: testScheme}
+	dep := r.deploymentForNginxCluster(newTestNginxCluster("update"), "hash")
+	replicas := int32(7)
+	if err := r.updateDeployment(context.Background(), dep, dep.DeepCopy(), func(d *appsv1.Deployment) {
+		d.Spec.Replicas = &replicas
+	}); err != nil {
+		t.Fatalf("updateDeployment() error = %v", err)
+	}
+	if *dep.Spec.Replicas != 7 {
+		t.Fatalf("mutate not applied to the live Deployment")
+	}
+}
+
+// deploymentUpdateClient accepts any update
+type deploymentUpdateClient struct {
+	client.Client
+}
+
+func (deploymentUpdateClient) Update(context.Context, client.Object, ...client.UpdateOption) error {
+	return nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// serviceLabelsAnnotation lists the label keys taken from spec.serviceLabels,
+// so labels removed from the spec can be removed from the Service without
+// touching labels added by others.
+const serviceLabelsAnnotation = "nginx.example.com/service-labels"
+
+// applyServiceLabels adds spec.serviceLabels to the Service metadata and
+// records their keys. They are not used in the selector or on the pods.
+func applyServiceLabels(srv *corev1.Service, m *nginxv1.NginxCluster) {
+	if len(m.Spec.ServiceLabels) == 0 {
+		return
+	}
+	if srv.Labels == nil {
+		srv.Labels = map[string]string{}
+	}
+	keys := make([]string, 0, len(m.Spec.ServiceLabels))
+	for k, v := range m.Spec.ServiceLabels {
+		srv.Labels[k] = v
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if srv.Annotations == nil {
+		srv.Annotations = map[string]string{}
+	}
+	srv.Annotations[serviceLabelsAnnotation] = strings.Join(keys, ",")
+}
+
+// syncServiceLabels brings the spec-derived labels of existing in line with
+// desired and reports whether anything changed
+func syncServiceLabels(existing, desired *corev1.Service) bool {
+	changed := false
+	for _, k := range strings.Split(existing.Annotations[serviceLabelsAnnotation], ",") {
+		if _, ok := desired.Labels[k]; !ok && k != "" {
+			if _, ok := existing.Labels[k]; ok {
+				delete(existing.Labels, k)
+				changed = true
+			}
+		}
+	}
+	for k, v := range desired.Labels {
+		if cur, ok := existing.Labels[k]; !ok || cur != v {
+			if existing.Labels == nil {
+				existing.Labels = map[string]string{}
+			}
+			existing.Labels[k] = v
+			changed = true
+		}
+	}
+	if keys := desired.Annotations[serviceLabelsAnnotation]; existing.Annotations[serviceLabelsAnnotation] != keys {
+		if keys == "" {
+			delete(existing.Annotations, serviceLabelsAnnotation)
+		} else {
+			if existing.Annotations == nil {
+				existing.Annotations = map[string]string{}
+			}
+			existing.Annotations[serviceLabelsAnnotation] = keys
+		}
+		changed = true
+	}
+	return changed
+}
+
+// syncServiceSelector sets the selector keys of desired on existing, keeping
+// the keys others added, e.g. for mesh routing, and reports whether anything
+// changed. The kept keys are copied to desired as well, since server-side
+// apply replaces the whole selector.
+func syncServiceSelector(existing, desired *corev1.Service) bool {
+	for k, v := range existing.Spec.Selector {
+		if _, ok := desired.Spec.Selector[k]; !ok {
+			desired.Spec.Selector[k] = v
+		}
+	}
+	changed := false
+	for k, v := range desired.Spec.Selector {
+		if cur, ok := existing.Spec.Selector[k]; !ok || cur != v {
+			if existing.Spec.Selector == nil {
+				existing.Spec.Selector = map[string]string{}
+			}
+			existing.Spec.Selector[k] = v
+			changed = true
+		}
+	}
+	return changed
+}
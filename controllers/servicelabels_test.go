@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestServiceLabels(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("service-labels")
+	existing := r.serviceForNginxCluster(m)
+
+	m.Spec.ServiceLabels = map[string]string{"monitored": "true", "tier": "edge"}
+	desired := r.serviceForNginxCluster(m)
+	if desired.Labels["monitored"] != "true" || desired.Labels["tier"] != "edge" {
+		t.Fatalf("expected the Service labels to be set, got %v", desired.Labels)
+	}
+	if _, ok := desired.Spec.Selector["monitored"]; ok {
+		t.Errorf("Service labels leaked into the selector: %v", desired.Spec.Selector)
+	}
+	if _, ok := r.deploymentForNginxCluster(m, "hash").Spec.Template.Labels["monitored"]; ok {
+		t.Errorf("Service labels leaked into the pod labels")
+	}
+
+	existing.Labels = map[string]string{"added-by": "someone-else"}
+	if !syncServiceLabels(existing, desired) {
+		t.Fatalf("expected new Service labels to be reported as drift")
+	}
+	if existing.Labels["monitored"] != "true" || existing.Labels["added-by"] != "someone-else" {
+		t.Fatalf("unexpected labels after sync: %v", existing.Labels)
+	}
+	if syncServiceLabels(existing, desired) {
+		t.Fatalf("expected no drift once synced")
+	}
+
+	delete(m.Spec.ServiceLabels, "tier")
+	if !syncServiceLabels(existing, r.serviceForNginxCluster(m)) {
+		t.Fatalf("expected a removed Service label to be reported as drift")
+	}
+	if _, ok := existing.Labels["tier"]; ok {
+		t.Errorf("removed label is still set: %v", existing.Labels)
+	}
+	if existing.Labels["added-by"] != "someone-else" {
+		t.Errorf("foreign label was removed: %v", existing.Labels)
+	}
+}
+
+func TestSyncServiceSelector(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("service-selector")
+	existing := r.serviceForNginxCluster(m)
+	existing.Spec.Selector["mesh-route"] = "canary"
+	delete(existing.Spec.Selector, "cluster")
+
+	desired := r.serviceForNginxCluster(m)
+	if !syncServiceSelector(existing, desired) {
+		t.Fatalf("expected a missing selector key to be reported as drift")
+	}
+	want := map[string]string{"app": "nginx", "cluster": m.Name, "mesh-route": "canary"}
+	for _, selector := range []map[string]string{existing.Spec.Selector, desired.Spec.Selector} {
+		if fmt.Sprint(selector) != fmt.Sprint(want) {
+			t.Fatalf("selector %v, want %v", selector, want)
+		}
+	}
+	if syncServiceSelector(existing, r.serviceForNginxCluster(m)) {
+		t.Fatalf("expected the added selector key not to be drift")
+	}
+}
+
+func TestReconcileKeepsAddedServiceSelectorKeys(t *testing.T) {
+	requireEnvtest(t)
+	ctx := context.Background()
+
+	m := newTestNginxCluster("service-selector-merge")
+	createTestNginxCluster(t, m)
+	key := client.ObjectKeyFromObject(m)
+	eventually(t, func() error {
+		srv := &corev1.Service{}
+		if err := k8sClient.Get(ctx, key, srv); err != nil {
+			return err
+		}
+		srv.Spec.Selector["mesh-route"] = "canary"
+		delete(srv.Spec.Selector, "cluster")
+		return k8sClient.Update(ctx, srv)
+	})
+
+	// The operator restores its own key and leaves the added one
+	eventually(t, func() error {
+		srv := &corev1.Service{}
+		if err := k8sClient.Get(ctx, key, srv); err != nil {
+			return err
+		}
+		if srv.Spec.Selector["cluster"] != m.Name {
+			return fmt.Errorf("selector %v lacks the cluster key", srv.Spec.Selector)
+		}
+		if srv.Spec.Selector["mesh-route"] != "canary" {
+			t.Fatalf("added selector key dropped: %v", srv.Spec.Selector)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+const (
+	// serviceProbeTimeout bounds the request of one Service reachability
+	// check, so an unreachable cluster IP doesn't hold up the reconcile
+	serviceProbeTimeout = 2 * time.Second
+	// serviceReachabilityInterval is how often the Service is checked
+	serviceReachabilityInterval = time.Minute
+)
+
+// httpDoer sends HTTP requests, implemented by http.Client
+type httpDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// serviceProbeURL returns the URL of the health check path on the http port
+// of srv's cluster IP, or an empty string for Services without one
+func serviceProbeURL(m *nginxv1.NginxCluster, srv *corev1.Service) string {
+	ip := srv.Spec.ClusterIP
+	if ip == "" || ip == corev1.ClusterIPNone {
+		return ""
+	}
+	port := int32(80)
+	for _, p := range srv.Spec.Ports {
+		if p.Name == "http" {
+			port = p.Port
+		}
+	}
+	path := "/"
+	if hc := m.Spec.HealthCheck; hc != nil && hc.Path != "" {
+		path = hc.Path
+	}
+	return (&url.URL{Scheme: "http", Host: net.JoinHostPort(ip, strconv.Itoa(int(port))), Path: path}).String()
+}
+
+// serviceReachableCondition sends a GET to the Service of m and reports
+// whether nginx answered through it. Server errors and refused connections
+// mean nginx doesn't serve behind the Service. Other failures, such as
+// timeouts, are reported as unknown: they are what an operator running
+// outside the cluster network sees.
+func (r *NginxClusterReconciler) serviceReachableCondition(ctx context.Context, m *nginxv1.NginxCluster) (metav1.Condition, error) {
+	cond := metav1.Condition{
+		Type:               nginxv1.ConditionServiceReachable,
+		Status:             metav1.ConditionUnknown,
+		ObservedGeneration: m.Generation,
+	}
+	srv := &corev1.Service{}
+	err := r.Get(ctx, types.NamespacedName{Name: m.Name, Namespace: workloadNamespace(m)}, srv)
+	if errors.IsNotFound(err) {
+		cond.Reason = "ServiceNotFound"
+		cond.Message = "The Service does not exist yet"
+		return cond, nil
+	} else if err != nil {
+		return cond, err
+	}
+	target := serviceProbeURL(m, srv)
+	if target == "" {
+		cond.Reason = "NoClusterIP"
+		cond.Message = "The Service has no cluster IP to check"
+		return cond, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, serviceProbeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return cond, err
+	}
+	doer := r.httpClient
+	if doer == nil {
+		doer = http.DefaultClient
+	}
+	resp, err := doer.Do(req)
+	switch {
+	case err != nil && stderrors.Is(err, syscall.ECONNREFUSED):
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "ConnectionRefused"
+		cond.Message = fmt.Sprintf("GET %s: connection refused", target)
+	case err != nil:
+		cond.Reason = "CheckFailed"
+		cond.Message = fmt.Sprintf("GET %s failed, the operator may not run in the cluster network: %v", target, err)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		resp.Body.Close()
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "ServerError"
+		cond.Message = fmt.Sprintf("GET %s returned %d", target, resp.StatusCode)
+	default:
+		resp.Body.Close()
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "Responding"
+		cond.Message = fmt.Sprintf("GET %s returned %d", target, resp.StatusCode)
+	}
+	return cond, nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"syscall"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// serviceClient serves srv to every Get
+type serviceClient struct {
+	client.Client
+	srv *corev1.Service
+}
+
+func (c serviceClient) Get(_ context.Context, _ client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	*obj.(*corev1.Service) = *c.srv.DeepCopy()
+	return nil
+}
+
+// stubDoer answers requests with status, or fails them with err
+type stubDoer struct {
+	status int
+	err    error
+	urls   []string
+}
+
+func (d *stubDoer) Do(req *http.Request) (*http.Response, error) {
+	d.urls = append(d.urls, req.URL.String())
+	if d.err != nil {
+		return nil, d.err
+	}
+	return &http.Response{StatusCode: d.status, Body: io.NopCloser(strings.NewReader(""))}, nil
+}
+
+func TestServiceReachableCondition(t *testing.T) {
+	m := newTestNginxCluster("reachable")
+	m.Spec.HealthCheck = &nginxv1.HealthCheckSpec{Path: "/healthz"}
+	srv := (&NginxClusterReconciler{Scheme: testScheme}).serviceForNginxCluster(m)
+	srv.Spec.ClusterIP = "10.96.0.10"
+
+	for _, tc := range []struct {
+		doer   *stubDoer
+		status metav1.ConditionStatus
+		reason string
+	}{
+		{&stubDoer{status: http.StatusOK}, metav1.ConditionTrue, "Responding"},
+		{&stubDoer{status: http.StatusNotFound}, metav1.ConditionTrue, "Responding"},
+		{&stubDoer{status: http.StatusBadGateway}, metav1.ConditionFalse, "ServerError"},
+		{&stubDoer{err: fmt.Errorf("dial tcp: %w", syscall.ECONNREFUSED)}, metav1.ConditionFalse, "ConnectionRefused"},
+		// What an operator outside the cluster network sees
+		{&stubDoer{err: context.DeadlineExceeded}, metav1.ConditionUnknown, "CheckFailed"},
+	} {
+		r := &NginxClusterReconciler{Client: serviceClient{srv: srv}, Scheme: testScheme, httpClient: tc.doer}
+		cond, err := r.serviceReachableCondition(context.Background(), m)
+		if err != nil {
+			t.Fatalf("serviceReachableCondition() error = %v", err)
+		}
+		if cond.Status != tc.status || cond.Reason != tc.reason {
+			t.Errorf("unexpected condition %+v, want %s/%s", cond, tc.status, tc.reason)
+		}
+		if len(tc.doer.urls) != 1 || tc.doer.urls[0] != "http://10.96.0.10:80/healthz" {
+			t.Errorf("unexpected requests %v", tc.doer.urls)
+		}
+	}
+
+	// ExternalName Services have no cluster IP
+	srv.Spec.ClusterIP = ""
+	doer := &stubDoer{status: http.StatusOK}
+	r := &NginxClusterReconciler{Client: serviceClient{srv: srv}, Scheme: testScheme, httpClient: doer}
+	if cond, _ := r.serviceReachableCondition(context.Background(), m); cond.Status != metav1.ConditionUnknown || cond.Reason != "NoClusterIP" || len(doer.urls) != 0 {
+		t.Fatalf("unexpected condition %+v", cond)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// isExternalName reports whether m is a placeholder for an external endpoint
+func isExternalName(m *nginxv1.NginxCluster) bool {
+	return m.Spec.ServiceType == corev1.ServiceTypeExternalName
+}
+
+// workloadReplicas returns the replicas the Deployment of m runs: none while
+// the Service points to an external name, spec.replicas otherwise. The
+// Deployment and its ConfigMap are kept, so switching back rolls out the
+// current config without creating them again.
+func workloadReplicas(m *nginxv1.NginxCluster) int32 {
+	if isExternalName(m) {
+		return 0
+	}
+	return m.Spec.Replicas
+}
+
+// applyServiceType sets the type requested by the spec on srv, ClusterIP by
+// default, the load balancer class of LoadBalancer Services and the name
+// ExternalName Services resolve to
+func applyServiceType(srv *corev1.Service, m *nginxv1.NginxCluster) {
+	srv.Spec.Type = corev1.ServiceTypeClusterIP
+	if m.Spec.ServiceType != "" {
+		srv.Spec.Type = m.Spec.ServiceType
+	}
+	if class := m.Spec.LoadBalancerClass; class != nil && srv.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		c := *class
+		srv.Spec.LoadBalancerClass = &c
+	}
+	if srv.Spec.Type == corev1.ServiceTypeExternalName {
+		srv.Spec.ExternalName = m.Spec.ExternalName
+	}
+}
+
+// syncServiceType brings the type and load balancer class of existing in
+// line with desired and reports whether anything changed. Services of
+// clusters that don't set spec.serviceType keep their type, which may have
+// been changed by hand. The API server drops fields that don't apply to the
+// new type, such as node ports.
+func syncServiceType(existing, desired *corev1.Service, m *nginxv1.NginxCluster) bool {
+	if m.Spec.ServiceType == "" {
+		return false
+	}
+	changed := false
+	if existing.Spec.Type != desired.Spec.Type {
+		existing.Spec.Type = desired.Spec.Type
+		changed = true
+	}
+	if !equalStringPtr(existing.Spec.LoadBalancerClass, desired.Spec.LoadBalancerClass) {
+		existing.Spec.LoadBalancerClass = desired.Spec.LoadBalancerClass
+		changed = true
+	}
+	if existing.Spec.ExternalName != desired.Spec.ExternalName {
+		existing.Spec.ExternalName = desired.Spec.ExternalName
+		changed = true
+	}
+	return changed
+}
+
+// loadBalancerClassChanged reports whether the load balancer of existing has
+// another class than desired asks for. The class of a LoadBalancer Service
+// is immutable, so only recreating the Service changes it.
+func loadBalancerClassChanged(existing, desired *corev1.Service) bool {
+	return existing.Spec.Type == corev1.ServiceTypeLoadBalancer && desired.Spec.Type == corev1.ServiceTypeLoadBalancer &&
+		!equalStringPtr(existing.Spec.LoadBalancerClass, desired.Spec.LoadBalancerClass)
+}
+
+func equalStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// withNodePorts returns desired with the node ports allocated to the ports of
+// the same name in existing, so updating the ports doesn't move them
+func withNodePorts(desired, existing []corev1.ServicePort) []corev1.ServicePort {
+	ports := append([]corev1.ServicePort(nil), desired...)
+	for i := range ports {
+		for _, cur := range existing {
+			if cur.Name == ports[i].Name && ports[i].NodePort == 0 {
+				ports[i].NodePort = cur.NodePort
+			}
+		}
+	}
+	return ports
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestServiceType(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("service-type")
+	live := r.serviceForNginxCluster(m)
+	if live.Spec.Type != corev1.ServiceTypeClusterIP || live.Spec.LoadBalancerClass != nil {
+		t.Fatalf("unexpected default Service spec %+v", live.Spec)
+	}
+	// A type changed by hand is kept while the spec leaves it unset
+	live.Spec.Type = corev1.ServiceTypeNodePort
+	if syncServiceType(live, r.serviceForNginxCluster(m), m) {
+		t.Fatalf("type changed without spec.serviceType")
+	}
+
+	metalLB := "metallb.universe.tf/metallb"
+	m.Spec.ServiceType = corev1.ServiceTypeLoadBalancer
+	m.Spec.LoadBalancerClass = &metalLB
+	desired := r.serviceForNginxCluster(m)
+	if !syncServiceType(live, desired, m) || live.Spec.Type != corev1.ServiceTypeLoadBalancer || *live.Spec.LoadBalancerClass != metalLB {
+		t.Fatalf("type and class not synced: %+v", live.Spec)
+	}
+	if loadBalancerClassChanged(live, desired) {
+		t.Fatalf("unchanged class reported as changed")
+	}
+
+	cloud := "example.com/cloud"
+	m.Spec.LoadBalancerClass = &cloud
+	if !loadBalancerClassChanged(live, r.serviceForNginxCluster(m)) {
+		t.Fatalf("expected the class change to require a new Service")
+	}
+}
+
+func TestValidateServiceType(t *testing.T) {
+	m := newTestNginxCluster("service-type")
+	class := "example.com/cloud"
+	m.Spec.LoadBalancerClass = &class
+	if err := validateSpec(m); err == nil {
+		t.Fatalf("expected a class without LoadBalancer type to be rejected")
+	}
+	m.Spec.ServiceType = corev1.ServiceTypeLoadBalancer
+	if err := validateSpec(m); err != nil {
+		t.Fatalf("validateSpec() error = %v", err)
+	}
+}
+
+func TestExternalNameService(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("external-name")
+	family := corev1.IPv4Protocol
+	m.Spec.IPFamilies = []corev1.IPFamily{family}
+	live := r.serviceForNginxCluster(m)
+
+	m.Spec.ServiceType = corev1.ServiceTypeExternalName
+	if err := validateSpec(m); err == nil {
+		t.Fatalf("expected ExternalName without a name to be rejected")
+	}
+	m.Spec.ExternalName = "nginx.legacy.example.com"
+	if err := validateSpec(m); err != nil {
+		t.Fatalf("validateSpec() error = %v", err)
+	}
+	desired := r.serviceForNginxCluster(m)
+	if desired.Spec.ExternalName != m.Spec.ExternalName || len(desired.Spec.IPFamilies) != 0 {
+		t.Fatalf("unexpected ExternalName Service spec %+v", desired.Spec)
+	}
+	if !syncServiceType(live, desired, m) || live.Spec.Type != corev1.ServiceTypeExternalName || live.Spec.ExternalName != m.Spec.ExternalName {
+		t.Fatalf("type and name not synced: %+v", live.Spec)
+	}
+
+	dep := r.deploymentForNginxCluster(m, "hash")
+	if *dep.Spec.Replicas != 0 {
+		t.Fatalf("Deployment of an ExternalName cluster has %d replicas, want 0", *dep.Spec.Replicas)
+	}
+
+	// Switching back clears the name
+	m.Spec.ServiceType = corev1.ServiceTypeClusterIP
+	m.Spec.ExternalName = ""
+	if !syncServiceType(live, r.serviceForNginxCluster(m), m) || live.Spec.ExternalName != "" {
+		t.Fatalf("external name kept: %+v", live.Spec)
+	}
+}
+
+func TestWithNodePorts(t *testing.T) {
+	existing := []corev1.ServicePort{{Name: "http", Port: 8080, NodePort: 30080}}
+	ports := withNodePorts([]corev1.ServicePort{{Name: "http", Port: 80}, {Name: "http3", Port: 443}}, existing)
+	if ports[0].NodePort != 30080 || ports[1].NodePort != 0 {
+		t.Fatalf("unexpected node ports %+v", ports)
+	}
+}
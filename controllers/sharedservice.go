@@ -0,0 +1,191 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// sharedServiceLabel marks pods that sit behind a shared Service. Its value is
+// the name of that Service.
+const sharedServiceLabel = "nginx.example.com/shared-service"
+
+// reconcileSharedService makes the cluster a member of its shared Service. The
+// Service is created on first use and carries a non-controller owner reference
+// for every participating cluster, so it is garbage collected once the last one
+// is gone. Any per-cluster Service left over from before is removed.
+//
+// Without owner references, membership is not tracked and the shared Service
+// is left in place when clusters leave it.
+func (r *NginxClusterReconciler) reconcileSharedService(ctx context.Context, m *nginxv1.NginxCluster) error {
+	logger := log.FromContext(ctx)
+	name := m.Spec.SharedServiceName
+
+	if name != m.Name {
+		own := &corev1.Service{}
+		err := r.Get(ctx, types.NamespacedName{Name: m.Name, Namespace: workloadNamespace(m)}, own)
+		if err == nil && r.isOwnedBy(own, m) {
+			logger.Info("Deleting per-cluster Service replaced by shared Service", "Service.Namespace", own.Namespace, "Service.Name", own.Name)
+			if err := r.Delete(ctx, own); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		} else if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	desired := sharedServiceForNginxCluster(m)
+	service := &corev1.Service{}
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: workloadNamespace(m)}, service)
+	if err != nil && errors.IsNotFound(err) {
+		if r.usesOwnerReferences(m) {
+			if err := controllerutil.SetOwnerReference(m, desired, r.Scheme); err != nil {
+				return err
+			}
+		}
+		logger.Info("Creating a new shared Service", "Service.Namespace", desired.Namespace, "Service.Name", desired.Name)
+		return r.createObject(ctx, desired)
+	} else if err != nil {
+		return err
+	}
+
+	if owner := metav1.GetControllerOf(service); owner != nil {
+		return fmt.Errorf("service %s/%s is controlled by %s %s and cannot be shared", service.Namespace, service.Name, owner.Kind, owner.Name)
+	}
+
+	joinOwners := r.usesOwnerReferences(m) && !hasOwnerReference(service, m)
+	if reflect.DeepEqual(service.Spec.Selector, desired.Spec.Selector) && sameServicePorts(service.Spec.Ports, desired.Spec.Ports) && !joinOwners {
+		return nil
+	}
+	logger.Info("Updating shared Service", "Service.Namespace", service.Namespace, "Service.Name", service.Name)
+	// An apply sets the owner references of all participants, not only m's
+	desired.OwnerReferences = service.OwnerReferences
+	if r.usesOwnerReferences(m) {
+		if err := controllerutil.SetOwnerReference(m, desired, r.Scheme); err != nil {
+			return err
+		}
+	}
+	var ownerErr error
+	err = r.updateObject(ctx, service, desired, func() {
+		service.Spec.Selector = desired.Spec.Selector
+		service.Spec.Ports = desired.Spec.Ports
+		if r.usesOwnerReferences(m) {
+			ownerErr = controllerutil.SetOwnerReference(m, service, r.Scheme)
+		}
+	})
+	if ownerErr != nil {
+		return ownerErr
+	}
+	return err
+}
+
+// releaseSharedServices removes the cluster's owner reference from shared
+// Services it no longer participates in.
+func (r *NginxClusterReconciler) releaseSharedServices(ctx context.Context, m *nginxv1.NginxCluster) error {
+	logger := log.FromContext(ctx)
+
+	services := &corev1.ServiceList{}
+	if err := r.List(ctx, services, client.InNamespace(workloadNamespace(m))); err != nil {
+		return err
+	}
+	for i := range services.Items {
+		service := &services.Items[i]
+		if service.Name == m.Spec.SharedServiceName || r.isOwnedBy(service, m) || !hasOwnerReference(service, m) {
+			continue
+		}
+		logger.Info("Leaving shared Service", "Service.Namespace", service.Namespace, "Service.Name", service.Name)
+		removeOwnerReference(service, m)
+		// Delete the Service once the last participant is gone
+		if len(service.OwnerReferences) == 0 {
+			if err := r.Delete(ctx, service); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			continue
+		}
+		if err := r.updateWithRetry(ctx, service, func() {
+			removeOwnerReference(service, m)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sharedServiceForNginxCluster returns the shared Service a cluster joins
+func sharedServiceForNginxCluster(m *nginxv1.NginxCluster) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      m.Spec.SharedServiceName,
+			Namespace: workloadNamespace(m),
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{
+				"app":              "nginx",
+				sharedServiceLabel: m.Spec.SharedServiceName,
+			},
+			Ports: servicePortsForNginxCluster(m),
+			Type:  corev1.ServiceTypeClusterIP,
+		},
+	}
+}
+
+// hasOwnerReference reports whether obj lists m among its owners
+func hasOwnerReference(obj metav1.Object, m *nginxv1.NginxCluster) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == m.UID {
+			return true
+		}
+	}
+	return false
+}
+
+// removeOwnerReference drops m from the owners of obj
+func removeOwnerReference(obj metav1.Object, m *nginxv1.NginxCluster) {
+	var refs []metav1.OwnerReference
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID != m.UID {
+			refs = append(refs, ref)
+		}
+	}
+	obj.SetOwnerReferences(refs)
+}
+
+// sameServicePorts compares the ports the operator manages, ignoring fields
+// such as NodePort and TargetPort that the API server fills in.
+func sameServicePorts(current, desired []corev1.ServicePort) bool {
+	if len(current) != len(desired) {
+		return false
+	}
+	for i := range desired {
+		if current[i].Name != desired[i].Name || current[i].Port != desired[i].Port || current[i].Protocol != desired[i].Protocol {
+			return false
+		}
+	}
+	return true
+}
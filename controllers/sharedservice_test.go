@@ -0,0 +1,133 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func TestSharedServiceSelectsParticipatingPods(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+
+	blue := newTestNginxCluster("blue")
+	blue.Spec.SharedServiceName = "web"
+	green := newTestNginxCluster("green")
+	green.Spec.SharedServiceName = "web"
+	other := newTestNginxCluster("other")
+
+	service := sharedServiceForNginxCluster(blue)
+	if service.Name != "web" {
+		t.Fatalf("shared Service name = %q, want web", service.Name)
+	}
+	selector := labels.SelectorFromSet(service.Spec.Selector)
+
+	for _, m := range []struct {
+		name  string
+		ok    bool
+		podOf func() map[string]string
+	}{
+		{"blue", true, func() map[string]string { return r.deploymentForNginxCluster(blue, "h").Spec.Template.Labels }},
+		{"green", true, func() map[string]string { return r.deploymentForNginxCluster(green, "h").Spec.Template.Labels }},
+		{"other", false, func() map[string]string { return r.deploymentForNginxCluster(other, "h").Spec.Template.Labels }},
+	} {
+		if got := selector.Matches(labels.Set(m.podOf())); got != m.ok {
+			t.Errorf("shared selector matches %s pods = %v, want %v", m.name, got, m.ok)
+		}
+	}
+
+	// The Deployment selector must stay on the immutable per-cluster labels
+	dep := r.deploymentForNginxCluster(blue, "h")
+	if _, ok := dep.Spec.Selector.MatchLabels[sharedServiceLabel]; ok {
+		t.Fatalf("Deployment selector must not include %s", sharedServiceLabel)
+	}
+}
+
+func TestSharedServiceNameCannotBeClusterName(t *testing.T) {
+	requireEnvtest(t)
+
+	// The per-cluster Service already has the name and is controller-owned
+	m := newTestNginxCluster("shared-own-name")
+	m.Spec.SharedServiceName = m.Name
+	if err := k8sClient.Create(context.Background(), m); !apierrors.IsInvalid(err) {
+		t.Fatalf("expected the cluster's own name to be rejected, got %v", err)
+	}
+}
+
+// sharedServiceClient serves a single Service and records the objects
+// applied to it
+type sharedServiceClient struct {
+	applyRecordingClient
+	service *corev1.Service
+	applied []*corev1.Service
+}
+
+func (c *sharedServiceClient) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	if key.Name != c.service.Name {
+		return apierrors.NewNotFound(schema.GroupResource{Resource: "services"}, key.Name)
+	}
+	c.service.DeepCopyInto(obj.(*corev1.Service))
+	return nil
+}
+
+func (c *sharedServiceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	c.applied = append(c.applied, obj.DeepCopyObject().(*corev1.Service))
+	return c.applyRecordingClient.Patch(ctx, obj, patch, opts...)
+}
+
+func TestSharedServiceServerSideApply(t *testing.T) {
+	blue := newTestNginxCluster("blue")
+	blue.UID = types.UID("blue-uid")
+	blue.Spec.SharedServiceName = "web"
+	green := blue.DeepCopy()
+	green.Name, green.UID = "green", types.UID("green-uid")
+
+	service := sharedServiceForNginxCluster(blue)
+	if err := controllerutil.SetOwnerReference(blue, service, testScheme); err != nil {
+		t.Fatal(err)
+	}
+	c := &sharedServiceClient{service: service}
+	r := &NginxClusterReconciler{Client: c, Scheme: testScheme, UseServerSideApply: true}
+
+	// Joining applies the owner references of all participants
+	if err := r.reconcileSharedService(context.Background(), green); err != nil {
+		t.Fatalf("reconcileSharedService() error = %v", err)
+	}
+	if len(c.applied) != 1 || c.patches[0].Type() != client.Apply.Type() {
+		t.Fatalf("expected a single apply, got %d", len(c.applied))
+	}
+	if applied := c.applied[0]; !hasOwnerReference(applied, blue) || !hasOwnerReference(applied, green) {
+		t.Fatalf("applied owner references %v, want blue and green", applied.OwnerReferences)
+	}
+
+	// A new shared Service is applied too
+	c.service = &corev1.Service{}
+	if err := r.reconcileSharedService(context.Background(), green); err != nil {
+		t.Fatalf("reconcileSharedService() error = %v", err)
+	}
+	if len(c.applied) != 2 || c.applied[1].Name != "web" || !hasOwnerReference(c.applied[1], green) {
+		t.Fatalf("shared Service not applied on creation: %+v", c.applied)
+	}
+}
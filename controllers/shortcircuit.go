@@ -0,0 +1,127 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// dirtyClusters records the clusters whose managed or referenced resources
+// changed since their last full reconcile. Only events on the NginxCluster
+// itself leave a cluster clean, so skipping converged clusters never hides
+// drift in the resources: those events, including the ones replayed by the
+// periodic resync, always lead to a full reconcile.
+type dirtyClusters struct {
+	keys sync.Map
+}
+
+func (d *dirtyClusters) mark(key types.NamespacedName) {
+	d.keys.Store(key, struct{}{})
+}
+
+// take reports whether key was marked and clears the mark
+func (d *dirtyClusters) take(key types.NamespacedName) bool {
+	_, ok := d.keys.LoadAndDelete(key)
+	return ok
+}
+
+// isConverged reports whether the last full reconcile of m already acted on
+// its current spec and saw the cluster fully rolled out. The hash of an inline
+// config is compared directly; a referenced ConfigMap, config template or
+// config dependency marks the cluster dirty when it changes. With the config
+// reloader, or without restarts on config changes, the hash in status leaves
+// the inline config out, so such clusters are always reconciled, and so are
+// clusters without a managed workload, as their Deployment is not watched,
+// and those whose validation sidecars are polled.
+func isConverged(m *nginxv1.NginxCluster) bool {
+	if reloadsConfig(m) || !manageWorkload(m) || m.Spec.ValidationSidecar {
+		return false
+	}
+	if m.Status.ObservedGeneration != m.Generation {
+		return false
+	}
+	if replicas := workloadReplicas(m); m.Status.Replicas != replicas || m.Status.ReadyReplicas != replicas {
+		return false
+	}
+	if m.Spec.NginxConfFrom == nil && m.Spec.ConfigTemplateFrom == nil && len(m.Spec.ConfigDependencies) == 0 && m.Status.ConfigHash != withNjsScripts(m, withErrorPages(m, calculateConfigHash(effectiveNginxConf(m)))) {
+		return false
+	}
+	return true
+}
+
+// markDirty wraps h so that every request it enqueues also marks the cluster
+// dirty
+func (r *NginxClusterReconciler) markDirty(h handler.EventHandler) handler.EventHandler {
+	return dirtyMarkingHandler{EventHandler: h, dirty: &r.dirty}
+}
+
+type dirtyMarkingHandler struct {
+	handler.EventHandler
+	dirty *dirtyClusters
+}
+
+func (h dirtyMarkingHandler) Create(ctx context.Context, e event.CreateEvent, q workqueue.RateLimitingInterface) {
+	h.EventHandler.Create(ctx, e, dirtyMarkingQueue{q, h.dirty})
+}
+
+func (h dirtyMarkingHandler) Update(ctx context.Context, e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	h.EventHandler.Update(ctx, e, dirtyMarkingQueue{q, h.dirty})
+}
+
+func (h dirtyMarkingHandler) Delete(ctx context.Context, e event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	h.EventHandler.Delete(ctx, e, dirtyMarkingQueue{q, h.dirty})
+}
+
+func (h dirtyMarkingHandler) Generic(ctx context.Context, e event.GenericEvent, q workqueue.RateLimitingInterface) {
+	h.EventHandler.Generic(ctx, e, dirtyMarkingQueue{q, h.dirty})
+}
+
+type dirtyMarkingQueue struct {
+	workqueue.RateLimitingInterface
+	dirty *dirtyClusters
+}
+
+func (q dirtyMarkingQueue) markItem(item interface{}) {
+	if req, ok := item.(reconcile.Request); ok {
+		q.dirty.mark(req.NamespacedName)
+	}
+}
+
+func (q dirtyMarkingQueue) Add(item interface{}) {
+	q.markItem(item)
+	q.RateLimitingInterface.Add(item)
+}
+
+func (q dirtyMarkingQueue) AddAfter(item interface{}, duration time.Duration) {
+	q.markItem(item)
+	q.RateLimitingInterface.AddAfter(item, duration)
+}
+
+func (q dirtyMarkingQueue) AddRateLimited(item interface{}) {
+	q.markItem(item)
+	q.RateLimitingInterface.AddRateLimited(item)
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// convergedTestNginxCluster returns a cluster whose status reflects a
+// completed reconcile of its spec
+func convergedTestNginxCluster(name string) *nginxv1.NginxCluster {
+	m := newTestNginxCluster(name)
+	m.Generation = 3
+	m.Status.ObservedGeneration = 3
+	m.Status.Replicas = m.Spec.Replicas
+	m.Status.ReadyReplicas = m.Spec.Replicas
+	m.Status.ConfigHash = calculateConfigHash(effectiveNginxConf(m))
+	return m
+}
+
+func TestIsConverged(t *testing.T) {
+	if !isConverged(convergedTestNginxCluster("converged")) {
+		t.Fatalf("expected a fully reconciled cluster to be converged")
+	}
+
+	for name, mutate := range map[string]func(m *nginxv1.NginxCluster){
+		"new generation":  func(m *nginxv1.NginxCluster) { m.Generation++ },
+		"pods not ready":  func(m *nginxv1.NginxCluster) { m.Status.ReadyReplicas-- },
+		"scaling":         func(m *nginxv1.NginxCluster) { m.Spec.Replicas++ },
+		"config changed":  func(m *nginxv1.NginxCluster) { m.Status.ConfigHash = "stale" },
+		"config reloader": func(m *nginxv1.NginxCluster) { m.Spec.ConfigReloaderSidecar = true },
+		"no restart on config change": func(m *nginxv1.NginxCluster) {
+			disabled := false
+			m.Spec.RestartOnConfigChange = &disabled
+		},
+	} {
+		m := convergedTestNginxCluster(name)
+		mutate(m)
+		if isConverged(m) {
+			t.Errorf("%s: expected the cluster not to be converged", name)
+		}
+	}
+}
+
+func TestDirtyMarkingHandler(t *testing.T) {
+	r := &NginxClusterReconciler{}
+	key := types.NamespacedName{Namespace: "default", Name: "web"}
+	h := r.markDirty(handler.EnqueueRequestsFromMapFunc(func(context.Context, client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: key}}
+	}))
+
+	q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer q.ShutDown()
+	h.Update(context.Background(), event.UpdateEvent{ObjectOld: newTestNginxCluster("a"), ObjectNew: newTestNginxCluster("a")}, q)
+
+	if q.Len() != 1 {
+		t.Fatalf("expected the request to be enqueued, queue has %d items", q.Len())
+	}
+	if !r.dirty.take(key) {
+		t.Fatalf("expected the cluster to be marked dirty")
+	}
+	if r.dirty.take(key) {
+		t.Fatalf("expected take to clear the mark")
+	}
+}
+
+// pinnedClusterClient serves a fixed NginxCluster and discards status
+// writes, so the benchmark is not disturbed by the manager reconciling the
+// same cluster. Everything else goes to the API server.
+type pinnedClusterClient struct {
+	client.Client
+	cluster *nginxv1.NginxCluster
+}
+
+func (c pinnedClusterClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if m, ok := obj.(*nginxv1.NginxCluster); ok && key == client.ObjectKeyFromObject(c.cluster) {
+		c.cluster.DeepCopyInto(m)
+		return nil
+	}
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c pinnedClusterClient) Status() client.SubResourceWriter {
+	return discardStatusWriter{}
+}
+
+type discardStatusWriter struct {
+	client.SubResourceWriter
+}
+
+func (discardStatusWriter) Update(context.Context, client.Object, ...client.SubResourceUpdateOption) error {
+	return nil
+}
+
+// BenchmarkReconcile compares a reconcile of a converged cluster, which
+// returns after reading the NginxCluster, with a full reconcile triggered by a
+// change to one of its resources. Resources are read from the API server
+// directly, so each skipped read is a saved round trip.
+func BenchmarkReconcile(b *testing.B) {
+	if testEnv == nil {
+		b.Skip("KUBEBUILDER_ASSETS not set, skipping envtest-based benchmark")
+	}
+	ctx := context.Background()
+
+	m := newTestNginxCluster("bench-reconcile")
+	if err := k8sClient.Create(ctx, m); err != nil {
+		b.Fatalf("failed to create NginxCluster: %v", err)
+	}
+	b.Cleanup(func() { _ = k8sClient.Delete(context.Background(), m) })
+	key := client.ObjectKeyFromObject(m)
+
+	// Wait for the manager to create the resources
+	deadline := time.Now().Add(testTimeout)
+	for {
+		if err := k8sClient.Get(ctx, key, m); err != nil {
+			b.Fatalf("failed to get NginxCluster: %v", err)
+		}
+		if m.Status.ObservedGeneration == m.Generation {
+			break
+		}
+		if time.Now().After(deadline) {
+			b.Fatalf("NginxCluster was not reconciled within %s", testTimeout)
+		}
+		time.Sleep(testInterval)
+	}
+	// envtest runs no pods, so report them ready by hand
+	m.Status.Replicas = m.Spec.Replicas
+	m.Status.ReadyReplicas = m.Spec.Replicas
+	if !isConverged(m) {
+		b.Fatalf("cluster did not converge: %+v", m.Status)
+	}
+
+	r := &NginxClusterReconciler{Client: pinnedClusterClient{Client: k8sClient, cluster: m}, Scheme: testScheme}
+	req := ctrl.Request{NamespacedName: key}
+
+	b.Run("converged", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("full", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			r.dirty.mark(key)
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// validateSpec applies the rules of the validating webhook, in case it is
+// not deployed. Retrying won't help until the spec is fixed.
+func validateSpec(m *nginxv1.NginxCluster) error {
+	return nginxv1.ValidateSpec(m).ToAggregate()
+}
+
+// reportInvalidSpec marks m as not ready because its spec was rejected, with
+// a Warning event when the error changes. m isn't requeued, retrying won't
+// help until the spec is fixed.
+func (r *NginxClusterReconciler) reportInvalidSpec(ctx context.Context, m *nginxv1.NginxCluster, err error) (ctrl.Result, error) {
+	ready := metav1.Condition{
+		Type:               nginxv1.ConditionReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             "InvalidSpec",
+		Message:            err.Error(),
+		ObservedGeneration: m.Generation,
+	}
+	if cond := meta.FindStatusCondition(m.Status.Conditions, nginxv1.ConditionReady); cond == nil || cond.Message != ready.Message {
+		r.recordEvent(m, corev1.EventTypeWarning, ready.Reason, ready.Message)
+	}
+	return ctrl.Result{}, r.updateStatusWithRetry(ctx, m, func() {
+		meta.SetStatusCondition(&m.Status.Conditions, ready)
+	})
+}
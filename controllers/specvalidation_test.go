@@ -0,0 +1,72 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+func TestValidateSpec(t *testing.T) {
+	m := newTestNginxCluster("validate-spec")
+	if err := validateSpec(m); err != nil {
+		t.Fatalf("unexpected error for a valid spec: %v", err)
+	}
+
+	// The rules of the webhook apply, all failing ones are reported
+	m.Spec.LogFormat = &nginxv1.LogFormatSpec{Name: "combined", Preset: "json"}
+	m.Spec.SharedServiceName = m.Name
+	err := validateSpec(m)
+	for _, path := range []string{"spec.logFormat.name", "spec.sharedServiceName"} {
+		if err == nil || !strings.Contains(err.Error(), path) {
+			t.Errorf("expected %s to be rejected, got %v", path, err)
+		}
+	}
+}
+
+func TestReportInvalidSpec(t *testing.T) {
+	stored := newTestNginxCluster("invalid-spec")
+	recorder := record.NewFakeRecorder(10)
+	r := &NginxClusterReconciler{Client: &conflictingClient{stored: stored}, Scheme: testScheme, Recorder: recorder}
+	m := stored.DeepCopy()
+	m.Spec.LogFormat = &nginxv1.LogFormatSpec{Name: "combined", Preset: "json"}
+	specErr := validateSpec(m)
+
+	for i := 0; i < 2; i++ {
+		if result, err := r.reportInvalidSpec(context.Background(), m, specErr); err != nil || result.Requeue || result.RequeueAfter != 0 {
+			t.Fatalf("reportInvalidSpec() = %+v, %v", result, err)
+		}
+	}
+	cond := meta.FindStatusCondition(stored.Status.Conditions, nginxv1.ConditionReady)
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != "InvalidSpec" || cond.Message != specErr.Error() {
+		t.Fatalf("unexpected Ready condition %+v", cond)
+	}
+	// The event isn't repeated while the error stays the same
+	if len(recorder.Events) != 1 {
+		t.Fatalf("expected a single event, got %d", len(recorder.Events))
+	}
+	if event := <-recorder.Events; !strings.HasPrefix(event, "Warning InvalidSpec ") {
+		t.Fatalf("unexpected event %q", event)
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// These tests use a real API server and etcd provided by envtest. The control
+// plane binaries are located through KUBEBUILDER_ASSETS, which `make test`
+// sets up; when it is unset, only the tests that don't need a cluster run.
+
+const (
+	testTimeout  = 10 * time.Second
+	testInterval = 250 * time.Millisecond
+)
+
+var (
+	testScheme = runtime.NewScheme()
+	k8sClient  client.Client
+	testEnv    *envtest.Environment
+)
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(testScheme))
+	utilruntime.Must(nginxv1.AddToScheme(testScheme))
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(runTests(m))
+}
+
+func runTests(m *testing.M) int {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		return m.Run()
+	}
+
+	logf.SetLogger(zap.New(zap.WriteTo(os.Stderr), zap.UseDevMode(true)))
+
+	testEnv = &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "config", "crd", "bases")},
+		ErrorIfCRDPathMissing: true,
+	}
+
+	cfg, err := testEnv.Start()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start test environment: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := testEnv.Stop(); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to stop test environment: %v\n", err)
+		}
+	}()
+
+	k8sClient, err = client.New(cfg, client.Options{Scheme: testScheme})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create client: %v\n", err)
+		return 1
+	}
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:  testScheme,
+		Metrics: metricsserver.Options{BindAddress: "0"},
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create manager: %v\n", err)
+		return 1
+	}
+
+	if err := (&NginxClusterReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("nginxcluster-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to set up reconciler: %v\n", err)
+		return 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mgrDone := make(chan error, 1)
+	go func() {
+		mgrDone <- mgr.Start(ctx)
+	}()
+
+	code := m.Run()
+
+	cancel()
+	if err := <-mgrDone; err != nil {
+		fmt.Fprintf(os.Stderr, "manager exited with error: %v\n", err)
+	}
+	return code
+}
+
+// requireEnvtest skips the calling test when no control plane is available.
+func requireEnvtest(t *testing.T) {
+	t.Helper()
+	if testEnv == nil {
+		t.Skip("KUBEBUILDER_ASSETS not set, skipping envtest-based test")
+	}
+}
+
+// eventually polls cond until it returns nil or the test timeout elapses.
+func eventually(t *testing.T, cond func() error) {
+	t.Helper()
+	var lastErr error
+	err := wait.PollUntilContextTimeout(context.Background(), testInterval, testTimeout, true,
+		func(ctx context.Context) (bool, error) {
+			lastErr = cond()
+			return lastErr == nil, nil
+		})
+	if err != nil {
+		t.Fatalf("condition not met within %s: %v", testTimeout, lastErr)
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+const (
+	// upstreamDNSTimeout bounds the lookups of one preflight, which is best
+	// effort: hosts not answered in time count as resolvable
+	upstreamDNSTimeout = 2 * time.Second
+	// upstreamDNSRetryInterval is how often a held rollout checks again, as
+	// the missing Services may still be created
+	upstreamDNSRetryInterval = 30 * time.Second
+)
+
+// hostResolver looks up host names, implemented by net.Resolver
+type hostResolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// proxyPassPattern matches a proxy_pass directive, capturing its URL
+var proxyPassPattern = regexp.MustCompile(`(?m)^[ \t]*proxy_pass\s+([^\s;]+)\s*;`)
+
+// upstreamBlockPattern matches the opening of an upstream block, capturing
+// its name
+var upstreamBlockPattern = regexp.MustCompile(`(?m)^[ \t]*upstream\s+([^\s{]+)\s*\{`)
+
+// upstreamServerPattern matches a server directive of an upstream block,
+// capturing its address. Server blocks don't match as they have no address.
+var upstreamServerPattern = regexp.MustCompile(`(?m)^[ \t]*server\s+([^\s;{]+)[^;{]*;`)
+
+// upstreamHosts returns the host names nginx resolves when loading conf: the
+// hosts of proxy_pass URLs and of upstream servers. Names of upstream
+// blocks, addresses, unix sockets and hosts set through variables are left
+// out, as nginx doesn't resolve them at startup.
+func upstreamHosts(conf string) []string {
+	blocks := map[string]bool{}
+	for _, match := range upstreamBlockPattern.FindAllStringSubmatch(conf, -1) {
+		blocks[match[1]] = true
+	}
+	var addresses []string
+	for _, match := range proxyPassPattern.FindAllStringSubmatch(conf, -1) {
+		address := match[1]
+		if i := strings.Index(address, "://"); i >= 0 {
+			address = address[i+3:]
+		}
+		if i := strings.Index(address, "/"); i >= 0 {
+			address = address[:i]
+		}
+		addresses = append(addresses, address)
+	}
+	for _, match := range upstreamServerPattern.FindAllStringSubmatch(conf, -1) {
+		addresses = append(addresses, match[1])
+	}
+
+	seen := map[string]bool{}
+	var hosts []string
+	for _, address := range addresses {
+		if strings.HasPrefix(address, "unix:") || strings.Contains(address, "$") {
+			continue
+		}
+		host := address
+		if h, _, err := net.SplitHostPort(address); err == nil {
+			host = h
+		}
+		host = strings.Trim(host, "[]")
+		if host == "" || blocks[host] || net.ParseIP(host) != nil || seen[host] {
+			continue
+		}
+		seen[host] = true
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// unresolvableUpstreams returns the upstream hosts of conf that don't
+// resolve from the operator. Single-label names are looked up in the
+// namespace of the pods, as their search path would. Only definite
+// NXDOMAIN answers count: timeouts and other failures are ignored.
+func unresolvableUpstreams(ctx context.Context, resolver hostResolver, m *nginxv1.NginxCluster, conf string) []string {
+	hosts := upstreamHosts(conf)
+	if len(hosts) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, upstreamDNSTimeout)
+	defer cancel()
+	var unresolvable []string
+	for _, host := range hosts {
+		name := host
+		if !strings.Contains(name, ".") {
+			name += "." + workloadNamespace(m)
+		}
+		_, err := resolver.LookupHost(ctx, name)
+		var dnsErr *net.DNSError
+		if stderrors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			unresolvable = append(unresolvable, host)
+		}
+	}
+	return unresolvable
+}
+
+// unresolvableUpstreamsCondition reports upstream hosts that don't resolve
+func unresolvableUpstreamsCondition(m *nginxv1.NginxCluster, hosts []string) metav1.Condition {
+	return metav1.Condition{
+		Type:               nginxv1.ConditionDegraded,
+		Status:             metav1.ConditionTrue,
+		Reason:             "UnresolvableUpstreams",
+		Message:            fmt.Sprintf("Rollout held, nginx would fail to start: upstream hosts %s don't resolve", strings.Join(hosts, ", ")),
+		ObservedGeneration: m.Generation,
+	}
+}
+
+// holdForUnresolvableUpstreams runs the upstream DNS preflight on conf when
+// it is enabled. If a host doesn't resolve, it sets the Degraded condition
+// and reports that the running config has to be kept.
+func (r *NginxClusterReconciler) holdForUnresolvableUpstreams(ctx context.Context, m *nginxv1.NginxCluster, conf string) (bool, error) {
+	if !r.UpstreamDNSPreflight {
+		return false, nil
+	}
+	resolver := r.resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	hosts := unresolvableUpstreams(ctx, resolver, m, conf)
+	if len(hosts) == 0 {
+		return false, nil
+	}
+	degraded := unresolvableUpstreamsCondition(m, hosts)
+	if cond := meta.FindStatusCondition(m.Status.Conditions, nginxv1.ConditionDegraded); cond == nil || cond.Message != degraded.Message {
+		r.recordEvent(m, corev1.EventTypeWarning, degraded.Reason, degraded.Message)
+	}
+	return true, r.updateStatusWithRetry(ctx, m, func() {
+		meta.SetStatusCondition(&m.Status.Conditions, degraded)
+	})
+}
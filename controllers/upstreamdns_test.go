@@ -0,0 +1,105 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUpstreamHosts(t *testing.T) {
+	conf := `
+http {
+    upstream backend {
+        server app-1.default.svc:8080 weight=2;
+        server 10.0.0.1:8080;
+        server unix:/var/run/app.sock;
+    }
+    server {
+        listen 80;
+        location / {
+            proxy_pass http://backend;
+        }
+        location /api/ {
+            proxy_pass https://api.example.com:8443/v1/;
+        }
+        location /auth/ {
+            proxy_pass http://auth;
+        }
+        location /dynamic/ {
+            proxy_pass http://$host;
+        }
+        location /v6/ {
+            proxy_pass http://[::1]:8080;
+        }
+    }
+}
+`
+	want := []string{"api.example.com", "app-1.default.svc", "auth"}
+	if got := upstreamHosts(conf); !reflect.DeepEqual(got, want) {
+		t.Fatalf("upstreamHosts() = %v, want %v", got, want)
+	}
+}
+
+// stubResolver resolves the hosts it knows and answers NXDOMAIN for others
+type stubResolver struct {
+	hosts  map[string]bool
+	lookup []string
+}
+
+func (s *stubResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	s.lookup = append(s.lookup, host)
+	if host == "slow.example.com" {
+		return nil, &net.DNSError{Err: "i/o timeout", Name: host, IsTimeout: true}
+	}
+	if s.hosts[host] {
+		return []string{"10.0.0.1"}, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func TestUnresolvableUpstreams(t *testing.T) {
+	m := newTestNginxCluster("upstream-dns")
+	resolver := &stubResolver{hosts: map[string]bool{"app.default": true}}
+	conf := "proxy_pass http://app:8080;\nproxy_pass http://missing.example.com;\nproxy_pass http://slow.example.com;\n"
+
+	got := unresolvableUpstreams(context.Background(), resolver, m, conf)
+	if !reflect.DeepEqual(got, []string{"missing.example.com"}) {
+		t.Fatalf("unresolvableUpstreams() = %v, timeouts and resolvable hosts must not count", got)
+	}
+	// Single-label names are looked up in the namespace of the pods
+	if resolver.lookup[0] != "app.default" {
+		t.Errorf("expected app to be qualified with the namespace, looked up %v", resolver.lookup)
+	}
+
+	cond := unresolvableUpstreamsCondition(m, got)
+	if cond.Reason != "UnresolvableUpstreams" || !strings.Contains(cond.Message, "missing.example.com") {
+		t.Errorf("unexpected condition %+v", cond)
+	}
+}
+
+func TestHoldForUnresolvableUpstreamsDisabled(t *testing.T) {
+	resolver := &stubResolver{}
+	r := &NginxClusterReconciler{Scheme: testScheme, resolver: resolver}
+	held, err := r.holdForUnresolvableUpstreams(context.Background(), newTestNginxCluster("no-preflight"), "proxy_pass http://missing.example.com;\n")
+	if held || err != nil || len(resolver.lookup) > 0 {
+		t.Fatalf("preflight ran while disabled: %v, %v", held, err)
+	}
+}
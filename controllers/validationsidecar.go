@@ -0,0 +1,188 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+const (
+	validationSidecarContainerName = "config-validator"
+	// validationSidecarPort serves the result of the last nginx -t
+	validationSidecarPort     = 8087
+	validationSidecarPortName = "config-check"
+	validationResultPath      = "/result"
+	// validationSidecarInterval is how often, in seconds, the sidecar checks
+	// the config
+	validationSidecarInterval = "30"
+	// validationCheckTimeout bounds fetching the results of all pods, so
+	// unreachable pods don't hold up the reconcile
+	validationCheckTimeout = 2 * time.Second
+	// validationRecheckInterval is how often clusters with the sidecar are
+	// reconciled to fetch its results
+	validationRecheckInterval = time.Minute
+	// validationResultLimit bounds the nginx -t output read from a pod
+	validationResultLimit = 512
+)
+
+// validationSidecarScript writes "ok", or the output of nginx -t for conf
+// when it fails, to a result file, and serves the file with a second nginx
+// listening on validationSidecarPort
+func validationSidecarScript(conf string) string {
+	return `dir=/tmp/nginx-config-validator
+mkdir -p "$dir"
+echo pending > "$dir/result"
+printf '%s\n' "pid $dir/nginx.pid;" 'events {}' 'http {' '    access_log off;' '    server {' \
+  '        listen ` + strconv.Itoa(validationSidecarPort) + `;' \
+  "        location = ` + validationResultPath + ` { default_type text/plain; alias $dir/result; }" \
+  '    }' '}' > "$dir/nginx.conf"
+nginx -c "$dir/nginx.conf" -g "error_log stderr;"
+while true; do
+  if out=$(nginx -t -c ` + conf + ` 2>&1); then out=ok; fi
+  echo "$out" > "$dir/result.tmp" && mv "$dir/result.tmp" "$dir/result"
+  sleep ` + validationSidecarInterval + `
+done
+`
+}
+
+// addValidationSidecar adds the validation sidecar with the volume mounts of
+// the nginx container, so nginx -t sees the files nginx loads. It runs the
+// nginx image, already on the node.
+func addValidationSidecar(podSpec *corev1.PodSpec, m *nginxv1.NginxCluster) {
+	if !m.Spec.ValidationSidecar {
+		return
+	}
+	conf := "/etc/nginx/nginx.conf"
+	if reloadsConfig(m) {
+		conf = configReloaderDir + "/nginx.conf"
+	}
+	podSpec.Containers = append(podSpec.Containers, corev1.Container{
+		Name:    validationSidecarContainerName,
+		Image:   imageForNginxCluster(m),
+		Command: []string{"/bin/sh", "-c", validationSidecarScript(conf)},
+		Ports: []corev1.ContainerPort{{
+			ContainerPort: validationSidecarPort,
+			Name:          validationSidecarPortName,
+		}},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("5m"),
+				corev1.ResourceMemory: resource.MustParse("16Mi"),
+			},
+		},
+		VolumeMounts: append([]corev1.VolumeMount(nil), podSpec.Containers[0].VolumeMounts...),
+	})
+}
+
+// validationSidecarRunning reports whether the validation sidecar of pod runs
+func validationSidecarRunning(pod *corev1.Pod) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == validationSidecarContainerName {
+			return status.State.Running != nil
+		}
+	}
+	return false
+}
+
+// configValidCondition fetches the nginx -t results of the validation
+// sidecars of the pods. A failed check in any pod makes the config invalid.
+// Results that can't be fetched, as happens when the operator runs outside
+// the cluster network, or that are still pending leave it unknown.
+func (r *NginxClusterReconciler) configValidCondition(ctx context.Context, m *nginxv1.NginxCluster, pods []corev1.Pod) metav1.Condition {
+	cond := metav1.Condition{
+		Type:               nginxv1.ConditionConfigValid,
+		Status:             metav1.ConditionUnknown,
+		Reason:             "NoPods",
+		Message:            "No validation sidecar runs yet",
+		ObservedGeneration: m.Generation,
+	}
+	doer := r.httpClient
+	if doer == nil {
+		doer = http.DefaultClient
+	}
+	ctx, cancel := context.WithTimeout(ctx, validationCheckTimeout)
+	defer cancel()
+
+	passed := 0
+	var unknown string
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil || pod.Status.PodIP == "" || !validationSidecarRunning(pod) {
+			continue
+		}
+		result, err := fetchValidationResult(ctx, doer, pod.Status.PodIP)
+		switch {
+		case err != nil:
+			unknown = fmt.Sprintf("Failed to fetch the result of pod %s, the operator may not run in the cluster network: %v", pod.Name, err)
+		case result == "ok":
+			passed++
+		case result == "pending":
+			unknown = fmt.Sprintf("Pod %s has not checked its config yet", pod.Name)
+		default:
+			cond.Status = metav1.ConditionFalse
+			cond.Reason = "Invalid"
+			cond.Message = fmt.Sprintf("nginx -t failed in pod %s: %s", pod.Name, result)
+			return cond
+		}
+	}
+	switch {
+	case unknown != "":
+		cond.Reason = "CheckFailed"
+		cond.Message = unknown
+	case passed > 0:
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "Valid"
+		cond.Message = fmt.Sprintf("nginx -t passed in %d pods", passed)
+	}
+	return cond
+}
+
+// fetchValidationResult returns the result the validation sidecar at podIP
+// serves, cut to validationResultLimit bytes
+func fetchValidationResult(ctx context.Context, doer httpDoer, podIP string) (string, error) {
+	target := "http://" + net.JoinHostPort(podIP, strconv.Itoa(validationSidecarPort)) + validationResultPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := doer.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GET %s returned %d", target, resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, validationResultLimit))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(body)), nil
+}
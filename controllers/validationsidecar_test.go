@@ -0,0 +1,116 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// validationResultDoer serves results by pod IP. Unknown IPs time out.
+type validationResultDoer map[string]string
+
+func (d validationResultDoer) Do(req *http.Request) (*http.Response, error) {
+	result, ok := d[req.URL.Hostname()]
+	if !ok {
+		return nil, fmt.Errorf("dial %s: i/o timeout", req.URL.Host)
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(result + "\n"))}, nil
+}
+
+func TestDeploymentValidationSidecar(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("validation-sidecar")
+	m.Spec.ValidationSidecar = true
+	m.Spec.ErrorPages = map[string]string{"404": "<h1>Not here</h1>"}
+	spec := r.deploymentForNginxCluster(m, "hash").Spec.Template.Spec
+	if len(spec.Containers) != 2 {
+		t.Fatalf("expected the validation sidecar, got %d containers", len(spec.Containers))
+	}
+	sidecar := spec.Containers[1]
+	if sidecar.Name != validationSidecarContainerName || !strings.Contains(sidecar.Command[2], "nginx -t -c /etc/nginx/nginx.conf") {
+		t.Fatalf("unexpected sidecar %+v", sidecar)
+	}
+	if len(sidecar.VolumeMounts) != len(spec.Containers[0].VolumeMounts) {
+		t.Fatalf("sidecar mounts %v, want those of nginx %v", sidecar.VolumeMounts, spec.Containers[0].VolumeMounts)
+	}
+
+	// With the reloader, nginx loads the config from the mounted directory
+	m.Spec.ConfigReloaderSidecar = true
+	spec = r.deploymentForNginxCluster(m, "hash").Spec.Template.Spec
+	if sidecar := spec.Containers[len(spec.Containers)-1]; !strings.Contains(sidecar.Command[2], "nginx -t -c "+configReloaderDir+"/nginx.conf") {
+		t.Fatalf("sidecar doesn't check the reloaded config: %s", sidecar.Command[2])
+	}
+}
+
+func TestConfigValidCondition(t *testing.T) {
+	m := newTestNginxCluster("validation-sidecar")
+	pod := func(name, ip string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.PodStatus{
+				PodIP: ip,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  validationSidecarContainerName,
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				}},
+			},
+		}
+	}
+	doer := validationResultDoer{
+		"10.0.0.1": "ok",
+		"10.0.0.2": "ok",
+		"10.0.0.3": `nginx: [emerg] unknown directive "serverr" in /etc/nginx/nginx.conf:3`,
+		"10.0.0.4": "pending",
+	}
+	r := &NginxClusterReconciler{httpClient: doer}
+
+	for _, tc := range []struct {
+		pods   []corev1.Pod
+		status metav1.ConditionStatus
+		reason string
+	}{
+		{nil, metav1.ConditionUnknown, "NoPods"},
+		{[]corev1.Pod{pod("a", "10.0.0.1"), pod("b", "10.0.0.2")}, metav1.ConditionTrue, "Valid"},
+		{[]corev1.Pod{pod("a", "10.0.0.1"), pod("c", "10.0.0.3")}, metav1.ConditionFalse, "Invalid"},
+		{[]corev1.Pod{pod("a", "10.0.0.1"), pod("d", "10.0.0.4")}, metav1.ConditionUnknown, "CheckFailed"},
+		{[]corev1.Pod{pod("e", "10.0.0.5"), pod("c", "10.0.0.3")}, metav1.ConditionFalse, "Invalid"},
+		{[]corev1.Pod{pod("e", "10.0.0.5")}, metav1.ConditionUnknown, "CheckFailed"},
+	} {
+		cond := r.configValidCondition(context.Background(), m, tc.pods)
+		if cond.Type != nginxv1.ConditionConfigValid || cond.Status != tc.status || cond.Reason != tc.reason {
+			t.Errorf("configValidCondition() = %s/%s, want %s/%s: %s", cond.Status, cond.Reason, tc.status, tc.reason, cond.Message)
+		}
+	}
+
+	cond := r.configValidCondition(context.Background(), m, []corev1.Pod{pod("c", "10.0.0.3")})
+	if !strings.Contains(cond.Message, `pod c: nginx: [emerg] unknown directive "serverr"`) {
+		t.Fatalf("nginx -t output missing from %q", cond.Message)
+	}
+	if m.Spec.ValidationSidecar = true; isConverged(m) {
+		t.Fatalf("clusters with the validation sidecar are reconciled every time")
+	}
+}
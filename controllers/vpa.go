@@ -0,0 +1,161 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/json"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+// vpaGVK is the VerticalPodAutoscaler kind. Like Routes, VPAs are handled as
+// unstructured objects since the CRD is optional.
+var vpaGVK = schema.GroupVersionKind{Group: "autoscaling.k8s.io", Version: "v1", Kind: "VerticalPodAutoscaler"}
+
+// vpaUpdateModeOff only computes recommendations
+const vpaUpdateModeOff = "Off"
+
+// newVPA returns an empty unstructured VerticalPodAutoscaler
+func newVPA() *unstructured.Unstructured {
+	vpa := &unstructured.Unstructured{}
+	vpa.SetGroupVersionKind(vpaGVK)
+	return vpa
+}
+
+// vpaUpdateMode returns the update mode of the VPA requested by m
+func vpaUpdateMode(m *nginxv1.NginxCluster) string {
+	if m.Spec.VPA == nil || m.Spec.VPA.UpdateMode == "" {
+		return vpaUpdateModeOff
+	}
+	return m.Spec.VPA.UpdateMode
+}
+
+// vpaManagesResources reports whether a VPA applies its recommendations to
+// the pods of m, so pod resources are not the ones of the pod template
+func vpaManagesResources(m *nginxv1.NginxCluster) bool {
+	return m.Spec.VPA != nil && vpaUpdateMode(m) != vpaUpdateModeOff
+}
+
+// reconcileVPA creates or updates the VerticalPodAutoscaler when it is
+// requested in the spec, and removes a previously created one otherwise.
+// Clusters without the VPA CRD are skipped.
+func (r *NginxClusterReconciler) reconcileVPA(ctx context.Context, m *nginxv1.NginxCluster) error {
+	logger := log.FromContext(ctx)
+
+	available, err := apiAvailable(r.RESTMapper(), vpaGVK)
+	if err != nil {
+		return err
+	}
+	if !available {
+		if m.Spec.VPA != nil {
+			logger.Info("VPA API autoscaling.k8s.io/v1 not available, skipping VerticalPodAutoscaler")
+		}
+		return nil
+	}
+
+	vpa := newVPA()
+	err = r.Get(ctx, types.NamespacedName{Name: m.Name, Namespace: workloadNamespace(m)}, vpa)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+
+	if m.Spec.VPA == nil {
+		if exists && r.isOwnedBy(vpa, m) {
+			logger.Info("Deleting VerticalPodAutoscaler", "VerticalPodAutoscaler.Namespace", vpa.GetNamespace(), "VerticalPodAutoscaler.Name", vpa.GetName())
+			if err := r.Delete(ctx, vpa); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+		return nil
+	}
+
+	desired, err := r.vpaForNginxCluster(m)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		logger.Info("Creating a new VerticalPodAutoscaler", "VerticalPodAutoscaler.Namespace", desired.GetNamespace(), "VerticalPodAutoscaler.Name", desired.GetName())
+		return r.createObject(ctx, desired)
+	}
+
+	if syncVPASpec(vpa.DeepCopy(), desired) {
+		logger.Info("Updating VerticalPodAutoscaler", "VerticalPodAutoscaler.Namespace", vpa.GetNamespace(), "VerticalPodAutoscaler.Name", vpa.GetName())
+		return r.updateObject(ctx, vpa, desired, func() {
+			syncVPASpec(vpa, desired)
+		})
+	}
+	return nil
+}
+
+// vpaForNginxCluster returns a VerticalPodAutoscaler targeting the cluster's
+// Deployment
+func (r *NginxClusterReconciler) vpaForNginxCluster(m *nginxv1.NginxCluster) (*unstructured.Unstructured, error) {
+	spec := map[string]interface{}{
+		"targetRef": map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"name":       m.Name,
+		},
+		"updatePolicy": map[string]interface{}{
+			"updateMode": vpaUpdateMode(m),
+		},
+	}
+	if policy := m.Spec.VPA.ResourcePolicy; policy != nil && len(policy.Raw) > 0 {
+		// Decoded like objects read from the API server, so integers compare
+		// equal when syncing
+		var resourcePolicy map[string]interface{}
+		if err := json.Unmarshal(policy.Raw, &resourcePolicy); err != nil {
+			return nil, err
+		}
+		spec["resourcePolicy"] = resourcePolicy
+	}
+
+	vpa := newVPA()
+	vpa.SetName(m.Name)
+	vpa.SetNamespace(workloadNamespace(m))
+	vpa.Object["spec"] = spec
+	r.setOwner(m, vpa)
+	return vpa, nil
+}
+
+// syncVPASpec copies the fields the operator manages from desired onto
+// existing and reports whether anything changed
+func syncVPASpec(existing, desired *unstructured.Unstructured) bool {
+	changed := false
+	for _, field := range []string{"targetRef", "updatePolicy", "resourcePolicy"} {
+		want, wantFound, _ := unstructured.NestedFieldCopy(desired.Object, "spec", field)
+		got, found, _ := unstructured.NestedFieldNoCopy(existing.Object, "spec", field)
+		switch {
+		case !wantFound && found:
+			unstructured.RemoveNestedField(existing.Object, "spec", field)
+			changed = true
+		case wantFound && !equality.Semantic.DeepEqual(got, want):
+			_ = unstructured.SetNestedField(existing.Object, want, "spec", field)
+			changed = true
+		}
+	}
+	return changed
+}
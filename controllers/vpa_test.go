@@ -0,0 +1,106 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	nginxv1 "github.com/example/nginx-operator/api/v1"
+)
+
+func TestVPAForNginxCluster(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("vpa")
+	m.UID = "vpa-uid"
+	m.Spec.VPA = &nginxv1.VPASpec{
+		ResourcePolicy: &runtime.RawExtension{Raw: []byte(`{"containerPolicies":[{"containerName":"nginx","minAllowed":{"cpu":"50m","memory":64}}]}`)},
+	}
+
+	vpa, err := r.vpaForNginxCluster(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpa.GetKind() != "VerticalPodAutoscaler" || vpa.GetAPIVersion() != "autoscaling.k8s.io/v1" {
+		t.Fatalf("unexpected kind %s %s", vpa.GetAPIVersion(), vpa.GetKind())
+	}
+	for path, want := range map[[3]string]string{
+		{"spec", "targetRef", "kind"}:          "Deployment",
+		{"spec", "targetRef", "name"}:          m.Name,
+		{"spec", "updatePolicy", "updateMode"}: "Off",
+	} {
+		if got, _, _ := unstructured.NestedString(vpa.Object, path[:]...); got != want {
+			t.Errorf("%v = %q, want %q", path, got, want)
+		}
+	}
+	policies, _, _ := unstructured.NestedSlice(vpa.Object, "spec", "resourcePolicy", "containerPolicies")
+	if len(policies) != 1 {
+		t.Fatalf("unexpected container policies %v", policies)
+	}
+	if memory := policies[0].(map[string]interface{})["minAllowed"].(map[string]interface{})["memory"]; memory != int64(64) {
+		t.Errorf("expected integers to decode as int64, got %T", memory)
+	}
+	if err := checkControllerOwner(vpa, m); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSyncVPASpec(t *testing.T) {
+	r := &NginxClusterReconciler{Scheme: testScheme}
+	m := newTestNginxCluster("vpa-sync")
+	m.Spec.VPA = &nginxv1.VPASpec{
+		ResourcePolicy: &runtime.RawExtension{Raw: []byte(`{"containerPolicies":[{"containerName":"*","mode":"Off"}]}`)},
+	}
+
+	existing, err := r.vpaForNginxCluster(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	desired, _ := r.vpaForNginxCluster(m)
+	if syncVPASpec(existing, desired) {
+		t.Fatalf("identical VPA counted as drift")
+	}
+
+	m.Spec.VPA = &nginxv1.VPASpec{UpdateMode: "Auto"}
+	desired, _ = r.vpaForNginxCluster(m)
+	if !syncVPASpec(existing, desired) {
+		t.Fatalf("expected the update mode and resource policy changes to be synced")
+	}
+	if mode, _, _ := unstructured.NestedString(existing.Object, "spec", "updatePolicy", "updateMode"); mode != "Auto" {
+		t.Errorf("unexpected update mode %q", mode)
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(existing.Object, "spec", "resourcePolicy"); found {
+		t.Errorf("expected the resource policy to be removed")
+	}
+}
+
+func TestVPAManagesResources(t *testing.T) {
+	m := newTestNginxCluster("vpa-resources")
+	if vpaManagesResources(m) {
+		t.Fatalf("expected no VPA without spec.vpa")
+	}
+	m.Spec.VPA = &nginxv1.VPASpec{}
+	if vpaManagesResources(m) {
+		t.Fatalf("expected the default Off mode to only recommend")
+	}
+	m.Spec.VPA.UpdateMode = "Auto"
+	if !vpaManagesResources(m) {
+		t.Fatalf("expected Auto mode to manage pod resources")
+	}
+}
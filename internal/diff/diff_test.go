@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"testing"
+)
+
+func TestUnifiedEqual(t *testing.T) {
+	if d := Unified("a", "b", "events {}\n", "events {}\n", 3); d != "" {
+		t.Fatalf("expected no diff for equal texts, got %q", d)
+	}
+}
+
+func TestUnified(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n"
+	want := `--- old
++++ new
+@@ -2,3 +2,3 @@
+ 2
+-3
++three
+ 4
+@@ -10,1 +10,2 @@
+ 10
++11
+`
+	if d := Unified("old", "new", a, b, 1); d != want {
+		t.Fatalf("Unified() =\n%s\nwant\n%s", d, want)
+	}
+
+	// Changes closer than twice the context share a hunk
+	want = `--- old
++++ new
+@@ -1,10 +1,11 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+ 6
+ 7
+ 8
+ 9
+ 10
++11
+`
+	if d := Unified("old", "new", a, b, 4); d != want {
+		t.Fatalf("Unified() =\n%s\nwant\n%s", d, want)
+	}
+}
+
+func TestUnifiedFromEmpty(t *testing.T) {
+	want := `--- old
++++ new
+@@ -0,0 +1,2 @@
++events {}
++http {}
+`
+	if d := Unified("old", "new", "", "events {}\nhttp {}\n", 3); d != want {
+		t.Fatalf("Unified() =\n%s\nwant\n%s", d, want)
+	}
+}
@@ -18,7 +18,10 @@ package main
 
 import (
 	"flag"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -26,10 +29,13 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	nginxv1 "github.com/example/nginx-operator/api/v1"
 	"github.com/example/nginx-operator/controllers"
@@ -52,11 +58,68 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var disableOwnerReferences bool
+	var disableBlockOwnerDeletion bool
+	var configPropagationDelay time.Duration
+	var rolloutPollInterval time.Duration
+	var watchNamespaces, excludeNamespaces string
+	var useServerSideApply bool
+	var upstreamDNSPreflight bool
+	var maxConcurrentRollouts int
+	var serviceReachabilityCheck bool
+	var skipSelectorOverlapCheck bool
+	var reconcileHistorySize int
+	var lockConfigMap string
+	var reconcileSinkURL string
+	var kubeAPIQPS float64
+	var kubeAPIBurst int
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.BoolVar(&disableOwnerReferences, "disable-owner-references", false,
+		"Tag managed resources with labels instead of owner references and clean them up in the finalizer. "+
+			"Use this when the resources live in a different cluster than the NginxCluster.")
+	flag.BoolVar(&disableBlockOwnerDeletion, "disable-block-owner-deletion", false,
+		"Set blockOwnerDeletion to false on the owner references of managed resources, "+
+			"so foreground deletion of a NginxCluster doesn't wait for them to be garbage collected.")
+	flag.DurationVar(&configPropagationDelay, "config-propagation-delay", controllers.DefaultConfigPropagationDelay,
+		"How long to wait after updating the generated ConfigMap before restarting the pods, "+
+			"so the kubelet serves the new content. 0 restarts them right away.")
+	flag.DurationVar(&rolloutPollInterval, "rollout-poll-interval", controllers.DefaultRolloutPollInterval,
+		"How often a cluster is reconciled while its Deployment rolls out, "+
+			"so its status follows the rollout without waiting for Deployment events.")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "",
+		"Comma-separated namespaces whose NginxClusters are managed. All namespaces when empty.")
+	flag.StringVar(&excludeNamespaces, "exclude-namespaces", "",
+		"Comma-separated namespaces whose NginxClusters are ignored, e.g. because another operator instance manages them.")
+	flag.BoolVar(&useServerSideApply, "server-side-apply", false,
+		"Write the managed resources with server-side apply as the nginx-operator field manager, "+
+			"so fields set by other controllers are left alone.")
+	flag.BoolVar(&upstreamDNSPreflight, "upstream-dns-preflight", false,
+		"Look up the upstream hosts of the nginx config before rolling it out, "+
+			"and hold back configs with hosts that don't resolve instead of letting nginx crash-loop.")
+	flag.IntVar(&maxConcurrentRollouts, "max-concurrent-rollouts", 0,
+		"Maximum number of NginxClusters rolling out at the same time; config rollouts beyond it wait for a slot. 0 is unlimited.")
+	flag.BoolVar(&serviceReachabilityCheck, "service-reachability-check", false,
+		"Send a GET through the cluster IP of each NginxCluster's Service and report the result in the ServiceReachable condition. "+
+			"Requires the operator to run in the cluster network.")
+	flag.BoolVar(&skipSelectorOverlapCheck, "skip-selector-overlap-check", false,
+		"Skip looking for pods of other workloads that match the Service selector of a NginxCluster, "+
+			"reported in the SelectorOverlap condition, e.g. in namespaces with many pods.")
+	flag.StringVar(&lockConfigMap, "lock-configmap", controllers.DefaultLockConfigMap,
+		"ConfigMap, as name in the operator's namespace or namespace/name, that pauses the reconciliation of all NginxClusters "+
+			"while it has paused: \"true\". Empty disables the lock.")
+	flag.IntVar(&reconcileHistorySize, "reconcile-history-size", 0,
+		"Number of recent reconciles kept in memory per NginxCluster and served as JSON at /reconciles on the metrics endpoint. 0 disables the history.")
+	flag.StringVar(&reconcileSinkURL, "reconcile-sink-url", "",
+		"URL the summary of every reconcile is posted to as JSON, e.g. for an audit pipeline. Posts are sent in the background "+
+			"and dropped when the endpoint falls behind. Disabled when empty.")
+	flag.Float64Var(&kubeAPIQPS, "kube-api-qps", controllers.DefaultClientQPS,
+		"Maximum requests per second the operator sends to the API server. -1 disables client-side rate limiting, 0 is rejected.")
+	flag.IntVar(&kubeAPIBurst, "kube-api-burst", controllers.DefaultClientBurst,
+		"Maximum burst of requests to the API server, at least --kube-api-qps.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -65,10 +128,45 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme:                 scheme,
-		MetricsBindAddress:     metricsAddr,
-		Port:                   9443,
+	restConfig, err := controllers.WithClientRateLimits(ctrl.GetConfigOrDie(), float32(kubeAPIQPS), kubeAPIBurst)
+	if err != nil {
+		setupLog.Error(err, "invalid API server rate limits")
+		os.Exit(1)
+	}
+	lock, err := controllers.ParseLockConfigMap(lockConfigMap)
+	if err != nil {
+		setupLog.Error(err, "lock ConfigMap disabled")
+	}
+
+	// The reconciler is created before the manager, whose metrics server
+	// serves its history, and gets the manager's client once it exists
+	reconciler := &controllers.NginxClusterReconciler{
+		DisableOwnerReferences:    disableOwnerReferences,
+		DisableBlockOwnerDeletion: disableBlockOwnerDeletion,
+		ConfigPropagationDelay:    configPropagationDelay,
+		RolloutPollInterval:       rolloutPollInterval,
+		WatchNamespaces:           splitList(watchNamespaces),
+		ExcludeNamespaces:         splitList(excludeNamespaces),
+		UseServerSideApply:        useServerSideApply,
+		UpstreamDNSPreflight:      upstreamDNSPreflight,
+		MaxConcurrentRollouts:     maxConcurrentRollouts,
+		ServiceReachabilityCheck:  serviceReachabilityCheck,
+		SkipSelectorOverlapCheck:  skipSelectorOverlapCheck,
+		ReconcileHistorySize:      reconcileHistorySize,
+		LockConfigMap:             lock,
+	}
+	metricsHandlers := map[string]http.Handler{}
+	if reconcileHistorySize > 0 {
+		metricsHandlers["/reconciles"] = reconciler.ReconcileHistoryHandler()
+	}
+
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
+		Scheme: scheme,
+		Metrics: metricsserver.Options{
+			BindAddress:   metricsAddr,
+			ExtraHandlers: metricsHandlers,
+		},
+		WebhookServer:          webhook.NewServer(webhook.Options{Port: 9443}),
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "nginx-operator.example.com",
@@ -78,13 +176,40 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err = (&controllers.NginxClusterReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+	// gRPC health checks fall back to TCP probes on clusters older than 1.24
+	grpcProbes := true
+	if dc, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig()); err != nil {
+		setupLog.Error(err, "unable to create discovery client")
+	} else if info, err := dc.ServerVersion(); err != nil {
+		setupLog.Error(err, "unable to get server version")
+	} else {
+		grpcProbes = controllers.GRPCProbesSupported(info)
+	}
+
+	reconciler.Client = mgr.GetClient()
+	reconciler.Scheme = mgr.GetScheme()
+	reconciler.Recorder = mgr.GetEventRecorderFor("nginxcluster-controller")
+	reconciler.DisableGRPCProbes = !grpcProbes
+	if reconcileSinkURL != "" {
+		sink := controllers.NewHTTPReconcileSink(reconcileSinkURL)
+		if err := mgr.Add(sink); err != nil {
+			setupLog.Error(err, "unable to set up reconcile sink")
+			os.Exit(1)
+		}
+		reconciler.ReconcileSink = sink
+	}
+	if err = reconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "NginxCluster")
 		os.Exit(1)
 	}
+	// The webhook server needs serving certificates, so it is only started
+	// when the webhook overlay (which provisions them) sets ENABLE_WEBHOOKS.
+	if os.Getenv("ENABLE_WEBHOOKS") == "true" {
+		if err = (&nginxv1.NginxCluster{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "NginxCluster")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -96,9 +221,20 @@ func main() {
 		os.Exit(1)
 	}
 
-	setupLog.Info("starting manager")
+	setupLog.Info("starting manager", "version", controllers.Version)
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// splitList splits a comma-separated flag value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}